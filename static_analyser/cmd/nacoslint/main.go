@@ -0,0 +1,17 @@
+package main
+
+import (
+	"static_analyser/pkg/analyzer"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	// main runs the Nacos usage analyzer on the packages named on the command line, or as a vet tool when invoked by
+	// "go vet -vettool".
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	singlechecker.Main(analyzer.Analyzer)
+}
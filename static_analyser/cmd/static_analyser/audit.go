@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	f_util "static_analyser/pkg/fileUtils"
+	"static_analyser/pkg/graph"
+	"static_analyser/pkg/policy"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func findNetworkPolicies(root string) ([]t.NetworkPolicy, error) {
+	// findNetworkPolicies finds the NetworkPolicies checked into a source tree.
+	//
+	// root: The root directory for the search.
+	//
+	// Returns:
+	// The NetworkPolicies of the .yaml, .yml and .json files under root. Files that are not valid YAML, such as templates, are skipped.
+	// An error if there was a problem walking the file tree.
+
+	policies := []t.NetworkPolicy{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			return nil
+		}
+		found, err := f_util.ReadNetworkPolicies([]string{path})
+		if err != nil {
+			fmt.Fprintf(logOut, "Skipping %s: %v\n", path, err)
+			return nil
+		}
+		policies = append(policies, found...)
+		return nil
+	})
+	return policies, err
+}
+
+func runAudit(args []string) error {
+	// runAudit implements the "audit" subcommand, which audits the NetworkPolicies already written for the applications
+	// against what their code needs.
+	//
+	// args: The command line arguments following the subcommand name. After the flags come the files or directories
+	// holding the NetworkPolicies; without any, the policies are searched for under the root directory.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the analysis failed, the policies could not be read,
+	// or a namespace scores below the minimum score.
+
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	minScore := flags.Int("min-score", 0, "fail if a namespace scores below this percentage")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	parsedYamls, manifests, err := analyse(*rootDir)
+	if err != nil {
+		return err
+	}
+
+	var policies []t.NetworkPolicy
+	if flags.NArg() == 0 {
+		policies, err = findNetworkPolicies(*rootDir)
+	} else {
+		policies, err = f_util.ReadNetworkPolicies(flags.Args())
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logOut, "Auditing %d NetworkPolicies\n\n", len(policies))
+
+	report := policy.AuditPolicies(parsedYamls, findKubernetesNamespaces(*rootDir), policies, graph.BuildServiceGraph(parsedYamls, manifests))
+	if *asJSON {
+		jsonData, err := json.MarshalIndent(report, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit report: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		fmt.Print(policy.RenderAuditReport(report))
+	}
+
+	for _, score := range report.Namespaces {
+		if score.Score < *minScore {
+			return fmt.Errorf("namespace %s scores %d%%, below %d%%", score.Namespace, score.Score, *minScore)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindNetworkPolicies(test *testing.T) {
+	// TestFindNetworkPolicies checks that the NetworkPolicies of a source tree are found among its other files, and that
+	// documents of other kinds, templates that are not valid YAML and files of other types are skipped.
+	//
+	// test: The test.
+
+	root := test.TempDir()
+	files := map[string]string{
+		"orders/orders.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: orders\n" +
+			"---\napiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: orders-egress\n  namespace: shop\nspec:\n  podSelector:\n    matchLabels:\n      app: orders\n  policyTypes: [Egress]\n",
+		"policies/default-deny.yml": "apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: default-deny\nspec:\n  podSelector: {}\n  policyTypes: [Ingress]\n",
+		"policies/list.json":        `{"apiVersion": "v1", "kind": "List", "items": [{"apiVersion": "networking.k8s.io/v1", "kind": "NetworkPolicy", "metadata": {"name": "payments-ingress"}}, {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings"}}]}`,
+		"charts/templates/np.yaml":  "kind: NetworkPolicy\nmetadata:\n  name: {{ .Values.name }\n",
+		"orders/config.yaml":        "server:\n  port: 8080\n",
+		"orders/policy.txt":         "apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: ignored\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			test.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			test.Fatal(err)
+		}
+	}
+
+	policies, err := findNetworkPolicies(root)
+	if err != nil {
+		test.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, policy := range policies {
+		got[policy.Metadata.Name] = true
+	}
+	want := map[string]bool{"orders-egress": true, "default-deny": true, "payments-ingress": true}
+	if len(policies) != len(want) || len(got) != len(want) {
+		test.Fatalf("got policies %+v, want %v", policies, want)
+	}
+	for name := range want {
+		if !got[name] {
+			test.Errorf("got policies %v, want %s among them", got, name)
+		}
+	}
+}
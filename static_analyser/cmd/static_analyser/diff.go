@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	diag "static_analyser/pkg/diagnostics"
+	"static_analyser/pkg/diff"
+	f_util "static_analyser/pkg/fileUtils"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func analyseRevision(rootDir string, revision string) (map[string]*t.Yaml2Go, map[string]t.TCPManifest, []t.Diagnostic, error) {
+	// analyseRevision analyses the input tree as it was at a git revision, with a diagnostics collector of its own.
+	//
+	// rootDir: The root directory of the applications, inside a git working tree.
+	// revision: The git revision to analyse.
+	//
+	// Returns:
+	// The parsed YAML files and the TCPManifests of the revision, keyed by application.
+	// The diagnostics of the revision, located under rootDir rather than the temporary directory it was extracted to.
+	// An error if the revision could not be extracted or analysed.
+
+	revisionRoot, tmpDir, err := f_util.ExtractGitRevision(rootDir, revision)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	collector := diagnosticsCollector
+	diagnosticsCollector = diag.NewCollector()
+	defer func() { diagnosticsCollector = collector }()
+
+	parsedYamls, manifests, err := analyse(revisionRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	diagnostics := diagnosticsCollector.Diagnostics()
+	for i, diagnostic := range diagnostics {
+		if relative, err := filepath.Rel(revisionRoot, diagnostic.Location); err == nil && !strings.HasPrefix(relative, "..") {
+			diagnostics[i].Location = filepath.Join(rootDir, relative)
+		}
+	}
+	return parsedYamls, manifests, diagnostics, nil
+}
+
+func runDiff(args []string) error {
+	// runDiff implements the "diff" subcommand, which reports how the allowed communication changes between two revisions.
+	//
+	// args: The command line arguments following the subcommand name. After the flags come the old and the new revision,
+	// either as two directories of manifests written by the analyser, or, with -git, as two git revisions of -root.
+	//
+	// Returns:
+	// An error if the arguments are invalid or either revision could not be loaded.
+
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications, used with -git")
+	git := flags.Bool("git", false, "treat the arguments as git revisions of -root instead of manifest directories")
+	format := flags.String("format", "text", "output format: text, markdown or json")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("usage: diff [flags] <old> <new>")
+	}
+
+	var oldYamls, newYamls map[string]*t.Yaml2Go
+	var oldManifests, newManifests map[string]t.TCPManifest
+	var err error
+	if *git {
+		// Only the diagnostics of the new revision are reported, so that problems already fixed cannot fail the run
+		if oldYamls, oldManifests, _, err = analyseRevision(*rootDir, flags.Arg(0)); err != nil {
+			return err
+		}
+		var diagnostics []t.Diagnostic
+		if newYamls, newManifests, diagnostics, err = analyseRevision(*rootDir, flags.Arg(1)); err != nil {
+			return err
+		}
+		diagnosticsCollector.Add(diagnostics...)
+	} else {
+		if oldManifests, err = f_util.ReadTCPManifestsFromJSON(flags.Arg(0)); err != nil {
+			return err
+		}
+		if newManifests, err = f_util.ReadTCPManifestsFromJSON(flags.Arg(1)); err != nil {
+			return err
+		}
+	}
+
+	result := diff.DiffManifests(oldYamls, oldManifests, newYamls, newManifests)
+
+	switch *format {
+	case "text", "markdown":
+		fmt.Print(diff.RenderManifestDiff(result, *format == "markdown"))
+	case "json":
+		jsonData, err := json.MarshalIndent(result, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diff: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	default:
+		return fmt.Errorf("unknown diff format %q", *format)
+	}
+	return nil
+}
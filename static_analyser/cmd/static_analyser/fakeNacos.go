@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"static_analyser/pkg/fake_nacos"
+	f_util "static_analyser/pkg/fileUtils"
+	"strings"
+	"syscall"
+)
+
+func runFakeNacos(args []string) error {
+	// runFakeNacos implements the "fake-nacos" subcommand, which serves an in-memory Nacos server until interrupted,
+	// so that the analysed services can be run locally and their actual registrations inspected.
+	//
+	// args: The command line arguments following the subcommand name.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the config snapshot could not be read, the server failed,
+	// or the state of the server could not be written on shutdown.
+
+	flags := flag.NewFlagSet("fake-nacos", flag.ContinueOnError)
+	addr := flags.String("addr", ":8848", "address to listen on")
+	dump := flags.String("dump", "", "file to write the registered instances and configurations to on shutdown")
+	flags.StringVar(&configSnapshot, "config-snapshot", configSnapshot, "directory holding a local snapshot of the Nacos configurations to publish on start")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server := fake_nacos.NewServer()
+	if configSnapshot != "" {
+		configs, err := f_util.ReadConfigSnapshot(configSnapshot)
+		if err != nil {
+			return err
+		}
+		for key, content := range configs {
+			dataId, group, _ := strings.Cut(key, "@@")
+			server.PublishConfig(dataId, group, "", content)
+		}
+	}
+
+	httpServer := &http.Server{Addr: *addr, Handler: server}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+	fmt.Fprintf(logOut, "Fake Nacos server listening on %s\n", *addr)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-errs:
+		return fmt.Errorf("fake Nacos server failed: %w", err)
+	case <-interrupt:
+		httpServer.Close()
+	}
+
+	if *dump != "" {
+		snapshot := server.Snapshot()
+		jsonData, err := json.MarshalIndent(snapshot, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+		if err := os.WriteFile(*dump, jsonData, 0644); err != nil {
+			return fmt.Errorf("failed to write snapshot '%s': %w", *dump, err)
+		}
+		fmt.Fprintf(logOut, "Wrote %d instances and %d configurations to %s\n", len(snapshot.Instances), len(snapshot.Configs), *dump)
+	}
+	return nil
+}
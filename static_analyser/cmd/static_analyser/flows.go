@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	diag "static_analyser/pkg/diagnostics"
+	f_util "static_analyser/pkg/fileUtils"
+	"static_analyser/pkg/flows"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+)
+
+func mergeObservedFlows(manifests map[string]t.TCPManifest, observed []t.ObservedFlow) {
+	// mergeObservedFlows adds the observed flows opened by the analysed applications to their TCPManifests as requests with source "observed".
+	// Flows to other analysed applications name them; other flows keep only the destination IP address. Flows to a workload
+	// that was not analysed and has no single IP address, such as one whose flows went to several pods, have no target
+	// a request can name, so they are left out and reported as diagnostics (SA012).
+	//
+	// manifests: A map where the keys are the names of the applications and the values are the corresponding TCPManifests. It is updated in place.
+	// observed: The flows to add.
+
+	for _, flow := range observed {
+		manifest, ok := manifests[flow.Source.Workload]
+		if !ok {
+			continue
+		}
+		req := t.TCPRequest{Type: flow.Protocol, URL: flow.Destination.IP, Port: flow.Port, Source: "observed"}
+		if _, ok := manifests[flow.Destination.Workload]; ok {
+			req.URL, req.Name = "", flow.Destination.Workload
+		} else if req.URL == "" {
+			message := fmt.Sprintf("observed flow to %s/%s on port %s is not merged, as %s was not analysed and its pods have several IP addresses", flow.Destination.Namespace, flow.Destination.Workload, flow.Port, flow.Destination.Workload)
+			diagnosticsCollector.Add(t.Diagnostic{Code: diag.UnmergedFlow, Severity: "warning", Location: flow.Source.Workload, Message: message, Service: flow.Source.Workload})
+			continue
+		}
+		manifest.Requests = append(manifest.Requests, req)
+		manifests[flow.Source.Workload] = manifest
+	}
+}
+
+func runFlows(args []string) error {
+	// runFlows implements the "flows" subcommand, which compares network flows observed at runtime with the service graph.
+	//
+	// args: The command line arguments following the subcommand name. After the flags come the flow log files.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the analysis failed, or a flow log or the inventory could not be read.
+
+	flags := flag.NewFlagSet("flows", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	format := flags.String("format", "auto", "flow log format: auto, hubble, calico, vpc or conntrack")
+	inventoryFile := flags.String("inventory", "", `output of "kubectl get pods,services -A -o json", used to map IP addresses to workloads`)
+	merge := flags.Bool("merge", false, "write the manifests with the observed but not predicted flows added as requests with source \"observed\"")
+	output := flags.String("o", outputPrefix, "prefix of the manifest files written with -merge")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: flows [flags] <flow log>...")
+	}
+
+	var inventory map[string]t.FlowEndpoint
+	if *inventoryFile != "" {
+		var err error
+		if inventory, err = f_util.ReadKubernetesInventory(*inventoryFile); err != nil {
+			return err
+		}
+	}
+
+	observed := []t.ObservedFlow{}
+	for _, path := range flags.Args() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read flow log '%s': %w", path, err)
+		}
+		parsed, err := flows.ParseFlowLog(string(content), *format)
+		if err != nil {
+			return fmt.Errorf("failed to parse flow log '%s': %w", path, err)
+		}
+		observed = append(observed, parsed...)
+	}
+
+	parsedYamls, manifests, err := analyse(*rootDir)
+	if err != nil {
+		return err
+	}
+	report := flows.CompareFlows(graph.BuildServiceGraph(parsedYamls, manifests), flows.ResolveFlows(observed, inventory))
+
+	if *merge {
+		mergeObservedFlows(manifests, report.ObservedNotPredicted)
+		for application, manifest := range manifests {
+			f_util.WriteTCPManifestToJSON(manifest, application, *output)
+		}
+	}
+
+	if *asJSON {
+		jsonData, err := json.MarshalIndent(report, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal flow report: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+	fmt.Print(flows.RenderFlowReport(report))
+	return nil
+}
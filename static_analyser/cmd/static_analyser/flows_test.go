@@ -0,0 +1,61 @@
+package main
+
+import (
+	diag "static_analyser/pkg/diagnostics"
+	"static_analyser/pkg/flows"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestMergeObservedFlows(test *testing.T) {
+	// TestMergeObservedFlows checks that observed flows to workloads that were not analysed keep the IP address of their
+	// single pod, and are left out with a diagnostic if their pods have several.
+	//
+	// test: The test.
+
+	diagnosticsCollector = diag.NewCollector()
+	defer func() { diagnosticsCollector = diag.NewCollector() }()
+
+	inventory := map[string]t.FlowEndpoint{
+		"10.0.0.1": {Workload: "orders", Namespace: "shop", Pod: "orders-1"},
+		"10.0.0.2": {Workload: "payments", Namespace: "shop", Pod: "payments-1"},
+		"10.0.0.3": {Workload: "ledger", Namespace: "finance", Pod: "ledger-1"},
+		"10.0.0.4": {Workload: "audit", Namespace: "finance", Pod: "audit-1"},
+		"10.0.0.5": {Workload: "audit", Namespace: "finance", Pod: "audit-2"},
+	}
+	flow := func(destination string, port string) t.ObservedFlow {
+		return t.ObservedFlow{Source: t.FlowEndpoint{IP: "10.0.0.1"}, Destination: t.FlowEndpoint{IP: destination}, Port: port, Protocol: "tcp", Count: 1}
+	}
+	observed := flows.ResolveFlows([]t.ObservedFlow{
+		flow("10.0.0.2", "8080"),
+		flow("10.0.0.3", "5432"),
+		flow("10.0.0.3", "5432"),
+		flow("10.0.0.4", "9000"),
+		flow("10.0.0.5", "9000"),
+	}, inventory)
+
+	manifests := map[string]t.TCPManifest{
+		"orders":   {Service: "orders"},
+		"payments": {Service: "payments"},
+	}
+	mergeObservedFlows(manifests, observed)
+
+	want := []t.TCPRequest{
+		{Type: "tcp", URL: "10.0.0.3", Port: "5432", Source: "observed"},
+		{Type: "tcp", Name: "payments", Port: "8080", Source: "observed"},
+	}
+	got := manifests["orders"].Requests
+	if len(got) != len(want) {
+		test.Fatalf("got requests %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			test.Errorf("got request %+v, want %+v", got[i], want[i])
+		}
+	}
+
+	diagnostics := diagnosticsCollector.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Code != diag.UnmergedFlow {
+		test.Fatalf("got diagnostics %+v, want one %s", diagnostics, diag.UnmergedFlow)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	f_util "static_analyser/pkg/fileUtils"
+	"static_analyser/pkg/policy"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// update makes TestGolden write the outputs to the golden directories instead of comparing them
+var update = flag.Bool("update", false, "write the outputs to the golden directories instead of comparing them")
+
+// goldenRoot is the directory holding a golden directory for each analysed directory of tests
+const goldenRoot = "../tests/golden"
+
+// goldenPolicies is the name of the golden file holding the generated NetworkPolicies
+const goldenPolicies = "policies.yaml"
+
+func TestMain(m *testing.M) {
+	// TestMain runs the tests from the static_analyser directory, as source locations in the golden files include the
+	// root path relative to it.
+	//
+	// m: The tests to run.
+
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	logOut = io.Discard
+	os.Exit(m.Run())
+}
+
+func TestGolden(t *testing.T) {
+	// TestGolden checks that the analysis of each directory of tests with golden files is reproducible: two runs must
+	// render byte-identical manifests and NetworkPolicies, equal to the golden files. Run with -update to write the
+	// golden files after an intended change.
+	//
+	// t: The test.
+
+	dirs, err := os.ReadDir(goldenRoot)
+	if err != nil {
+		t.Fatalf("failed to read golden directories: %v", err)
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		name := dir.Name()
+		t.Run(name, func(t *testing.T) {
+			goldenDir := filepath.Join(goldenRoot, name)
+			rootDir := "../tests/" + name + "/"
+
+			first, err := renderOutputs(rootDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := renderOutputs(rootDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := sortedOutputNames(first, second)
+			for _, name := range names {
+				if !bytes.Equal(first[name], second[name]) {
+					t.Fatalf("%s differs between two runs", name)
+				}
+			}
+
+			if *update {
+				for _, name := range names {
+					if err := os.WriteFile(filepath.Join(goldenDir, name), first[name], 0644); err != nil {
+						t.Fatalf("failed to write golden file '%s': %v", name, err)
+					}
+				}
+				return
+			}
+
+			golden := make(map[string][]byte)
+			entries, err := os.ReadDir(goldenDir)
+			if err != nil {
+				t.Fatalf("failed to read golden directory '%s': %v", goldenDir, err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || (!strings.HasSuffix(entry.Name(), ".json") && entry.Name() != goldenPolicies) {
+					continue
+				}
+				content, err := os.ReadFile(filepath.Join(goldenDir, entry.Name()))
+				if err != nil {
+					t.Fatalf("failed to read golden file '%s': %v", entry.Name(), err)
+				}
+				golden[entry.Name()] = content
+			}
+
+			for _, name := range sortedOutputNames(first, golden) {
+				switch expected, ok := golden[name]; {
+				case !ok:
+					t.Errorf("%s: not in the golden files", name)
+				case first[name] == nil:
+					t.Errorf("%s: no longer written", name)
+				case !bytes.Equal(first[name], expected):
+					t.Errorf("%s: differs from the golden file; rerun with -update if the change is intended", name)
+				}
+			}
+		})
+	}
+}
+
+func renderOutputs(rootDir string) (map[string][]byte, error) {
+	// renderOutputs analyses a root directory and renders the files a run commits to git: the manifests, as written by
+	// f_util.WriteTCPManifestToJSON, and the generated NetworkPolicies.
+	//
+	// rootDir: The root directory of the applications to analyse.
+	//
+	// Returns:
+	// A map where the keys are the file names and the values are the file contents.
+	// An error if the analysis failed or a file could not be rendered.
+
+	parsedYamls, manifests, err := analyse(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "static_analyser-golden-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	outputs := make(map[string][]byte)
+	for application, manifest := range manifests {
+		if err := f_util.WriteTCPManifestToJSON(manifest, application, dir+string(filepath.Separator)); err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(filepath.Join(dir, manifest.Service+".json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest of '%s': %w", application, err)
+		}
+		outputs[manifest.Service+".json"] = content
+	}
+
+	var policies bytes.Buffer
+	for i, p := range policy.GenerateNetworkPolicies(parsedYamls, manifests) {
+		yamlData, err := yaml.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal NetworkPolicy '%s': %w", p.Metadata.Name, err)
+		}
+		if i > 0 {
+			policies.WriteString("---\n")
+		}
+		policies.Write(yamlData)
+	}
+	outputs[goldenPolicies] = policies.Bytes()
+	return outputs, nil
+}
+
+func sortedOutputNames(a map[string][]byte, b map[string][]byte) []string {
+	// sortedOutputNames returns the file names of two sets of outputs.
+	//
+	// a: The first set of outputs.
+	// b: The second set of outputs.
+	//
+	// Returns:
+	// The names found in either set, sorted.
+
+	seen := make(map[string]bool)
+	for name := range a {
+		seen[name] = true
+	}
+	for name := range b {
+		seen[name] = true
+	}
+	names := []string{}
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
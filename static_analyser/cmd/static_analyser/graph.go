@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+)
+
+func renderGraph(g t.ServiceGraph, format string) (string, error) {
+	// renderGraph renders a service graph in one of the supported formats.
+	//
+	// g: The graph to render.
+	// format: One of "dot", "mermaid", "cytoscape" or "graphml".
+	//
+	// Returns:
+	// The rendered graph.
+	// An error if the format is unknown or rendering fails.
+
+	switch format {
+	case "dot":
+		return graph.RenderDOT(g), nil
+	case "mermaid":
+		return graph.RenderMermaid(g), nil
+	case "cytoscape":
+		return graph.RenderCytoscape(g)
+	case "graphml":
+		return graph.RenderGraphML(g)
+	}
+	return "", fmt.Errorf("unknown graph format %q", format)
+}
+
+func runGraph(args []string) error {
+	// runGraph implements the "graph" subcommand, which renders the service dependency graph.
+	//
+	// args: The command line arguments following the subcommand name.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the analysis fails or the output could not be written.
+
+	flags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	format := flags.String("format", "dot", "output format: dot, mermaid, cytoscape or graphml")
+	service := flags.String("service", "", "only render the neighbourhood of this service")
+	depth := flags.Int("depth", 1, "number of hops around -service to render")
+	output := flags.String("o", "", "file to write the graph to (default stdout)")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	parsedYamls, manifests, err := analyse(*rootDir)
+	if err != nil {
+		return err
+	}
+
+	g := graph.BuildServiceGraph(parsedYamls, manifests)
+	if *service != "" {
+		g = graph.FilterServiceGraph(g, *service, *depth)
+		if len(g.Nodes) == 0 {
+			return fmt.Errorf("service %q is not part of the graph", *service)
+		}
+	}
+
+	rendered, err := renderGraph(g, *format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write graph to file '%s': %w", *output, err)
+	}
+	return nil
+}
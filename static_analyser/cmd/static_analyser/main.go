@@ -1,13 +1,24 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	diag "static_analyser/pkg/diagnostics"
 	f_util "static_analyser/pkg/fileUtils"
-	"static_analyser/pkg/file_finder"
 	"static_analyser/pkg/parser"
+	"static_analyser/pkg/policy"
+	"static_analyser/pkg/security"
 	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +33,27 @@ var root = "../tests/example_3/"
 // var root = "../input/"
 // var root = "..\\input\\"
 
+// set the directory holding a local snapshot of the Nacos configurations, used to resolve addresses read from the config center
+var configSnapshot = ""
+
+// set the directory caching the analysis of each application, keyed by the hash of its sources; empty disables the cache
+var cacheDir = ""
+
+// set the number of applications analysed at once
+var jobs = runtime.NumCPU()
+
+// set the least severity of diagnostics that makes a run exit with an error: "error", "warning", "info" or "never"
+var failOn = "error"
+
+// set the file the diagnostics are written to as JSON; empty writes none
+var diagnosticsOutput = ""
+
+// diagnosticsCollector gathers the problems found while analysing the sources
+var diagnosticsCollector = diag.NewCollector()
+
+// set the writer for progress output; subcommands that print their result to stdout send it to stderr instead
+var logOut io.Writer = os.Stdout
+
 var nacosFunctions = []string{"RegisterInstance", "GetService", "SelectAllInstances", "SelectOneHealthyInstance", "SelectInstances", "Subscribe"}
 
 func parseYamlFiles(root string) ([]string, map[string]*t.Yaml2Go, map[string]string, error) {
@@ -41,13 +73,29 @@ func parseYamlFiles(root string) ([]string, map[string]*t.Yaml2Go, map[string]st
 
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return err
+			// Skip what cannot be read, along with its contents
+			diagnosticsCollector.Add(t.Diagnostic{Code: diag.UnreadableSource, Severity: "error", Location: path, Message: err.Error()})
+			return nil
 		}
 		if !info.IsDir() && strings.HasSuffix(info.Name(), ".yaml") {
 			conf, serviceName, err := parser.ParseYaml(path) // Correctly handle returned values
 			if err != nil {
-				fmt.Printf("Error parsing YAML file %s: %v\n", path, err)
-				return nil // Continue processing other files even if this one fails
+				// Continue processing other files even if this one fails
+				var pathErr *fs.PathError
+				switch {
+				case errors.Is(err, parser.ErrMissingFields):
+					diagnosticsCollector.Add(t.Diagnostic{Code: diag.NotKubernetesResource, Severity: "info", Location: path, Message: "YAML file has no apiVersion or kind, so it is not a Kubernetes resource"})
+				case errors.As(err, &pathErr):
+					diagnosticsCollector.Add(t.Diagnostic{Code: diag.UnreadableSource, Severity: "error", Location: path, Message: err.Error()})
+				default:
+					diagnosticsCollector.Add(t.Diagnostic{Code: diag.InvalidYaml, Severity: "warning", Location: path, Message: err.Error()})
+				}
+				return nil
+			}
+
+			// NetworkPolicies are not workloads, they are read by the audit command
+			if conf.Kind == "NetworkPolicy" {
+				return nil
 			}
 
 			// Assuming you want to track YAML files that successfully parsed and contained the app label
@@ -75,11 +123,11 @@ func printValidYamlFiles(validYamlFiles []string) {
 	// Returns:
 	// This function doesn't return a value. It prints the paths to the valid YAML files to the standard output.
 
-	fmt.Println("Valid .yaml files with required fields:")
+	fmt.Fprintln(logOut, "Valid .yaml files with required fields:")
 	for _, file := range validYamlFiles {
-		fmt.Println(file)
+		fmt.Fprintln(logOut, file)
 	}
-	fmt.Println("")
+	fmt.Fprintln(logOut, "")
 }
 
 func createTCPManifests(parsedYamls map[string]*t.Yaml2Go) map[string]t.TCPManifest {
@@ -92,192 +140,777 @@ func createTCPManifests(parsedYamls map[string]*t.Yaml2Go) map[string]t.TCPManif
 
 	application2manifest := make(map[string]t.TCPManifest)
 
-	for application, value := range parsedYamls {
-		fmt.Printf("Service: %s, Version: %s \n", application, value.Metadata.Labels.Version)
+	applications := []string{}
+	for application := range parsedYamls {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+	for _, application := range applications {
+		value := parsedYamls[application]
+		fmt.Fprintf(logOut, "Service: %s, Version: %s \n", application, value.Metadata.Labels.Version)
 		version := value.Metadata.Labels.Version
 		application2manifest[application] = t.TCPManifest{Version: version, Service: application}
 	}
-	fmt.Println("")
+	fmt.Fprintln(logOut, "")
 
 	return application2manifest
 }
 
-func processServiceRegistrationCalls(applicationFolders map[string]string, nacosFunctions []string) (map[string]t.ServiceInfo, error) {
-	// processServiceRegistrationCalls processes the service registration calls from the application folders.
+func processServiceRegistrationCalls(analyses map[string]t.ApplicationAnalysis, applicationFolders map[string]string) map[string][]t.ServiceInfo {
+	// processServiceRegistrationCalls collects the service instances registered by the applications.
+	// A service registered by several applications, such as one whose folder holds the folders of others, is taken from
+	// the application with the most deeply nested folder, and then from the first application by name.
 	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
 	// applicationFolders: A map where the keys are the names of the applications and the values are the corresponding folder paths.
-	// nacosFunctions: A list of function names to search for in the .go files.
 	//
 	// Returns:
-	// A map where the keys are the names of the services and the values are the corresponding ServiceInfo.
-	// An error if there was a problem finding go files, parsing a file, or finding service registration wrappers.
+	// A map where the keys are the names of the services and the values are every instance registered under that name,
+	// such as one per port of a service registering its HTTP and gRPC ports.
 
-	registerWrapperMap := make(map[string]t.RegisterInstanceWrapper)
-	serviceDirectory := make(map[string]t.ServiceInfo)
-	var nacosFiles map[string][]string
-
-	for application, dir := range applicationFolders {
-		// Find all the go files
-		goFiles, err := file_finder.FindGoFiles(dir)
-		if err != nil {
-			return nil, fmt.Errorf("error finding go files in %s: %v", dir, err)
+	applications := []string{}
+	for application := range analyses {
+		applications = append(applications, application)
+	}
+	sort.Slice(applications, func(i, j int) bool {
+		di, dj := filepath.Clean(applicationFolders[applications[i]]), filepath.Clean(applicationFolders[applications[j]])
+		if len(di) != len(dj) {
+			return len(di) > len(dj)
 		}
-		// Find all the go files with sdk function calls
-		nacosFiles, err = file_finder.FindGoFilesWithFunctions(dir, nacosFunctions)
-		if err != nil {
-			return nil, fmt.Errorf("error finding go files with nacos functions in %s: %v", dir, err)
+		return applications[i] < applications[j]
+	})
+
+	serviceDirectory := make(map[string][]t.ServiceInfo)
+	for _, application := range applications {
+		registrations := analyses[application].Registrations
+		names := []string{}
+		for name := range registrations {
+			names = append(names, name)
 		}
-		for _, files := range nacosFiles {
-			for _, file := range files {
-				f, err := parser.ParseFile(file)
-				if err != nil {
-					return nil, fmt.Errorf("error parsing file %s: %v", file, err)
-				}
-				instances := parser.FindRegisterInstanceWrappers(f)
-				for _, instance := range instances {
-					registerWrapperMap[application] = instance
+		sort.Strings(names)
+		for _, name := range names {
+			if existing, ok := serviceDirectory[name]; ok {
+				if existing[0].Application != application {
+					message := fmt.Sprintf("service %s is registered by both %s and %s, using %s", name, existing[0].Application, application, existing[0].Application)
+					diagnosticsCollector.Add(t.Diagnostic{Code: diag.DuplicateRegistration, Severity: "warning", Location: applicationFolders[application], Message: message, Service: application})
 				}
+				continue
 			}
-			for _, file := range goFiles {
-				f, err := parser.ParseFile(file)
-				if err != nil {
-					return nil, fmt.Errorf("error parsing file %s: %v", file, err)
-				}
-				for key, value := range registerWrapperMap {
-					if key == application {
-						names, infos := parser.FindRegisterInstanceWrapperInvocations(f, value, application)
-						for i, name := range names {
-							serviceDirectory[name] = infos[i]
-						}
-					}
-				}
+			serviceDirectory[name] = registrations[name]
+		}
+	}
+	return serviceDirectory
+}
+
+func processServiceDiscoveryCalls(analyses map[string]t.ApplicationAnalysis, serviceDirectory map[string][]t.ServiceInfo) map[string][]t.TCPRequest {
+	// processServiceDiscoveryCalls turns the service discovery calls of the applications into TCPRequests.
+	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// serviceDirectory: A map where the keys are the names of the services and the values are their registered instances.
+	//
+	// Returns:
+	// A map where the keys are the names of the applications and the values are slices of TCPRequests, one per
+	// discovery call and instance of the discovered service. A discovery of an unregistered service yields a single request without target;
+	// checkServiceNames reports it.
+
+	callMap := make(map[string][]t.TCPRequest)
+	for application, analysis := range analyses {
+		for _, call := range analysis.Discoveries {
+			for _, service := range instancesOf(serviceDirectory, call.ServiceName) {
+				req := t.TCPRequest{Type: "tcp", URL: service.IP, Name: service.Application, Port: service.Port, ServiceName: call.ServiceName, Kind: call.Method, Location: call.Location}
+				callMap[application] = append(callMap[application], req)
 			}
 		}
 	}
-	return serviceDirectory, nil
+	return callMap
 }
 
-func processServiceDiscoveryCalls(applicationFolders map[string]string, nacosFunctions []string, serviceDirectory map[string]t.ServiceInfo) (map[string][]t.TCPRequest, error) {
-	// processServiceDiscoveryCalls processes the service discovery calls from the application folders.
+func checkServiceNames(analyses map[string]t.ApplicationAnalysis, serviceDirectory map[string][]t.ServiceInfo, applicationFolders map[string]string) {
+	// checkServiceNames checks that the services the applications discover and register match up, and reports the
+	// mismatches as diagnostics: discovered services no application registers (SA008), names that differ from a
+	// registered one only in case, separators or a typo (SA009), and registered services no application discovers (SA010).
+	// Services registered by several applications are reported by processServiceRegistrationCalls (SA005).
 	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// serviceDirectory: A map where the keys are the names of the services and the values are their registered instances.
 	// applicationFolders: A map where the keys are the names of the applications and the values are the corresponding folder paths.
-	// nacosFunctions: A list of function names to search for in the .go files.
-	// serviceDirectory: A map where the keys are the names of the services and the values are the corresponding ServiceInfo.
 	//
 	// Returns:
-	// A map where the keys are the names of the applications and the values are slices of TCPRequests.
-	// An error if there was a problem finding go files, parsing a file, or finding service discovery wrappers.
+	// This function doesn't return a value. Names computed at runtime are not checked, but a discovered one consumes every
+	// registered service it can match, and a registered one registers every discovered service it can match, see util.MatchTemplate.
 
-	serviceDiscoveryWrapperMap := make(map[string]t.ServiceDiscoveryWrapper)
-	callMap := make(map[string][]t.TCPRequest)
-	var nacosFiles map[string][]string
+	applications := []string{}
+	for application := range analyses {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+	registered := []string{}
+	// Names computed at runtime register every service they can match
+	templates := []string{}
+	for name := range serviceDirectory {
+		if name != "nil" && util.IsResolved(name) {
+			registered = append(registered, name)
+		} else if name != "nil" {
+			templates = append(templates, name)
+		}
+	}
+	sort.Strings(registered)
+	sort.Strings(templates)
+
+	normalize := func(name string) string {
+		// normalize folds the case and drops the separators of a service name.
+		//
+		// name: The service name.
+		//
+		// Returns:
+		// The name in lower case, without dashes, underscores, dots and spaces.
+
+		return strings.NewReplacer("-", "", "_", "", ".", "", " ", "").Replace(strings.ToLower(name))
+	}
+	similar := func(name string) string {
+		// similar finds the registered service a name is most likely meant to be.
+		//
+		// name: The service name, which is not registered.
+		//
+		// Returns:
+		// The first registered name equal to it but for case and separators, or else within one edit of it, two for names
+		// of eight characters or more. An empty string if there is none.
+
+		allowed := 1
+		if len(name) >= 8 {
+			allowed = 2
+		}
+		match := ""
+		for _, candidate := range registered {
+			if normalize(candidate) == normalize(name) {
+				return candidate
+			}
+			if match == "" && util.EditDistance(strings.ToLower(candidate), strings.ToLower(name)) <= allowed {
+				match = candidate
+			}
+		}
+		return match
+	}
+
+	discovered := make(map[string]bool)
+	// Names computed at runtime consume every registered service they can match
+	patterns := []string{}
+	for _, application := range applications {
+		for _, call := range analyses[application].Discoveries {
+			name := call.ServiceName
+			if name != "nil" && !util.IsResolved(name) {
+				patterns = append(patterns, name)
+			}
+			if name == "nil" || !util.IsResolved(name) {
+				continue
+			}
+			discovered[name] = true
+			if _, ok := serviceDirectory[name]; ok {
+				continue
+			}
+			matched := false
+			for _, template := range templates {
+				matched = matched || util.MatchTemplate(template, name)
+			}
+			if matched {
+				continue
+			}
+			if match := similar(name); match != "" {
+				message := fmt.Sprintf("service %s is discovered but not registered; did you mean %s, which is registered? Nacos service names are matched exactly", name, match)
+				diagnosticsCollector.Add(t.Diagnostic{Code: diag.SimilarServiceName, Severity: "warning", Location: call.Location, Message: message, Service: application})
+				continue
+			}
+			message := fmt.Sprintf("service %s is discovered but no workload registers it", name)
+			diagnosticsCollector.Add(t.Diagnostic{Code: diag.UnregisteredService, Severity: "warning", Location: call.Location, Message: message, Service: application})
+		}
+	}
+
+	// Registered names that only differ in case or separators may be taken for one another, or squat on one another
+	for i, name := range registered {
+		for _, other := range registered[:i] {
+			if normalize(other) == normalize(name) {
+				application := serviceDirectory[name][0].Application
+				message := fmt.Sprintf("service %s is registered by %s, and %s by %s; the names only differ in case or separators", other, serviceDirectory[other][0].Application, name, application)
+				diagnosticsCollector.Add(t.Diagnostic{Code: diag.SimilarServiceName, Severity: "warning", Location: applicationFolders[application], Message: message, Service: application})
+			}
+		}
+	}
+
+	for _, name := range registered {
+		matched := false
+		for _, pattern := range patterns {
+			matched = matched || util.MatchTemplate(pattern, name)
+		}
+		if discovered[name] || matched {
+			continue
+		}
+		application := serviceDirectory[name][0].Application
+		message := fmt.Sprintf("service %s is registered but no analysed workload discovers it", name)
+		diagnosticsCollector.Add(t.Diagnostic{Code: diag.UnconsumedService, Severity: "info", Location: applicationFolders[application], Message: message, Service: application})
+	}
+}
+
+func findKubernetesServices(root string) []t.KubernetesService {
+	// findKubernetesServices finds the Kubernetes Services declared in a source tree.
+	//
+	// root: The root directory for the search.
+	//
+	// Returns:
+	// The Services of the .yaml and .yml files under root. Files that cannot be read or parsed are skipped; parseYamlFiles reports them.
 
-	for application, dir := range applicationFolders {
-		goFiles, err := file_finder.FindGoFiles(dir)
+	services := []t.KubernetesService{}
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return nil, fmt.Errorf("error finding go files in %s: %v", dir, err)
+			return nil
 		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+		if found, err := f_util.ReadKubernetesServices(path); err == nil {
+			services = append(services, found...)
+		}
+		return nil
+	})
+	return services
+}
+
+func findKubernetesNamespaces(root string) []t.KubernetesNamespace {
+	// findKubernetesNamespaces finds the Kubernetes Namespaces declared in a source tree.
+	//
+	// root: The root directory for the search.
+	//
+	// Returns:
+	// The Namespaces of the .yaml and .yml files under root. Files that cannot be read or parsed are skipped; parseYamlFiles reports them.
 
-		nacosFiles, err = file_finder.FindGoFilesWithFunctions(dir, nacosFunctions)
+	namespaces := []t.KubernetesNamespace{}
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return nil, fmt.Errorf("error finding go files with nacos functions in %s: %v", dir, err)
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+		if found, err := f_util.ReadKubernetesNamespaces(path); err == nil {
+			namespaces = append(namespaces, found...)
+		}
+		return nil
+	})
+	return namespaces
+}
+
+func checkPorts(analyses map[string]t.ApplicationAnalysis, serviceDirectory map[string][]t.ServiceInfo, parsedYamls map[string]*t.Yaml2Go, applicationFolders map[string]string, services []t.KubernetesService) {
+	// checkPorts checks that the ports an application listens on, registers in Nacos, declares as containerPorts and is
+	// sent traffic on by its Kubernetes Services agree, and reports the mismatches as diagnostics (SA011). Generated
+	// policies allow the registered ports, so a mismatch makes them allow a port nothing listens on.
+	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// serviceDirectory: A map where the keys are the names of the services and the values are their registered instances.
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	// applicationFolders: A map where the keys are the names of the applications and the values are the corresponding folder paths.
+	// services: The Kubernetes Services, see findKubernetesServices.
+	//
+	// Returns:
+	// This function doesn't return a value. Ports computed at runtime are not checked, and neither are the registered
+	// and target ports of an application with a listen port computed at runtime.
+
+	applications := []string{}
+	for application := range analyses {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+
+	owner := func(file string) string {
+		// owner finds the application a source file belongs to.
+		//
+		// file: The path of the file.
+		//
+		// Returns:
+		// The application with the most deeply nested folder holding the file, as an application's folder may hold the
+		// folders of others.
+
+		best := ""
+		for application, folder := range applicationFolders {
+			folder = filepath.Clean(folder)
+			if !strings.HasPrefix(filepath.Clean(file), folder+string(filepath.Separator)) {
+				continue
+			}
+			if best == "" || len(folder) > len(filepath.Clean(applicationFolders[best])) || (len(folder) == len(filepath.Clean(applicationFolders[best])) && application < best) {
+				best = application
+			}
 		}
+		return best
+	}
+	// numeric tells whether a port is known statically
+	numeric := func(port string) bool {
+		_, err := strconv.Atoi(port)
+		return err == nil
+	}
+	// report records a port mismatch of an application
+	report := func(application string, location string, message string) {
+		diagnosticsCollector.Add(t.Diagnostic{Code: diag.PortMismatch, Severity: "warning", Location: location, Message: message, Service: application})
+	}
+
+	names := []string{}
+	for name := range serviceDirectory {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, application := range applications {
+		folder := applicationFolders[application]
 
-		for _, files := range nacosFiles {
-			for _, file := range files {
-				f, err := parser.ParseFile(file)
-				if err != nil {
-					return nil, fmt.Errorf("error parsing file %s: %v", file, err)
+		listened := []string{}
+		dynamic := false
+		for _, listener := range analyses[application].Listeners {
+			if file, _ := util.SplitLocation(listener.Location); owner(file) != application {
+				continue
+			}
+			if !numeric(listener.Port) {
+				dynamic = true
+			} else if !util.Contains(listened, listener.Port) {
+				listened = append(listened, listener.Port)
+			}
+		}
+		sort.Strings(listened)
+
+		containerPorts := []string{}
+		portNames := make(map[string]string)
+		if conf, ok := parsedYamls[application]; ok {
+			for _, container := range conf.Spec.Template.Spec.Containers {
+				for _, port := range container.Ports {
+					containerPorts = append(containerPorts, strconv.Itoa(port.ContainerPort))
+					if port.Name != "" {
+						portNames[port.Name] = strconv.Itoa(port.ContainerPort)
+					}
 				}
+			}
+		}
+		sort.Strings(containerPorts)
 
-				instances := parser.FindServiceDiscoveryWrappers(f)
-				for _, instance := range instances {
-					serviceDiscoveryWrapperMap[application] = instance
+		for _, name := range names {
+			for _, info := range serviceDirectory[name] {
+				if info.Application != application || !numeric(info.Port) {
+					continue
+				}
+				if len(listened) > 0 && !dynamic && !util.Contains(listened, info.Port) {
+					report(application, folder, fmt.Sprintf("service %s is registered on port %s, but %s only listens on %s", name, info.Port, application, strings.Join(listened, ", ")))
+				}
+				if len(containerPorts) > 0 && !util.Contains(containerPorts, info.Port) {
+					report(application, folder, fmt.Sprintf("service %s is registered on port %s, which is not a containerPort of %s (%s)", name, info.Port, application, strings.Join(containerPorts, ", ")))
 				}
 			}
 		}
 
-		for _, file := range goFiles {
-			f, err := parser.ParseFile(file)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing file %s: %v", file, err)
+		if len(containerPorts) > 0 {
+			for _, listener := range analyses[application].Listeners {
+				if file, _ := util.SplitLocation(listener.Location); owner(file) != application || !numeric(listener.Port) {
+					continue
+				}
+				if !util.Contains(containerPorts, listener.Port) {
+					report(application, listener.Location, fmt.Sprintf("%s listens on port %s, which is not a containerPort of %s (%s)", listener.Kind, listener.Port, application, strings.Join(containerPorts, ", ")))
+				}
 			}
+		}
 
-			for key, value := range serviceDiscoveryWrapperMap {
-				if key == application {
-					names := parser.FindSelectInstanceWrappersInvocations(f, value, application)
-					for _, name := range names {
-					
-						req := t.TCPRequest{Type: "tcp", URL: serviceDirectory[name].IP, Name: serviceDirectory[name].Application, Port: serviceDirectory[name].Port}
-						callMap[application] = append(callMap[application], req)
-					}
+		if len(listened) == 0 || dynamic {
+			continue
+		}
+		namespace, _ := policy.WorkloadSelector(application, parsedYamls)
+		labels := policy.WorkloadLabels(application, parsedYamls)
+		for _, service := range services {
+			serviceNamespace := service.Metadata.Namespace
+			if serviceNamespace == "" {
+				serviceNamespace = "default"
+			}
+			if serviceNamespace != namespace || len(service.Spec.Selector) == 0 || !policy.MatchSelector(t.LabelSelector{MatchLabels: service.Spec.Selector}, labels) {
+				continue
+			}
+			for _, port := range service.Spec.Ports {
+				target := port.TargetPort
+				if target == "" {
+					target = strconv.Itoa(port.Port)
+				} else if named, ok := portNames[target]; ok {
+					target = named
+				}
+				if numeric(target) && !util.Contains(listened, target) {
+					report(application, service.File, fmt.Sprintf("Service %s targets port %s, but %s only listens on %s", service.Metadata.Name, target, application, strings.Join(listened, ", ")))
 				}
 			}
 		}
 	}
+}
+
+func instancesOf(serviceDirectory map[string][]t.ServiceInfo, serviceName string) []t.ServiceInfo {
+	// instancesOf returns the registered instances of a service.
+	//
+	// serviceDirectory: A map where the keys are the names of the services and the values are their registered instances.
+	// serviceName: The name of the service.
+	//
+	// Returns:
+	// The instances of the service, or a single empty ServiceInfo if it is not registered. A name computed at runtime,
+	// such as "orders-{shard}", gets the instances of every registered service it can match, see util.MatchTemplate.
+
+	if instances := serviceDirectory[serviceName]; len(instances) > 0 {
+		return instances
+	}
+	if !util.IsResolved(serviceName) {
+		names := []string{}
+		for name := range serviceDirectory {
+			if util.MatchTemplate(serviceName, name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		instances := []t.ServiceInfo{}
+		for _, name := range names {
+			instances = append(instances, serviceDirectory[name]...)
+		}
+		if len(instances) > 0 {
+			return instances
+		}
+	}
+	return []t.ServiceInfo{{}}
+}
+
+func processConfigAccesses(analyses map[string]t.ApplicationAnalysis, application2manifest map[string]t.TCPManifest) {
+	// processConfigAccesses records the Nacos configurations each application reads and writes in its TCPManifest.
+	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// application2manifest: A map where the keys are the names of the applications and the values are the corresponding TCPManifests. It is updated in place.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	for application, analysis := range analyses {
+		manifest := application2manifest[application]
+		manifest.Configs = analysis.Configs
+		application2manifest[application] = manifest
+	}
+}
+
+func processSecurityChecks(analyses map[string]t.ApplicationAnalysis, application2manifest map[string]t.TCPManifest) {
+	// processSecurityChecks checks the Nacos client configuration of each application and records the weaknesses in its TCPManifest.
+	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// application2manifest: A map where the keys are the names of the applications and the values are the corresponding TCPManifests. It is updated in place.
+	//
+	// Returns:
+	// This function doesn't return a value. The weaknesses are also printed, by application.
+
+	applications := []string{}
+	for application := range analyses {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+	for _, application := range applications {
+		findings := security.CheckClientConfigs(analyses[application].ClientConfigs)
+		for _, finding := range findings {
+			fmt.Fprintf(logOut, "Security: %s %s %s %s: %s\n", application, finding.Rule, finding.Severity, finding.Location, finding.Message)
+		}
+		manifest := application2manifest[application]
+		manifest.Security = findings
+		application2manifest[application] = manifest
+	}
+}
 
-	return callMap, nil
+func containsRequest(requests []t.TCPRequest, req t.TCPRequest) bool {
+	// containsRequest checks whether a list of TCPRequests holds a request to the same target, of the same kind, as req.
+	//
+	// requests: The requests to search.
+	// req: The request to look for.
+	//
+	// Returns:
+	// True if a request with the same kind, URL, port and path is found, false otherwise.
+
+	for _, existing := range requests {
+		if existing.Kind == req.Kind && existing.URL == req.URL && existing.Port == req.Port && existing.Path == req.Path {
+			return true
+		}
+	}
+	return false
 }
 
-func updateAndWriteManifests(applicationFolders map[string]string, application2manifest map[string]t.TCPManifest, callMap map[string][]t.TCPRequest, outputPrefix string) {
-	// updateAndWriteManifests updates the TCPManifests with the corresponding TCPRequests and writes them to JSON files.
+func processClientCalls(analyses map[string]t.ApplicationAnalysis, applicationFolders map[string]string, serviceDirectory map[string][]t.ServiceInfo, callMap map[string][]t.TCPRequest) {
+	// processClientCalls adds the HTTP, gRPC, database, cache, message broker and Nacos server client calls of the applications to the call map.
+	// A call whose target derives from a discovered instance adds its method and path to the requests of the matching
+	// discovery call; if there are none, as for gRPC targets using the nacos:// resolver, it becomes a request to each
+	// registered instance. Other calls become requests of their own, linked to a workload if their host names one.
 	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
 	// applicationFolders: A map where the keys are the names of the applications and the values are the corresponding folder paths.
+	// serviceDirectory: A map where the keys are the names of the services and the values are their registered instances.
+	// callMap: A map where the keys are the names of the applications and the values are slices of TCPRequests. It is updated in place.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	for application, analysis := range analyses {
+		for _, call := range analysis.ClientCalls {
+			req := t.TCPRequest{Type: "tcp", Kind: call.Kind, Method: call.Method, Path: call.Path, Location: call.Location}
+
+			if call.Discovered {
+				// Refine the requests of the discovery call the URL came from, if they have not been refined yet.
+				// Targets using the nacos:// resolver discover the service themselves, so they come from no discovery call
+				refined := false
+				location := ""
+				for i, existing := range callMap[application] {
+					if strings.HasPrefix(call.URL, "nacos://") {
+						break
+					}
+					if existing.ServiceName == call.ServiceName && existing.Kind != "http" && existing.Kind != "grpc" && existing.Method == "" && existing.Path == "" && (!refined || existing.Location == location) {
+						callMap[application][i].Method = call.Method
+						callMap[application][i].Path = call.Path
+						refined = true
+						location = existing.Location
+					}
+				}
+				if refined {
+					continue
+				}
+				req.ServiceName = call.ServiceName
+				for _, service := range instancesOf(serviceDirectory, call.ServiceName) {
+					req.Name = service.Application
+					req.URL = service.IP
+					req.Port = service.Port
+					callMap[application] = append(callMap[application], req)
+				}
+				continue
+			} else {
+				req.URL = call.Host
+				req.Port = call.Port
+				if strings.HasPrefix(call.Kind, "nacos") && containsRequest(callMap[application], req) {
+					// Clients are often configured in several places; keep one registry entry per server
+					continue
+				}
+				// The longest matching workload is the most specific, so "api-admin.ns" is not taken for "api"
+				for workload := range applicationFolders {
+					if (call.Host == workload || strings.HasPrefix(call.Host, workload+".")) && len(workload) > len(req.Name) {
+						req.Name = workload
+					}
+				}
+			}
+			callMap[application] = append(callMap[application], req)
+		}
+	}
+}
+
+func sortRequests(requests []t.TCPRequest) []t.TCPRequest {
+	// sortRequests sorts TCPRequests so that manifests are byte-identical across runs and diff cleanly.
+	//
+	// requests: The requests to sort. They are sorted in place.
+	//
+	// Returns:
+	// The requests, sorted by target workload, service name, URL, port, kind, method, path and source location.
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		ka := []string{a.Name, a.ServiceName, a.URL, a.Port, a.Kind, a.Method, a.Path, a.Location}
+		kb := []string{b.Name, b.ServiceName, b.URL, b.Port, b.Kind, b.Method, b.Path, b.Location}
+		for k := range ka {
+			if ka[k] != kb[k] {
+				return ka[k] < kb[k]
+			}
+		}
+		return false
+	})
+	return requests
+}
+
+func writeManifests(application2manifest map[string]t.TCPManifest, outputPrefix string) {
+	// writeManifests writes the TCPManifests to JSON files.
+	//
 	// application2manifest: A map where the keys are the names of the applications and the values are the corresponding TCPManifests.
-	// callMap: A map where the keys are the names of the applications and the values are slices of TCPRequests.
 	// outputPrefix: A string to be prepended to the output file names.
 	//
 	// Returns:
-	// This function doesn't return a value. It updates the TCPManifests in application2manifest and writes them to JSON files. The file names are the application names with outputPrefix prepended.
-
-	for application := range applicationFolders {
-		temp := application2manifest[application]
-		temp.Requests = callMap[application]
-		fmt.Println("Manifest: ", temp)
-		application2manifest[application] = temp
+	// This function doesn't return a value. It writes the TCPManifests to JSON files. The file names are the application names with outputPrefix prepended.
 
+	applications := []string{}
+	for application := range application2manifest {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+	for _, application := range applications {
+		fmt.Fprintln(logOut, "Manifest: ", application2manifest[application])
 		f_util.WriteTCPManifestToJSON(application2manifest[application], application, outputPrefix)
-
 	}
 }
 
-func main() {
-	// main is the entry point of the program.
+func analyse(root string) (map[string]*t.Yaml2Go, map[string]t.TCPManifest, error) {
+	// analyse runs the registration and discovery analysis over a root directory without writing any files.
 	// It performs the following steps:
-	// 1. Parses YAML files from a given root directory.
-	// 2. Prints the valid YAML files.
-	// 3. Creates TCP manifests from the parsed YAMLs.
+	// 1. Parses YAML files from a given root directory and prints the valid ones.
+	// 2. Creates TCP manifests from the parsed YAMLs.
+	// 3. Analyses the source of every application concurrently, parsing each Go file once.
+	//    Unchanged applications are read from the cache, if enabled.
 	// 4. Processes service registration calls from the application folders.
 	// 5. Processes service discovery calls from the application folders.
-	// 6. Updates and writes the manifests.
+	// 6. Checks that the discovered and registered services match up, such as discoveries of services no one registers.
+	// 7. Checks that the listen, registered, container and Service target ports of the applications agree.
+	// 8. Processes Nacos config center accesses from the application folders.
+	// 9. Checks the Nacos client configuration of the applications for weaknesses, such as hard-coded credentials.
+	// 10. Processes HTTP, gRPC, database, cache, message broker and Nacos server client calls from the application folders.
+	// 11. Adds the requests to the manifests.
+	// The problems found on the way are recorded as diagnostics.
+	//
+	// root: The root directory for the search.
+	//
+	// Returns:
+	// A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	// A map where the keys are the names of the applications and the values are the corresponding TCPManifests, including their requests.
+	// An error if there was a problem walking the file tree or processing the application folders.
 
-	// Parse YAML files from the root directory
 	validYamlFiles, parsedYamls, applicationFolders, err := parseYamlFiles(root)
 	if err != nil {
-		fmt.Printf("Error walking the file tree: %v\n", err)
-		return
+		return nil, nil, fmt.Errorf("error walking the file tree: %v", err)
 	}
-
-	// Print the valid YAML files
 	printValidYamlFiles(validYamlFiles)
 
-	// Create TCP manifests from the parsed YAMLs
 	application2manifest := createTCPManifests(parsedYamls)
 
-	// Process service registration calls from the application folders
-	serviceDirectory, err := processServiceRegistrationCalls(applicationFolders, nacosFunctions)
+	analyses := analyseApplications(applicationFolders, configSnapshot, cacheDir, jobs)
+
+	serviceDirectory := processServiceRegistrationCalls(analyses, applicationFolders)
+	callMap := processServiceDiscoveryCalls(analyses, serviceDirectory)
+	checkServiceNames(analyses, serviceDirectory, applicationFolders)
+	checkPorts(analyses, serviceDirectory, parsedYamls, applicationFolders, findKubernetesServices(root))
+	processConfigAccesses(analyses, application2manifest)
+	processSecurityChecks(analyses, application2manifest)
+	processClientCalls(analyses, applicationFolders, serviceDirectory, callMap)
+
+	for application := range applicationFolders {
+		manifest := application2manifest[application]
+		manifest.Requests = sortRequests(callMap[application])
+		application2manifest[application] = manifest
+	}
+
+	return parsedYamls, application2manifest, nil
+}
+
+func addAnalysisFlags(flags *flag.FlagSet) {
+	// addAnalysisFlags adds the flags controlling the analysis to the flags of a command.
+	//
+	// flags: The flag set of the command.
+	//
+	// Returns:
+	// This function doesn't return a value. The flags set the config snapshot, the analysis cache and the number of jobs.
+
+	flags.StringVar(&configSnapshot, "config-snapshot", configSnapshot, "directory holding a local snapshot of the Nacos configurations")
+	flags.StringVar(&cacheDir, "cache", cacheDir, "directory caching the analysis of each application, so that unchanged applications are skipped on re-runs")
+	flags.IntVar(&jobs, "jobs", jobs, "number of applications to analyse at once")
+	flags.StringVar(&failOn, "fail-on", failOn, "least severity of diagnostics that fails the run: error, warning, info or never")
+	flags.StringVar(&diagnosticsOutput, "diagnostics", diagnosticsOutput, "file to write the diagnostics to as JSON")
+}
+
+func reportDiagnostics() error {
+	// reportDiagnostics prints a summary of the diagnostics of the run, writes them as JSON if asked to,
+	// and decides whether they fail the run.
+	//
+	// Returns:
+	// An error if the diagnostics could not be written, or if one of them is at least as severe as failOn.
+
+	diagnostics := diagnosticsCollector.Diagnostics()
+	fmt.Fprint(logOut, diag.RenderDiagnostics(diagnostics))
+
+	if diagnosticsOutput != "" {
+		jsonData, err := json.MarshalIndent(diagnostics, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal diagnostics: %w", err)
+		}
+		if err := os.WriteFile(diagnosticsOutput, jsonData, 0644); err != nil {
+			return fmt.Errorf("failed to write diagnostics to '%s': %w", diagnosticsOutput, err)
+		}
+	}
+
+	failed, err := diag.ExceedsSeverity(diagnostics, failOn)
 	if err != nil {
-		fmt.Printf("Error processing application folders: %v\n", err)
+		return err
+	}
+	if failed {
+		return fmt.Errorf("diagnostics at or above %s severity were found", failOn)
+	}
+	return nil
+}
+
+func runSubcommand(name string, args []string) error {
+	// runSubcommand runs one of the static analyser subcommands.
+	//
+	// name: The name of the subcommand.
+	// args: The command line arguments following the subcommand name.
+	//
+	// Returns:
+	// An error if the subcommand is unknown or fails, or if its diagnostics fail the run.
+
+	// Subcommands print their result to stdout, so progress output goes to stderr
+	logOut = os.Stderr
+
+	var err error
+	switch name {
+	case "graph":
+		err = runGraph(args)
+	case "query":
+		err = runQuery(args)
+	case "diff":
+		err = runDiff(args)
+	case "fake-nacos":
+		err = runFakeNacos(args)
+	case "validate":
+		err = runValidate(args)
+	case "flows":
+		err = runFlows(args)
+	case "simulate":
+		err = runSimulate(args)
+	case "audit":
+		err = runAudit(args)
+	case "rbac":
+		err = runRBAC(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	// Diagnostics are reported even if the command failed, as they may explain why
+	if diagErr := reportDiagnostics(); err == nil {
+		err = diagErr
+	}
+	return err
+}
+
+func main() {
+	// main is the entry point of the program.
+	// It analyses the applications under the root directory, see analyse, writes their manifests, and reports the
+	// problems found on the way as diagnostics, exiting with an error if they are at least as severe as -fail-on.
+	//
+	// If a subcommand is given as the first argument, it is run instead. Flags given as the first
+	// arguments set the root directory, the output directory, the config snapshot, the cache, the number of jobs
+	// and the handling of diagnostics.
+
+	if len(os.Args) > 1 && strings.HasPrefix(os.Args[1], "-") {
+		flags := flag.NewFlagSet("static_analyser", flag.ExitOnError)
+		flags.StringVar(&root, "root", root, "root directory of the applications to analyse")
+		flags.StringVar(&outputPrefix, "o", outputPrefix, "prefix of the manifest files to write, such as a directory ending in a slash")
+		addAnalysisFlags(flags)
+		flags.Parse(os.Args[1:])
+	} else if len(os.Args) > 1 {
+		if err := runSubcommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	// Process service discovery calls from the application folders
-	callMap, err := processServiceDiscoveryCalls(applicationFolders, nacosFunctions, serviceDirectory)
+	// Analyse the applications under the root directory and write their manifests
+	_, application2manifest, err := analyse(root)
 	if err != nil {
-		fmt.Printf("Error processing application files: %v\n", err)
-		return
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
+	writeManifests(application2manifest, outputPrefix)
 
-	// Update and write the manifests
-	updateAndWriteManifests(applicationFolders, application2manifest, callMap, outputPrefix)
+	// Report the diagnostics
+	if err := reportDiagnostics(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 }
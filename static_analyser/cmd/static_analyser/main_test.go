@@ -0,0 +1,58 @@
+package main
+
+import (
+	diag "static_analyser/pkg/diagnostics"
+	t "static_analyser/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestCheckServiceNames(test *testing.T) {
+	// TestCheckServiceNames checks the diagnostics of discovered and registered names, with names computed at runtime
+	// on either side matching the names they can take.
+	//
+	// test: The test.
+
+	diagnosticsCollector = diag.NewCollector()
+	defer func() { diagnosticsCollector = diag.NewCollector() }()
+
+	discover := func(names ...string) t.ApplicationAnalysis {
+		analysis := t.ApplicationAnalysis{}
+		for _, name := range names {
+			analysis.Discoveries = append(analysis.Discoveries, t.DiscoveryCall{ServiceName: name, Location: "gateway/main.go:1"})
+		}
+		return analysis
+	}
+	analyses := map[string]t.ApplicationAnalysis{
+		// orders-shard-1 is registered by the template of the shards, audit-{region} consumes audit-eu, and payment is a typo
+		"gateway": discover("orders-shard-1", "payment", "ledger", "audit-{region}"),
+		"shards":  {},
+	}
+	serviceDirectory := map[string][]t.ServiceInfo{
+		"orders-shard-{shard}": {{Application: "shards", IP: "{ip}", Port: "8080"}},
+		"payments":             {{Application: "payments", IP: "{ip}", Port: "8080"}},
+		"audit-eu":             {{Application: "audit", IP: "{ip}", Port: "8080"}},
+		"reports":              {{Application: "reports", IP: "{ip}", Port: "8080"}},
+	}
+	checkServiceNames(analyses, serviceDirectory, map[string]string{"reports": "reports"})
+
+	want := map[string]string{
+		"payment":  diag.SimilarServiceName,
+		"ledger":   diag.UnregisteredService,
+		"payments": diag.UnconsumedService,
+		"reports":  diag.UnconsumedService,
+	}
+	diagnostics := diagnosticsCollector.Diagnostics()
+	if len(diagnostics) != len(want) {
+		test.Fatalf("got diagnostics %+v, want %d", diagnostics, len(want))
+	}
+	for _, diagnostic := range diagnostics {
+		found := false
+		for name, code := range want {
+			found = found || (diagnostic.Code == code && strings.Contains(diagnostic.Message, "service "+name+" "))
+		}
+		if !found {
+			test.Errorf("got unexpected diagnostic %+v", diagnostic)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"os"
+	"sort"
+	diag "static_analyser/pkg/diagnostics"
+	f_util "static_analyser/pkg/fileUtils"
+	"static_analyser/pkg/file_finder"
+	"static_analyser/pkg/parser"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+	"sync"
+)
+
+// cacheVersion is part of every cache key; bump it whenever the detectors change what they find
+const cacheVersion = "11"
+
+func analyseApplication(application string, dir string, snapshot map[string]string, cacheDir string) t.ApplicationAnalysis {
+	// analyseApplication analyses the source of one application in a single pass, parsing each of its Go files once.
+	//
+	// application: The name of the application.
+	// dir: The folder of the application.
+	// snapshot: The config snapshot, see f_util.ReadConfigSnapshot. May be empty.
+	// cacheDir: The directory of the analysis cache. Empty if the cache is disabled.
+	//
+	// Returns:
+	// The ApplicationAnalysis of the application, read from the cache if its Go files, folder and the snapshot are unchanged.
+	// Files that cannot be read are skipped and files with syntax errors are analysed as far as they parse; both are
+	// reported in the Diagnostics of the analysis.
+
+	diagnostics := []t.Diagnostic{}
+	goFiles, err := file_finder.FindGoFiles(dir)
+	if err != nil {
+		diagnostics = append(diagnostics, t.Diagnostic{Code: diag.UnreadableSource, Severity: "error", Location: dir, Message: err.Error(), Service: application})
+	}
+
+	// The cache key covers everything the analysis depends on: file paths appear in source locations
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", cacheVersion, application, dir)
+	keys := make([]string, 0, len(snapshot))
+	for key := range snapshot {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s\x00%d\x00%s", key, len(snapshot[key]), snapshot[key])
+	}
+	readable := []string{}
+	contents := []string{}
+	for _, file := range goFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			diagnostics = append(diagnostics, t.Diagnostic{Code: diag.UnreadableSource, Severity: "error", Location: file, Message: err.Error(), Service: application})
+			continue
+		}
+		readable = append(readable, file)
+		contents = append(contents, string(content))
+		fmt.Fprintf(hash, "%s\x00%d\x00%s", file, len(content), content)
+	}
+	analysis := t.ApplicationAnalysis{Application: application, Hash: hex.EncodeToString(hash.Sum(nil)), Diagnostics: diagnostics}
+	if cacheDir != "" && len(diagnostics) == 0 {
+		if cached, ok := f_util.ReadAnalysisCache(cacheDir, analysis.Hash); ok {
+			return cached
+		}
+	}
+
+	// Every wrapper is followed, each with its own parameter mapping. A function calling the SDK several times is a
+	// wrapper of each call, so only identical wrappers, such as those of a file analysed twice, are taken once
+	registerWrappers := []t.RegisterInstanceWrapper{}
+	discoveryWrappers := []t.ServiceDiscoveryWrapper{}
+	seenWrappers := make(map[string]bool)
+	files := []*ast.File{}
+	registerFiles := []*ast.File{}
+	wrappers := []t.ServiceDiscoveryWrapper{}
+	grpcServices := make(map[string]string)
+	for i, file := range readable {
+		f, err := parser.ParseFile(file)
+		var syntaxErrors scanner.ErrorList
+		if errors.As(err, &syntaxErrors) {
+			for _, syntaxError := range syntaxErrors {
+				location := fmt.Sprintf("%s:%d", syntaxError.Pos.Filename, syntaxError.Pos.Line)
+				analysis.Diagnostics = append(analysis.Diagnostics, t.Diagnostic{Code: diag.GoSyntaxError, Severity: "error", Location: location, Message: syntaxError.Msg, Service: application})
+			}
+		} else if err != nil {
+			analysis.Diagnostics = append(analysis.Diagnostics, t.Diagnostic{Code: diag.UnreadableSource, Severity: "error", Location: file, Message: err.Error(), Service: application})
+		}
+		if f == nil {
+			continue
+		}
+		files = append(files, f)
+
+		discovered := parser.FindServiceDiscoveryWrappers(f)
+		wrappers = append(wrappers, discovered...)
+		// Only files calling the Nacos SDK can declare registration and discovery wrappers; registration parameters
+		// may also be passed to a helper outside the analysed source
+		registers := strings.Contains(contents[i], "RegisterInstanceParam{")
+		for _, funcName := range nacosFunctions {
+			if strings.Contains(contents[i], funcName+"(") {
+				registers = true
+				for _, instance := range discovered {
+					if key := fmt.Sprintf("discovery\x00%#v", instance); !seenWrappers[key] {
+						seenWrappers[key] = true
+						discoveryWrappers = append(discoveryWrappers, instance)
+					}
+				}
+				break
+			}
+		}
+		if registers {
+			registerFiles = append(registerFiles, f)
+		}
+		for short, full := range parser.FindGRPCServiceNames(f) {
+			grpcServices[short] = full
+		}
+		analysis.Configs = append(analysis.Configs, parser.FindConfigAccesses(f)...)
+	}
+
+	analysis.Registrations = make(map[string][]t.ServiceInfo)
+	var values map[string]string
+	for _, config := range analysis.Configs {
+		content, ok := snapshot[config.DataId+"@@"+config.Group]
+		if !ok || config.Access != "read" {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		for key, value := range util.ParseConfigValues(content) {
+			values[key] = value
+		}
+	}
+
+	// Registration wrappers are found once the configuration values are known, as they resolve the computed fields
+	for _, f := range registerFiles {
+		for _, instance := range parser.FindRegisterInstanceWrappers(f, values) {
+			if key := fmt.Sprintf("register\x00%#v", instance); !seenWrappers[key] {
+				seenWrappers[key] = true
+				registerWrappers = append(registerWrappers, instance)
+			}
+		}
+	}
+
+	for _, f := range files {
+		for _, wrapper := range registerWrappers {
+			names, infos := parser.FindRegisterInstanceWrapperInvocations(f, wrapper, application)
+			for i, name := range names {
+				if !util.ContainsServiceInfo(analysis.Registrations[name], infos[i]) {
+					analysis.Registrations[name] = append(analysis.Registrations[name], infos[i])
+				}
+			}
+		}
+		for _, wrapper := range discoveryWrappers {
+			names, locations, groups := parser.FindSelectInstanceWrappersInvocations(f, wrapper, application)
+			for i, name := range names {
+				analysis.Discoveries = append(analysis.Discoveries, t.DiscoveryCall{ServiceName: name, Method: wrapper.Method, Location: locations[i], Group: groups[i]})
+			}
+		}
+		analysis.ClientCalls = append(analysis.ClientCalls, parser.FindHTTPClientCalls(f, wrappers, values)...)
+		analysis.ClientCalls = append(analysis.ClientCalls, parser.FindGRPCClientCalls(f, wrappers, grpcServices, values)...)
+		analysis.ClientCalls = append(analysis.ClientCalls, parser.FindDatastoreClients(f, values)...)
+		analysis.ClientCalls = append(analysis.ClientCalls, parser.FindNacosServers(f, values)...)
+		analysis.ClientConfigs = append(analysis.ClientConfigs, parser.FindNacosClientConfigs(f, values)...)
+		analysis.Listeners = append(analysis.Listeners, parser.FindListeners(f, values)...)
+	}
+
+	if cacheDir != "" && len(diagnostics) == 0 {
+		if err := f_util.WriteAnalysisCache(cacheDir, analysis); err != nil {
+			fmt.Fprintf(logOut, "Error caching analysis of %s: %v\n", application, err)
+		}
+	}
+	return analysis
+}
+
+func analyseApplications(applicationFolders map[string]string, snapshotDir string, cacheDir string, jobs int) map[string]t.ApplicationAnalysis {
+	// analyseApplications analyses the source of every application concurrently with a bounded pool of workers.
+	//
+	// applicationFolders: A map where the keys are the names of the applications and the values are the corresponding folder paths.
+	// snapshotDir: The directory holding the config snapshot, see f_util.ReadConfigSnapshot. Empty if there is none.
+	// cacheDir: The directory of the analysis cache. Empty if the cache is disabled.
+	// jobs: The number of applications to analyse at once.
+	//
+	// Returns:
+	// A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// The diagnostics of every analysis, and an unreadable snapshot, are reported to diagnosticsCollector.
+
+	snapshot := make(map[string]string)
+	if snapshotDir != "" {
+		var err error
+		if snapshot, err = f_util.ReadConfigSnapshot(snapshotDir); err != nil {
+			diagnosticsCollector.Add(t.Diagnostic{Code: diag.UnreadableConfigSource, Severity: "error", Location: snapshotDir, Message: err.Error()})
+			snapshot = make(map[string]string)
+		}
+	}
+
+	applications := make(chan string)
+	analyses := make(map[string]t.ApplicationAnalysis)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < max(jobs, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for application := range applications {
+				analysis := analyseApplication(application, applicationFolders[application], snapshot, cacheDir)
+				diagnosticsCollector.Add(analysis.Diagnostics...)
+				mu.Lock()
+				analyses[application] = analysis
+				mu.Unlock()
+			}
+		}()
+	}
+	for application := range applicationFolders {
+		applications <- application
+	}
+	close(applications)
+	wg.Wait()
+	return analyses
+}
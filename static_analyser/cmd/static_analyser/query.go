@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+)
+
+func printPaths(paths []t.GraphPath, asJSON bool, empty string) error {
+	// printPaths prints the result of a query.
+	//
+	// paths: The paths found by the query.
+	// asJSON: Whether to print the paths as JSON instead of text.
+	// empty: The message to print when no path was found.
+	//
+	// Returns:
+	// An error if the paths could not be converted to JSON.
+
+	if asJSON {
+		jsonData, err := json.MarshalIndent(paths, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal query result: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(paths) == 0 {
+		fmt.Println(empty)
+	}
+	for _, path := range paths {
+		fmt.Print(graph.ExplainPath(path))
+	}
+	return nil
+}
+
+func runQuery(args []string) error {
+	// runQuery implements the "query" subcommand, which answers reachability questions about the service graph.
+	//
+	// args: The command line arguments following the subcommand name. After the flags comes one of
+	//   reach <service>            every service reachable from <service>, transitively
+	//   path <from> <to>           the shortest path from <from> to <to>
+	//   callers <service>          the services that call <service>; add -transitive for indirect callers
+	//
+	// Returns:
+	// An error if the arguments are invalid or the analysis fails.
+
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	transitive := flags.Bool("transitive", false, "include indirect callers in a callers query")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	query := flags.Args()
+	arity := map[string]int{"reach": 2, "path": 3, "callers": 2}
+	if len(query) == 0 || arity[query[0]] != len(query) {
+		return fmt.Errorf("usage: query [flags] reach <service> | path <from> <to> | callers <service>")
+	}
+
+	parsedYamls, manifests, err := analyse(*rootDir)
+	if err != nil {
+		return err
+	}
+	g := graph.BuildServiceGraph(parsedYamls, manifests)
+
+	known := make(map[string]bool)
+	for _, node := range g.Nodes {
+		known[node.ID] = true
+	}
+	for _, service := range query[1:] {
+		if !known[service] {
+			return fmt.Errorf("service %q is not part of the graph", service)
+		}
+	}
+
+	switch query[0] {
+	case "reach":
+		return printPaths(graph.FindReachable(g, query[1]), *asJSON, fmt.Sprintf("%s cannot reach any other service", query[1]))
+	case "callers":
+		return printPaths(graph.FindCallers(g, query[1], *transitive), *asJSON, fmt.Sprintf("no service calls %s", query[1]))
+	}
+
+	paths := []t.GraphPath{}
+	if path, ok := graph.FindShortestPath(g, query[1], query[2]); ok {
+		paths = append(paths, path)
+	}
+	return printPaths(paths, *asJSON, fmt.Sprintf("%s cannot reach %s", query[1], query[2]))
+}
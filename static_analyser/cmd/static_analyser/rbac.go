@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"static_analyser/pkg/nacos_auth"
+)
+
+func runRBAC(args []string) error {
+	// runRBAC implements the "rbac" subcommand, which generates the Nacos users, roles and permissions that limit each
+	// application to the services it registers and discovers and the configurations it reads and writes.
+	//
+	// args: The command line arguments following the subcommand name.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the analysis fails or the output could not be written.
+
+	flags := flag.NewFlagSet("rbac", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	namespace := flags.String("namespace", "public", "namespace of the applications whose Nacos client reads it at runtime")
+	format := flags.String("format", "json", "output format: json, or script for a shell script calling the Nacos auth Open API")
+	output := flags.String("o", "", "file to write the configuration to (default stdout)")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "script" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	_, _, applicationFolders, err := parseYamlFiles(*rootDir)
+	if err != nil {
+		return fmt.Errorf("error walking the file tree: %v", err)
+	}
+	analyses := analyseApplications(applicationFolders, configSnapshot, cacheDir, jobs)
+	serviceDirectory := processServiceRegistrationCalls(analyses, applicationFolders)
+
+	config, diagnostics := nacos_auth.GenerateAuthConfig(analyses, serviceDirectory, applicationFolders, *namespace)
+	diagnosticsCollector.Add(diagnostics...)
+	fmt.Fprintf(logOut, "Generated %d users and %d permissions\n", len(config.Users), len(config.Permissions))
+
+	var rendered string
+	if *format == "script" {
+		rendered = nacos_auth.RenderAuthScript(config)
+	} else {
+		jsonData, err := json.MarshalIndent(config, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal Nacos auth configuration: %w", err)
+		}
+		rendered = string(jsonData) + "\n"
+	}
+
+	if *output == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write Nacos auth configuration to file '%s': %w", *output, err)
+	}
+	return nil
+}
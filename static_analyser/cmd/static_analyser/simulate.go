@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	f_util "static_analyser/pkg/fileUtils"
+	"static_analyser/pkg/graph"
+	"static_analyser/pkg/policy"
+	t "static_analyser/pkg/types"
+)
+
+func runSimulate(args []string) error {
+	// runSimulate implements the "simulate" subcommand, which evaluates NetworkPolicies against the service graph
+	// to check that they allow every edge before they are applied.
+	//
+	// args: The command line arguments following the subcommand name. After the flags come the files or directories
+	// holding the NetworkPolicies; without any, the policies generated from the manifests are evaluated.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the analysis failed, the policies could not be read,
+	// or the policies block an edge or hold over-permissive rules.
+
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	parsedYamls, manifests, err := analyse(*rootDir)
+	if err != nil {
+		return err
+	}
+
+	var policies []t.NetworkPolicy
+	if flags.NArg() == 0 {
+		policies = policy.GenerateNetworkPolicies(parsedYamls, manifests)
+	} else if policies, err = f_util.ReadNetworkPolicies(flags.Args()); err != nil {
+		return err
+	}
+
+	report := policy.EvaluatePolicies(parsedYamls, findKubernetesNamespaces(*rootDir), policies, graph.BuildServiceGraph(parsedYamls, manifests))
+	if *asJSON {
+		jsonData, err := json.MarshalIndent(report, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal simulation report: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		fmt.Print(policy.RenderSimulationReport(report))
+	}
+
+	blocked := 0
+	for _, verdict := range report.Verdicts {
+		if verdict.Verdict == "blocked" {
+			blocked++
+		}
+	}
+	if blocked > 0 || len(report.Permissive) > 0 {
+		return fmt.Errorf("policies block %d edges and hold %d over-permissive rules", blocked, len(report.Permissive))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	f_util "static_analyser/pkg/fileUtils"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/validate"
+	"strings"
+)
+
+func runValidate(args []string) error {
+	// runValidate implements the "validate" subcommand, which compares the registrations predicted by static analysis
+	// with those of a Nacos registry.
+	//
+	// args: The command line arguments following the subcommand name. After the flags comes the registry: the URL of a
+	// Nacos server, a JSON dump written by the fake-nacos command, or a directory holding SDK naming cache files.
+	//
+	// Returns:
+	// An error if the arguments are invalid, the analysis failed or the registry could not be read.
+
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	rootDir := flags.String("root", root, "root directory of the applications to analyse")
+	namespace := flags.String("namespace", "public", "namespace to query on a Nacos server")
+	group := flags.String("group", "DEFAULT_GROUP", "group to query on a Nacos server")
+	username := flags.String("username", "", "user to log in to a Nacos server as")
+	password := flags.String("password", "", "password of -username")
+	asJSON := flags.Bool("json", false, "print the findings as JSON")
+	addAnalysisFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: validate [flags] <server URL | dump.json | cache directory>")
+	}
+
+	_, _, applicationFolders, err := parseYamlFiles(*rootDir)
+	if err != nil {
+		return fmt.Errorf("error walking the file tree: %v", err)
+	}
+	analyses := analyseApplications(applicationFolders, configSnapshot, cacheDir, jobs)
+	serviceDirectory := processServiceRegistrationCalls(analyses, applicationFolders)
+
+	var instances []t.NacosInstance
+	source := flags.Arg(0)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		names := []string{}
+		for name := range serviceDirectory {
+			names = append(names, name)
+		}
+		if instances, err = validate.QueryNacosServer(source, *namespace, *group, names, *username, *password); err != nil {
+			return err
+		}
+	} else if info, err := os.Stat(source); err != nil {
+		return fmt.Errorf("failed to read registry '%s': %w", source, err)
+	} else if info.IsDir() {
+		if instances, err = f_util.ReadNamingCache(source); err != nil {
+			return err
+		}
+	} else {
+		snapshot, err := f_util.ReadNacosSnapshot(source)
+		if err != nil {
+			return err
+		}
+		instances = snapshot.Instances
+	}
+
+	findings := validate.ValidateRegistrations(serviceDirectory, instances)
+	if *asJSON {
+		jsonData, err := json.MarshalIndent(findings, "", " ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal findings: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+	fmt.Print(validate.RenderRegistryFindings(findings))
+	return nil
+}
@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"static_analyser/pkg/parser"
+	t "static_analyser/pkg/types"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer checks the use of the Nacos SDK against the rules of Rules.go, such as discoveries of services no workload
+// registers. It follows registration and discovery wrappers and functions calling the SDK across packages with facts,
+// and records the services each package registers and discovers.
+var Analyzer = &analysis.Analyzer{
+	Name:      "nacoslint",
+	Doc:       "check the use of the Nacos SDK, such as registrations never deregistered and discoveries of services no workload registers",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(RegisterWrapperFact), new(DiscoveryWrapperFact), new(NacosCallsFact), new(ServicesFact)},
+}
+
+// root is the directory holding the sources of every workload. Unregistered discoveries are only reported if it is set,
+// since the workloads registering a service are rarely dependencies of the package discovering it.
+var root string
+
+// enable and disable are the rules to check and not to check, see enabledRules
+var enable, disable ruleList
+
+// registeredServices caches FindRegisteredServices, as a run analyses many packages against the same root.
+var registeredServices = struct {
+	sync.Mutex
+	byRoot map[string]map[string][]string
+}{byRoot: make(map[string]map[string][]string)}
+
+func init() {
+	Analyzer.Flags.StringVar(&root, "root", "", "directory holding the sources of every workload; discoveries of services no workload below it registers are reported")
+	Analyzer.Flags.Var(&enable, "enable", "comma separated rules to check, such as NACOS001,NACOS004; all rules if empty")
+	Analyzer.Flags.Var(&disable, "disable", "comma separated rules not to check")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// run analyses one package: it marks the wrappers declared in the package and its functions calling the SDK with
+	// facts, checks the enabled rules, resolves the services registered and discovered by calls to any known wrapper,
+	// and reports discoveries no workload registers.
+	//
+	// pass: The analysis pass of the package.
+	//
+	// Returns:
+	// Nothing, as the results are shared with facts.
+	// An error if the sources below root could not be searched.
+
+	enabled := enabledRules(enable, disable)
+	// The detectors name wrappers after their function, so the declarations are looked up by name
+	for _, f := range pass.Files {
+		registerWrappers := parser.FindRegisterInstanceWrappers(f, nil)
+		discoveryWrappers := parser.FindServiceDiscoveryWrappers(f)
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			obj := pass.TypesInfo.Defs[fn.Name]
+			if obj == nil {
+				continue
+			}
+			// A function calling the SDK several times is a wrapper of each call
+			registerFact := RegisterWrapperFact{}
+			for _, wrapper := range registerWrappers {
+				if wrapper.Wrapper == fn.Name.Name {
+					registerFact.Wrappers = append(registerFact.Wrappers, wrapper)
+				}
+			}
+			if len(registerFact.Wrappers) > 0 {
+				pass.ExportObjectFact(obj, &registerFact)
+			}
+			discoveryFact := DiscoveryWrapperFact{}
+			for _, wrapper := range discoveryWrappers {
+				if wrapper.Wrapper == fn.Name.Name {
+					discoveryFact.Wrappers = append(discoveryFact.Wrappers, wrapper)
+				}
+			}
+			if len(discoveryFact.Wrappers) > 0 {
+				pass.ExportObjectFact(obj, &discoveryFact)
+			}
+		}
+	}
+
+	exportCallFacts(pass)
+	checkDeregistration(pass, enabled, unreachable(pass))
+	checkDeregisterParams(pass, enabled)
+	checkRetryLoops(pass, enabled)
+	checkSuccessResults(pass, enabled)
+	checkFirstInstance(pass, enabled)
+	checkFailedCalls(pass, enabled)
+
+	registered := make(map[string]bool)
+	discovered := make(map[string]bool)
+	type discovery struct {
+		call    *ast.CallExpr
+		wrapper string
+		service string
+	}
+	discoveries := []discovery{}
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			// The function a call is made in resolves the variables it passes, such as names drawn from a slice literal
+			scope, _ := decl.(*ast.FuncDecl)
+			ast.Inspect(decl, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+				if !ok {
+					return true
+				}
+				// The detectors match calls by the name of the wrapper, which is not the callee of a qualified or method call
+				named := *call
+				named.Fun = ast.NewIdent(fn.Name())
+				var registerFact RegisterWrapperFact
+				if pass.ImportObjectFact(fn, &registerFact) {
+					for _, wrapper := range registerFact.Wrappers {
+						if !resolvable(call, wrapper.ServiceName, wrapper.IP, wrapper.Port) {
+							continue
+						}
+						names, _ := parser.FindRegisterInstanceWrapperInvocations(&named, wrapper, pass.Pkg.Path())
+						for _, name := range enumeratedNames(names, wrapper.ServiceName, call, f, scope) {
+							registered[name] = true
+						}
+					}
+				}
+				var discoveryFact DiscoveryWrapperFact
+				if pass.ImportObjectFact(fn, &discoveryFact) {
+					for _, wrapper := range discoveryFact.Wrappers {
+						if !resolvable(call, wrapper.ServiceName) {
+							continue
+						}
+						names, _, _ := parser.FindSelectInstanceWrappersInvocations(&named, wrapper, pass.Pkg.Path())
+						for _, name := range enumeratedNames(names, wrapper.ServiceName, call, f, scope) {
+							discovered[name] = true
+							discoveries = append(discoveries, discovery{call: call, wrapper: fn.Name(), service: name})
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	if len(registered) > 0 || len(discovered) > 0 {
+		pass.ExportPackageFact(&ServicesFact{Registered: sortedKeys(registered), Discovered: sortedKeys(discovered)})
+	}
+	if root == "" || len(discoveries) == 0 || !enabled[UnregisteredDiscovery] {
+		return nil, nil
+	}
+
+	// Services registered by the package, its dependencies or any workload below root are known
+	for _, fact := range pass.AllPackageFacts() {
+		if services, ok := fact.Fact.(*ServicesFact); ok {
+			for _, name := range services.Registered {
+				registered[name] = true
+			}
+		}
+	}
+	registeredServices.Lock()
+	workloads, ok := registeredServices.byRoot[root]
+	if !ok {
+		var err error
+		if workloads, err = FindRegisteredServices(root); err != nil {
+			registeredServices.Unlock()
+			return nil, err
+		}
+		registeredServices.byRoot[root] = workloads
+	}
+	registeredServices.Unlock()
+	for _, d := range discoveries {
+		if !registered[d.service] && len(workloads[d.service]) == 0 {
+			report(pass, enabled, UnregisteredDiscovery, d.call, "%s will discover %s but no workload registers it", d.wrapper, d.service)
+		}
+	}
+	return nil, nil
+}
+
+func resolvable(call *ast.CallExpr, fields ...interface{}) bool {
+	// resolvable checks that a call passes every argument a wrapper takes the fields of an instance from.
+	//
+	// call: The call of the wrapper.
+	// fields: The fields of the wrapper, each a string or a t.WrapperParams.
+	//
+	// Returns:
+	// False if a field is a parameter the call does not pass, as with a variadic wrapper, true otherwise.
+
+	for _, field := range fields {
+		if param, ok := field.(t.WrapperParams); ok && param.Position >= len(call.Args) {
+			return false
+		}
+	}
+	return true
+}
+
+func enumeratedNames(names []string, field interface{}, call *ast.CallExpr, f *ast.File, scope *ast.FuncDecl) []string {
+	// enumeratedNames works out the services a call of a wrapper registers or discovers. The detectors only see the
+	// call, so a name the call passes in a variable drawn from a finite set is enumerated in the function around it.
+	//
+	// names: The names the detectors resolved for the call.
+	// field: The ServiceName field of the wrapper, a string, the strings of a finite set or a t.WrapperParams.
+	// call: The call of the wrapper.
+	// f: The file holding the call.
+	// scope: The function the call is made in, nil for calls at the top level.
+	//
+	// Returns:
+	// Every name, in order, without those that are not known statically.
+
+	if param, ok := field.(t.WrapperParams); ok && param.Position < len(call.Args) {
+		if values := parser.EnumerateValues(f, scope, call.Args[param.Position]); values != nil {
+			names = values
+		}
+	}
+	known := []string{}
+	for _, name := range names {
+		if name != "" && name != "nil" {
+			known = append(known, name)
+		}
+	}
+	return known
+}
+
+func sortedKeys(set map[string]bool) []string {
+	// sortedKeys lists the members of a set.
+	//
+	// set: The set.
+	//
+	// Returns:
+	// The keys of the set, sorted.
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func withRules(test *testing.T, enabled ruleList, disabled ruleList, workloads string) {
+	// withRules sets the -enable, -disable and -root flags of the analyzer for the rest of a test.
+	//
+	// test: The test.
+	// enabled: The rules to check, or none for all of them.
+	// disabled: The rules not to check.
+	// workloads: The directory holding the sources of every workload, or empty.
+
+	enable, disable, root = enabled, disabled, workloads
+	test.Cleanup(func() {
+		enable, disable, root = nil, nil, ""
+	})
+}
+
+func TestRules(test *testing.T) {
+	// TestRules checks the findings of each rule, and the facts the analyzer records on the way, against the
+	// "// want" comments of the testdata packages. Each package is checked with its rule alone.
+	//
+	// test: The test.
+
+	cases := []struct {
+		rule     string
+		packages []string
+	}{
+		{RegisterWithoutDeregister, []string{"nacos001/missing", "nacos001/dead", "nacos001/deregistered"}},
+		{DeregisterMismatch, []string{"nacos002"}},
+		{BusyRetryLoop, []string{"nacos003"}},
+		{IgnoredSuccess, []string{"nacos004"}},
+		{FirstInstance, []string{"nacos005"}},
+		{ContinueAfterFailure, []string{"nacos006"}},
+		{UnregisteredDiscovery, []string{"nacos007"}},
+	}
+	for _, c := range cases {
+		test.Run(c.rule, func(test *testing.T) {
+			withRules(test, ruleList{c.rule}, nil, filepath.Join(analysistest.TestData(), "workloads"))
+			analysistest.Run(test, analysistest.TestData(), Analyzer, c.packages...)
+		})
+	}
+}
+
+func TestCrossPackageWrappers(test *testing.T) {
+	// TestCrossPackageWrappers checks that wrappers declared in another package are followed through their facts, both
+	// for the services a package registers and discovers and for registrations never deregistered.
+	//
+	// test: The test.
+
+	withRules(test, ruleList{RegisterWithoutDeregister}, nil, "")
+	analysistest.Run(test, analysistest.TestData(), Analyzer, "crosspackage", "registry")
+}
+
+func TestEnumeratedNames(test *testing.T) {
+	// TestEnumeratedNames checks that every service a call of a wrapper registers or discovers is recorded when the
+	// name is drawn from a finite set, whether the call passes it or the wrapper enumerates it itself.
+	//
+	// test: The test.
+
+	withRules(test, ruleList{UnregisteredDiscovery}, nil, "")
+	analysistest.Run(test, analysistest.TestData(), Analyzer, "enumerated")
+}
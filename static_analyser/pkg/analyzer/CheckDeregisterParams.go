@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func checkDeregisterParams(pass *analysis.Pass, enabled map[string]bool) {
+	// checkDeregisterParams reports the fields of DeregisterInstanceParam literals that do not match the
+	// RegisterInstanceParam of the same package (NACOS002): constants that differ, and constants hard-coded where the
+	// registration computes the value. A deregistration is compared with the registration of the same service name, or
+	// with the only registration of the package.
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	paramsOf := func(lit *ast.CompositeLit) map[string]ast.Expr {
+		// paramsOf maps the keys of a struct literal to their values.
+		//
+		// lit: The struct literal.
+		//
+		// Returns:
+		// A map where the keys are the field names and the values are their expressions.
+
+		params := make(map[string]ast.Expr)
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					params[key.Name] = kv.Value
+				}
+			}
+		}
+		return params
+	}
+
+	registers := []map[string]ast.Expr{}
+	deregisters := []*ast.CompositeLit{}
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			named, ok := types.Unalias(pass.TypesInfo.TypeOf(lit)).(*types.Named)
+			if !ok || !isSDKPackage(named.Obj().Pkg()) {
+				return true
+			}
+			switch named.Obj().Name() {
+			case "RegisterInstanceParam":
+				// Literals filled in later, such as an empty one passed to a helper, cannot be compared
+				if params := paramsOf(lit); params["ServiceName"] != nil {
+					registers = append(registers, params)
+				}
+			case "DeregisterInstanceParam":
+				deregisters = append(deregisters, lit)
+			}
+			return true
+		})
+	}
+
+	// Missing fields take the defaults of the SDK; the cluster field is named differently in the two structs
+	fields := []struct{ deregister, register, fallback string }{
+		{"Ip", "Ip", ""}, {"Port", "Port", ""}, {"ServiceName", "ServiceName", ""},
+		{"GroupName", "GroupName", "DEFAULT_GROUP"}, {"Cluster", "ClusterName", "DEFAULT"},
+	}
+	valueOf := func(expr ast.Expr, fallback string) constant.Value {
+		// valueOf works out the constant value of a field.
+		//
+		// expr: The expression of the field, nil if it is missing.
+		// fallback: The default of the field, empty if it has none.
+		//
+		// Returns:
+		// The constant value of the field, or nil if it is not constant.
+
+		if expr == nil {
+			if fallback == "" {
+				return nil
+			}
+			return constant.MakeString(fallback)
+		}
+		return pass.TypesInfo.Types[expr].Value
+	}
+
+	for _, lit := range deregisters {
+		deregister := paramsOf(lit)
+		var register map[string]ast.Expr
+		name := valueOf(deregister["ServiceName"], "")
+		for _, candidate := range registers {
+			if other := valueOf(candidate["ServiceName"], ""); name != nil && other != nil && constant.Compare(name, token.EQL, other) {
+				register = candidate
+			}
+		}
+		if register == nil && len(registers) == 1 {
+			register = registers[0]
+		}
+		if register == nil {
+			continue
+		}
+		for _, field := range fields {
+			dExpr, rExpr := deregister[field.deregister], register[field.register]
+			if field.fallback == "" && (dExpr == nil || rExpr == nil) {
+				continue
+			}
+			var at ast.Node = lit
+			if dExpr != nil {
+				at = dExpr
+			}
+			dValue, rValue := valueOf(dExpr, field.fallback), valueOf(rExpr, field.fallback)
+			switch {
+			case dValue != nil && rValue != nil && (dValue.Kind() != rValue.Kind() || !constant.Compare(dValue, token.EQL, rValue)):
+				report(pass, enabled, DeregisterMismatch, at, "DeregisterInstance %s is %s but the instance is registered with %s %s", field.deregister, dValue.ExactString(), field.register, rValue.ExactString())
+			case dValue != nil && rValue == nil:
+				report(pass, enabled, DeregisterMismatch, at, "DeregisterInstance %s is hard-coded to %s but the instance is registered with %s %s", field.deregister, dValue.ExactString(), field.register, types.ExprString(rExpr))
+			}
+		}
+	}
+}
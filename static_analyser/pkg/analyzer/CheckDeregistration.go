@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func checkDeregistration(pass *analysis.Pass, enabled map[string]bool, dead func(pos token.Pos) bool) {
+	// checkDeregistration reports the registrations of a main package that are never deregistered (NACOS001). A
+	// deregistration only counts if it is reachable from main or init. Libraries are not checked, as their callers
+	// deregister through them; registrations through a library are reported where the main package calls it.
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check.
+	// dead: Checks if a position is unreachable, see unreachable.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	if pass.Pkg.Name() != "main" {
+		return
+	}
+	registrations := []*ast.CallExpr{}
+	bodies := make(map[*types.Func]*ast.BlockStmt)
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+					bodies[obj] = fn.Body
+				}
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			// Registrations through functions of the package are reported where they call the SDK
+			fn := callee(pass, call)
+			if fn != nil && (isSDK(fn) || fn.Pkg() != pass.Pkg) && calls(pass, fn, "RegisterInstance", "BatchRegisterInstance") {
+				registrations = append(registrations, call)
+			}
+			return true
+		})
+	}
+	if len(registrations) == 0 {
+		return
+	}
+
+	deregistered := false
+	deadOnly := false
+	queue := []*ast.BlockStmt{}
+	visited := make(map[*types.Func]bool)
+	for fn, body := range bodies {
+		sig := fn.Type().(*types.Signature)
+		if sig.Recv() == nil && (fn.Name() == "main" || fn.Name() == "init") {
+			queue = append(queue, body)
+			visited[fn] = true
+		}
+	}
+	for len(queue) > 0 && !deregistered {
+		body := queue[0]
+		queue = queue[1:]
+		ast.Inspect(body, func(n ast.Node) bool {
+			ident, ok := n.(*ast.Ident)
+			if !ok {
+				return !deregistered
+			}
+			fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+			if !ok {
+				return true
+			}
+			// Functions are followed wherever they are referenced, as they may be deferred or handed to a signal handler
+			if dead(ident.Pos()) {
+				deadOnly = deadOnly || calls(pass, fn, "DeregisterInstance")
+			} else if _, local := bodies[fn]; local {
+				if !visited[fn] {
+					visited[fn] = true
+					queue = append(queue, bodies[fn])
+				}
+			} else if calls(pass, fn, "DeregisterInstance") {
+				deregistered = true
+			}
+			return !deregistered
+		})
+	}
+	if deregistered {
+		return
+	}
+	for _, call := range registrations {
+		if deadOnly {
+			report(pass, enabled, RegisterWithoutDeregister, call, "the instance registered here is never deregistered: DeregisterInstance is only called after a statement that never returns, such as log.Fatal")
+		} else {
+			report(pass, enabled, RegisterWithoutDeregister, call, "the instance registered here is never deregistered; call DeregisterInstance on shutdown, such as on SIGTERM, so that consumers stop discovering it")
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func checkFailedCalls(pass *analysis.Pass, enabled map[string]bool) {
+	// checkFailedCalls reports error checks of Nacos calls, direct or through functions calling the SDK, whose branch
+	// carries on as if the call had succeeded, such as one that only logs the error (NACOS006).
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	errorType := types.Universe.Lookup("error").Type()
+	checkList := func(list []ast.Stmt) {
+		// checkList reports the error checks of a statement list that follow a Nacos call and do not terminate.
+		//
+		// list: The statements of a block or a case.
+		//
+		// Returns:
+		// This function doesn't return a value.
+
+		for i := 0; i+1 < len(list); i++ {
+			assign, ok := list[i].(*ast.AssignStmt)
+			if !ok || len(assign.Rhs) != 1 {
+				continue
+			}
+			call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			fn := callee(pass, call)
+			if len(nacosMethods(pass, fn)) == 0 {
+				continue
+			}
+			errIdent, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident)
+			if !ok {
+				continue
+			}
+			errObj := pass.TypesInfo.ObjectOf(errIdent)
+			if errObj == nil || !types.Identical(errObj.Type(), errorType) {
+				continue
+			}
+			check, ok := list[i+1].(*ast.IfStmt)
+			if !ok || check.Init != nil || check.Else != nil || terminates(pass, check.Body) {
+				continue
+			}
+			cond, ok := check.Cond.(*ast.BinaryExpr)
+			if !ok || cond.Op != token.NEQ {
+				continue
+			}
+			if x, ok := cond.X.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(x) == errObj && pass.TypesInfo.Types[cond.Y].IsNil() {
+				report(pass, enabled, ContinueAfterFailure, check, "execution continues after %s fails; return, retry or exit in this branch", fn.Name())
+			}
+		}
+	}
+
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt:
+				checkList(n.List)
+			case *ast.CaseClause:
+				checkList(n.Body)
+			case *ast.CommClause:
+				checkList(n.Body)
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func checkFirstInstance(pass *analysis.Pass, enabled map[string]bool) {
+	// checkFirstInstance reports the first element of a slice of discovered instances being taken, such as
+	// instances[0] after SelectInstances or in a Subscribe callback, instead of a weighted selection (NACOS005).
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			index, ok := n.(*ast.IndexExpr)
+			if !ok {
+				return true
+			}
+			value := pass.TypesInfo.Types[index.Index].Value
+			if value == nil || value.Kind() != constant.Int || constant.Sign(value) != 0 {
+				return true
+			}
+			slice, ok := pass.TypesInfo.TypeOf(index.X).Underlying().(*types.Slice)
+			if !ok {
+				return true
+			}
+			elem := slice.Elem()
+			if pointer, ok := elem.(*types.Pointer); ok {
+				elem = pointer.Elem()
+			}
+			if named, ok := types.Unalias(elem).(*types.Named); ok && named.Obj().Name() == "Instance" && isSDKPackage(named.Obj().Pkg()) {
+				report(pass, enabled, FirstInstance, index, "%s always uses the first instance; use SelectOneHealthyInstance to balance the load across healthy instances by weight", types.ExprString(index))
+			}
+			return true
+		})
+	}
+}
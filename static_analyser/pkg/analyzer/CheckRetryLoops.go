@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"static_analyser/pkg/util"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// waits are the functions that pause a loop between attempts
+var waits = []string{"time.Sleep", "time.After", "time.Tick", "time.NewTimer", "time.NewTicker"}
+
+func checkRetryLoops(pass *analysis.Pass, enabled map[string]bool) {
+	// checkRetryLoops reports loops that retry a Nacos call without waiting between attempts (NACOS003). Only loops
+	// without an init or post statement, such as "for {" and "for err != nil {", are retry loops; a loop waits if it
+	// sleeps, receives from a channel, selects, or calls a function whose name mentions a backoff or a wait.
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			loop, ok := n.(*ast.ForStmt)
+			if !ok || loop.Init != nil || loop.Post != nil {
+				return true
+			}
+			retried := ""
+			waiting := false
+			visit := func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					// Closures run when they are called, not on every iteration
+					return false
+				case *ast.SelectStmt:
+					waiting = true
+				case *ast.UnaryExpr:
+					waiting = waiting || n.Op == token.ARROW
+				case *ast.CallExpr:
+					fn := callee(pass, n)
+					if fn == nil {
+						return true
+					}
+					name := strings.ToLower(fn.Name())
+					if util.Contains(waits, fn.FullName()) || strings.Contains(name, "backoff") || strings.Contains(name, "sleep") || strings.Contains(name, "wait") {
+						waiting = true
+					} else if retried == "" && len(nacosMethods(pass, fn)) > 0 {
+						retried = fn.Name()
+					}
+				}
+				return !waiting
+			}
+			if loop.Cond != nil {
+				ast.Inspect(loop.Cond, visit)
+			}
+			ast.Inspect(loop.Body, visit)
+			if retried != "" && !waiting {
+				report(pass, enabled, BusyRetryLoop, loop, "%s is retried in a loop without waiting between attempts; sleep with a backoff before retrying so that a failing Nacos server is not flooded", retried)
+			}
+			return true
+		})
+	}
+}
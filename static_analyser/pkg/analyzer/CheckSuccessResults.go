@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+func checkSuccessResults(pass *analysis.Pass, enabled map[string]bool) {
+	// checkSuccessResults reports calls of Nacos SDK functions returning a success bool, such as RegisterInstance,
+	// whose bool is discarded (NACOS004).
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	check := func(expr ast.Expr) {
+		// check reports a call whose results are discarded, if it returns a success bool.
+		//
+		// expr: The expression whose first result is discarded.
+		//
+		// Returns:
+		// This function doesn't return a value.
+
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fn := callee(pass, call)
+		if !isSDK(fn) {
+			return
+		}
+		results := fn.Type().(*types.Signature).Results()
+		if results.Len() == 0 {
+			return
+		}
+		if basic, ok := results.At(0).Type().Underlying().(*types.Basic); ok && basic.Kind() == types.Bool {
+			report(pass, enabled, IgnoredSuccess, call, "the success result of %s is ignored; it can be false without an error, so check it as well", fn.Name())
+		}
+	}
+	blank := func(expr ast.Expr) bool {
+		// blank checks if an expression is the blank identifier.
+		//
+		// expr: The expression.
+		//
+		// Returns:
+		// True if the expression is "_", false otherwise.
+
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == "_"
+	}
+
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ExprStmt:
+				check(n.X)
+			case *ast.GoStmt:
+				check(n.Call)
+			case *ast.DeferStmt:
+				check(n.Call)
+			case *ast.AssignStmt:
+				if len(n.Rhs) == 1 && len(n.Lhs) > 0 && blank(n.Lhs[0]) {
+					check(n.Rhs[0])
+				}
+			case *ast.ValueSpec:
+				if len(n.Values) == 1 && len(n.Names) > 0 && blank(n.Names[0]) {
+					check(n.Values[0])
+				}
+			}
+			return true
+		})
+	}
+}
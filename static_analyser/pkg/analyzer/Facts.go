@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"encoding/gob"
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+// RegisterWrapperFact marks a function that registers a Nacos instance, so that its callers in other packages are followed.
+type RegisterWrapperFact struct {
+	Wrappers []t.RegisterInstanceWrapper // Wrappers map the parameters of the function to each instance it registers.
+}
+
+// DiscoveryWrapperFact marks a function that discovers a Nacos service, so that its callers in other packages are followed.
+type DiscoveryWrapperFact struct {
+	Wrappers []t.ServiceDiscoveryWrapper // Wrappers map the parameters of the function to each service it discovers.
+}
+
+// NacosCallsFact marks a function that calls the Nacos SDK, directly or through other functions.
+type NacosCallsFact struct {
+	Methods []string // Methods are the names of the SDK functions the function ends up in, sorted.
+}
+
+// ServicesFact records the services a package registers and discovers.
+type ServicesFact struct {
+	Registered []string // Registered are the names of the registered services, sorted.
+	Discovered []string // Discovered are the names of the discovered services, sorted.
+}
+
+func init() {
+	// Wrapper parameters are held in interface fields, which gob only encodes for registered types
+	gob.Register(t.WrapperParams{})
+}
+
+func (*RegisterWrapperFact) AFact() {}
+
+func (f *RegisterWrapperFact) String() string {
+	described := []string{}
+	for _, wrapper := range f.Wrappers {
+		described = append(described, describe(wrapper.ServiceName))
+	}
+	return fmt.Sprintf("registers %s", strings.Join(described, ", "))
+}
+
+func (*DiscoveryWrapperFact) AFact() {}
+
+func (f *DiscoveryWrapperFact) String() string {
+	described := []string{}
+	for _, wrapper := range f.Wrappers {
+		described = append(described, fmt.Sprintf("%s with %s", describe(wrapper.ServiceName), wrapper.Method))
+	}
+	return fmt.Sprintf("discovers %s", strings.Join(described, ", "))
+}
+
+func (*NacosCallsFact) AFact() {}
+
+func (f *NacosCallsFact) String() string {
+	return fmt.Sprintf("calls [%s]", strings.Join(f.Methods, " "))
+}
+
+func (*ServicesFact) AFact() {}
+
+func (f *ServicesFact) String() string {
+	return fmt.Sprintf("registers [%s] discovers [%s]", strings.Join(f.Registered, " "), strings.Join(f.Discovered, " "))
+}
+
+func describe(field interface{}) string {
+	// describe formats a field of a wrapper for the facts printed by the -debug flag.
+	//
+	// field: The field, a string, the strings of a name drawn from a finite set or a t.WrapperParams.
+	//
+	// Returns:
+	// The value of the field, or the parameter it is taken from.
+
+	if param, ok := field.(t.WrapperParams); ok {
+		return fmt.Sprintf("parameter %d", param.Position)
+	}
+	return fmt.Sprintf("%q", field)
+}
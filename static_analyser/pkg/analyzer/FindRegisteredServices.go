@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"sort"
+	"static_analyser/pkg/file_finder"
+	"static_analyser/pkg/parser"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func FindRegisteredServices(root string) (map[string][]string, error) {
+	// FindRegisteredServices finds the services registered by every workload below a directory, the same way the
+	// static analyser does. Each Go module is a workload, and wrappers are only followed within the module declaring them.
+	// Vendored and testdata sources are skipped.
+	//
+	// root: The directory holding the sources of the workloads.
+	//
+	// Returns:
+	// A map where the keys are the names of the registered services and the values are the sorted folders of the
+	// workloads registering them.
+	// An error if the directory could not be searched.
+
+	moduleOf := func(file string) string {
+		// moduleOf finds the workload of a Go file.
+		//
+		// file: The path of the Go file.
+		//
+		// Returns:
+		// The folder of the nearest go.mod above the file, or root if there is none.
+
+		dir := filepath.Dir(file)
+		for dir != root && filepath.Dir(dir) != dir {
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				return dir
+			}
+			dir = filepath.Dir(dir)
+		}
+		return root
+	}
+
+	root = filepath.Clean(root)
+	goFiles, err := file_finder.FindGoFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	modules := make(map[string][]string)
+	for _, file := range goFiles {
+		// Only the folders below root are skipped, so that a root inside a testdata folder is still searched
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			rel = file
+		}
+		slashed := "/" + filepath.ToSlash(rel)
+		if strings.Contains(slashed, "/vendor/") || strings.Contains(slashed, "/testdata/") {
+			continue
+		}
+		module := moduleOf(file)
+		modules[module] = append(modules[module], file)
+	}
+
+	registered := make(map[string][]string)
+	for module, files := range modules {
+		parsed := []*ast.File{}
+		wrappers := []t.RegisterInstanceWrapper{}
+		seen := make(map[string]bool)
+		for _, file := range files {
+			// Files with syntax errors are analysed as far as they parse
+			f, _ := parser.ParseFile(file)
+			if f == nil {
+				continue
+			}
+			parsed = append(parsed, f)
+			for _, wrapper := range parser.FindRegisterInstanceWrappers(f, nil) {
+				// A function calling the SDK several times is a wrapper of each call
+				if key := fmt.Sprintf("%#v", wrapper); !seen[key] {
+					seen[key] = true
+					wrappers = append(wrappers, wrapper)
+				}
+			}
+		}
+		for _, f := range parsed {
+			for _, decl := range f.Decls {
+				scope, _ := decl.(*ast.FuncDecl)
+				ast.Inspect(decl, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					// Wrappers declared in another package of the module are called qualified, as in "nacos.Register(...)"
+					name := ""
+					switch fun := call.Fun.(type) {
+					case *ast.Ident:
+						name = fun.Name
+					case *ast.SelectorExpr:
+						name = fun.Sel.Name
+					}
+					for _, wrapper := range wrappers {
+						if wrapper.Wrapper != name || !resolvable(call, wrapper.ServiceName, wrapper.IP, wrapper.Port) {
+							continue
+						}
+						named := *call
+						named.Fun = ast.NewIdent(name)
+						names, _ := parser.FindRegisterInstanceWrapperInvocations(&named, wrapper, module)
+						for _, service := range enumeratedNames(names, wrapper.ServiceName, call, f, scope) {
+							if !util.Contains(registered[service], module) {
+								registered[service] = append(registered[service], module)
+							}
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	for _, modules := range registered {
+		sort.Strings(modules)
+	}
+	return registered, nil
+}
@@ -0,0 +1,26 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestFindRegisteredServices(test *testing.T) {
+	// TestFindRegisteredServices checks the services the workloads below a root register, with the root inside a
+	// testdata folder, which is only skipped below the root.
+	//
+	// test: The test.
+
+	workloads := filepath.Join(analysistest.TestData(), "workloads")
+	registered, err := FindRegisteredServices(workloads)
+	if err != nil {
+		test.Fatal(err)
+	}
+	want := map[string][]string{"orders": {filepath.Join(workloads, "orders")}}
+	if !reflect.DeepEqual(registered, want) {
+		test.Errorf("got %v, want %v", registered, want)
+	}
+}
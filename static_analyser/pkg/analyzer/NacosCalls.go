@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"static_analyser/pkg/util"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// sdkPrefix is the import path prefix of the Nacos Go SDK, of both its major versions
+const sdkPrefix = "github.com/nacos-group/nacos-sdk-go"
+
+// noReturn are the functions that never return to their caller
+var noReturn = []string{"os.Exit", "log.Fatal", "log.Fatalf", "log.Fatalln", "log.Panic", "log.Panicf", "log.Panicln",
+	"(*log.Logger).Fatal", "(*log.Logger).Fatalf", "(*log.Logger).Fatalln", "(*log.Logger).Panic", "(*log.Logger).Panicf",
+	"(*log.Logger).Panicln", "runtime.Goexit"}
+
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	// callee finds the function or method a call statically calls.
+	//
+	// pass: The analysis pass of the package.
+	// call: The call.
+	//
+	// Returns:
+	// The function, or nil for calls of builtins, conversions and function values.
+
+	fn, _ := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	return fn
+}
+
+func isSDK(fn *types.Func) bool {
+	// isSDK checks if a function or method belongs to the Nacos SDK, including the methods of its client interfaces.
+	//
+	// fn: The function.
+	//
+	// Returns:
+	// True if the function is declared by a package of the SDK, false otherwise.
+
+	return fn != nil && isSDKPackage(fn.Pkg())
+}
+
+func isSDKPackage(pkg *types.Package) bool {
+	// isSDKPackage checks if a package belongs to the Nacos SDK.
+	//
+	// pkg: The package, nil for objects of the universe scope.
+	//
+	// Returns:
+	// True if the import path of the package starts with that of the SDK, false otherwise.
+
+	return pkg != nil && strings.HasPrefix(pkg.Path(), sdkPrefix)
+}
+
+func nacosMethods(pass *analysis.Pass, fn *types.Func) []string {
+	// nacosMethods lists the Nacos SDK functions a call of a function ends up in.
+	//
+	// pass: The analysis pass of the package.
+	// fn: The called function.
+	//
+	// Returns:
+	// The name of the function if it belongs to the SDK, the methods of its NacosCallsFact otherwise, or nil if it does
+	// not call the SDK.
+
+	if fn == nil {
+		return nil
+	}
+	if isSDK(fn) {
+		return []string{fn.Name()}
+	}
+	var fact NacosCallsFact
+	if pass.ImportObjectFact(fn, &fact) {
+		return fact.Methods
+	}
+	return nil
+}
+
+func calls(pass *analysis.Pass, fn *types.Func, methods ...string) bool {
+	// calls checks if a call of a function ends up in one of the given Nacos SDK functions.
+	//
+	// pass: The analysis pass of the package.
+	// fn: The called function.
+	// methods: The names of the SDK functions.
+	//
+	// Returns:
+	// True if the function is, or calls, one of the SDK functions, false otherwise.
+
+	for _, method := range nacosMethods(pass, fn) {
+		if util.Contains(methods, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func exportCallFacts(pass *analysis.Pass) {
+	// exportCallFacts marks every function of the package that calls the Nacos SDK, directly or through other functions,
+	// with a NacosCallsFact.
+	//
+	// pass: The analysis pass of the package.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	bodies := make(map[*types.Func]*ast.BlockStmt)
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+					bodies[obj] = fn.Body
+				}
+			}
+		}
+	}
+
+	// Functions of the package may call each other in any order, so their methods are collected until none change
+	methods := make(map[*types.Func][]string)
+	for changed := true; changed; {
+		changed = false
+		for fn, body := range bodies {
+			ast.Inspect(body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				called := callee(pass, call)
+				found := nacosMethods(pass, called)
+				if _, local := bodies[called]; local {
+					found = methods[called]
+				}
+				for _, method := range found {
+					if !util.Contains(methods[fn], method) {
+						methods[fn] = append(methods[fn], method)
+						changed = true
+					}
+				}
+				return true
+			})
+		}
+	}
+	for fn, found := range methods {
+		set := make(map[string]bool)
+		for _, method := range found {
+			set[method] = true
+		}
+		pass.ExportObjectFact(fn, &NacosCallsFact{Methods: sortedKeys(set)})
+	}
+}
+
+func terminates(pass *analysis.Pass, stmt ast.Stmt) bool {
+	// terminates checks if control never flows from a statement to the one after it.
+	//
+	// pass: The analysis pass of the package.
+	// stmt: The statement.
+	//
+	// Returns:
+	// True for returns, branches, calls of panic, os.Exit, log.Fatal and the like, empty selects and loops without a
+	// condition or a break, and for blocks and if statements all of whose branches terminate. False otherwise.
+
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := stmt.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if ident, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+			if builtin, ok := pass.TypesInfo.Uses[ident].(*types.Builtin); ok {
+				return builtin.Name() == "panic"
+			}
+		}
+		fn := callee(pass, call)
+		return fn != nil && util.Contains(noReturn, fn.FullName())
+	case *ast.SelectStmt:
+		return len(stmt.Body.List) == 0
+	case *ast.ForStmt:
+		if stmt.Cond != nil {
+			return false
+		}
+		breaks := false
+		ast.Inspect(stmt.Body, func(n ast.Node) bool {
+			if branch, ok := n.(*ast.BranchStmt); ok && branch.Tok == token.BREAK {
+				breaks = true
+			}
+			_, closure := n.(*ast.FuncLit)
+			return !breaks && !closure
+		})
+		return !breaks
+	case *ast.BlockStmt:
+		return len(stmt.List) > 0 && terminates(pass, stmt.List[len(stmt.List)-1])
+	case *ast.IfStmt:
+		return stmt.Else != nil && terminates(pass, stmt.Body) && terminates(pass, stmt.Else)
+	}
+	return false
+}
+
+func unreachable(pass *analysis.Pass) func(pos token.Pos) bool {
+	// unreachable finds the statements of the package that follow a terminating statement in their block.
+	//
+	// pass: The analysis pass of the package.
+	//
+	// Returns:
+	// A function checking if a position lies in such a statement. Labeled statements, which a goto may reach, end the
+	// unreachable statements of a block.
+
+	type span struct{ pos, end token.Pos }
+	spans := []span{}
+	markDead := func(list []ast.Stmt) {
+		// markDead records the statements of a list that follow a terminating statement.
+		//
+		// list: The statements of a block or a case.
+		//
+		// Returns:
+		// This function doesn't return a value.
+
+		dead := false
+		for _, stmt := range list {
+			if _, labeled := stmt.(*ast.LabeledStmt); labeled {
+				dead = false
+			}
+			if dead {
+				spans = append(spans, span{stmt.Pos(), stmt.End()})
+			} else if terminates(pass, stmt) {
+				dead = true
+			}
+		}
+	}
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt:
+				markDead(n.List)
+			case *ast.CaseClause:
+				markDead(n.Body)
+			case *ast.CommClause:
+				markDead(n.Body)
+			}
+			return true
+		})
+	}
+	return func(pos token.Pos) bool {
+		for _, s := range spans {
+			if s.pos <= pos && pos < s.end {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"static_analyser/pkg/util"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Rules of the analyzer; their IDs are stable and prefix every message
+const (
+	RegisterWithoutDeregister = "NACOS001" // An instance is registered but never deregistered on shutdown.
+	DeregisterMismatch        = "NACOS002" // The parameters of a deregistration do not match those of the registration.
+	BusyRetryLoop             = "NACOS003" // A Nacos call is retried in a loop without waiting between attempts.
+	IgnoredSuccess            = "NACOS004" // The success result of a Nacos call is ignored.
+	FirstInstance             = "NACOS005" // The first discovered instance is used instead of a load-balanced selection.
+	ContinueAfterFailure      = "NACOS006" // Execution continues after a Nacos call fails.
+	UnregisteredDiscovery     = "NACOS007" // A service is discovered that no workload registers.
+)
+
+// rules lists every rule, in order
+var rules = []string{RegisterWithoutDeregister, DeregisterMismatch, BusyRetryLoop, IgnoredSuccess, FirstInstance, ContinueAfterFailure, UnregisteredDiscovery}
+
+// ruleList is a flag holding a comma separated list of rules, which are checked when the flag is set.
+type ruleList []string
+
+func (l *ruleList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *ruleList) Set(value string) error {
+	// Set parses the value of the flag.
+	//
+	// value: The comma separated list of rules, in any case.
+	//
+	// Returns:
+	// An error if the list names an unknown rule.
+
+	ids := ruleList{}
+	for _, id := range strings.Split(value, ",") {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+		if !util.Contains(rules, id) {
+			return fmt.Errorf("unknown rule %q, expected one of %s", id, strings.Join(rules, ", "))
+		}
+		ids = append(ids, id)
+	}
+	*l = ids
+	return nil
+}
+
+func enabledRules(enable ruleList, disable ruleList) map[string]bool {
+	// enabledRules works out which rules to check from the -enable and -disable flags.
+	//
+	// enable: The rules to check, or none for all of them.
+	// disable: The rules not to check.
+	//
+	// Returns:
+	// The set of rules to check.
+
+	if len(enable) == 0 {
+		enable = rules
+	}
+	enabled := make(map[string]bool)
+	for _, id := range enable {
+		enabled[id] = true
+	}
+	for _, id := range disable {
+		delete(enabled, id)
+	}
+	return enabled
+}
+
+func report(pass *analysis.Pass, enabled map[string]bool, rule string, node ast.Node, format string, args ...interface{}) {
+	// report reports a finding of a rule, if the rule is enabled.
+	//
+	// pass: The analysis pass of the package.
+	// enabled: The set of rules to check, see enabledRules.
+	// rule: The ID of the rule.
+	// node: The node the finding is reported at.
+	// format: The format of the message, followed by its arguments.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	if !enabled[rule] {
+		return
+	}
+	pass.Report(analysis.Diagnostic{Pos: node.Pos(), End: node.End(), Category: rule, Message: rule + ": " + fmt.Sprintf(format, args...)})
+}
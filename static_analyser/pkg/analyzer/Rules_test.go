@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestRuleList(test *testing.T) {
+	// TestRuleList checks that the -enable and -disable flags parse rules in any case and with spaces, and reject
+	// unknown rules.
+	//
+	// test: The test.
+
+	cases := []struct {
+		value string
+		want  ruleList
+		ok    bool
+	}{
+		{"NACOS001", ruleList{"NACOS001"}, true},
+		{"nacos001, NACOS004 ,", ruleList{"NACOS001", "NACOS004"}, true},
+		{"", ruleList{}, true},
+		{"NACOS001,NACOS999", nil, false},
+		{"unused", nil, false},
+	}
+	for _, c := range cases {
+		var list ruleList
+		err := list.Set(c.value)
+		if (err == nil) != c.ok {
+			test.Errorf("Set(%q) returned %v, want success %v", c.value, err, c.ok)
+			continue
+		}
+		if c.ok && !reflect.DeepEqual(list, c.want) {
+			test.Errorf("Set(%q) = %v, want %v", c.value, list, c.want)
+		}
+	}
+}
+
+func TestEnabledRules(test *testing.T) {
+	// TestEnabledRules checks the rules the -enable and -disable flags select.
+	//
+	// test: The test.
+
+	all := map[string]bool{}
+	for _, rule := range rules {
+		all[rule] = true
+	}
+	allBut := func(disabled ...string) map[string]bool {
+		enabled := map[string]bool{}
+		for rule := range all {
+			enabled[rule] = true
+		}
+		for _, rule := range disabled {
+			delete(enabled, rule)
+		}
+		return enabled
+	}
+
+	cases := []struct {
+		name    string
+		enable  ruleList
+		disable ruleList
+		want    map[string]bool
+	}{
+		{"all rules by default", nil, nil, all},
+		{"enabled rules only", ruleList{IgnoredSuccess, FirstInstance}, nil, map[string]bool{IgnoredSuccess: true, FirstInstance: true}},
+		{"disabled rules left out", nil, ruleList{UnregisteredDiscovery}, allBut(UnregisteredDiscovery)},
+		{"disabled rules win over enabled ones", ruleList{IgnoredSuccess, FirstInstance}, ruleList{FirstInstance}, map[string]bool{IgnoredSuccess: true}},
+	}
+	for _, c := range cases {
+		if got := enabledRules(c.enable, c.disable); !reflect.DeepEqual(got, c.want) {
+			test.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRuleSwitches(test *testing.T) {
+	// TestRuleSwitches checks that only enabled rules that are not disabled are reported, through the flags of the
+	// analyzer.
+	//
+	// test: The test.
+
+	withRules(test, nil, nil, "")
+	for name, value := range map[string]string{"enable": "NACOS004,NACOS005", "disable": "nacos005"} {
+		if err := Analyzer.Flags.Set(name, value); err != nil {
+			test.Fatal(err)
+		}
+	}
+	analysistest.Run(test, analysistest.TestData(), Analyzer, "switches")
+}
@@ -0,0 +1,15 @@
+package main // want package:`registers \[orders\] discovers \[payments\]`
+
+import (
+	"wrappers"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+)
+
+func main() { // want main:`calls \[RegisterInstance SelectOneHealthyInstance\]`
+	var client naming_client.INamingClient
+	if ok, err := wrappers.Register(client, "orders", 8080); !ok || err != nil { // want `NACOS001: the instance registered here is never deregistered`
+		return
+	}
+	wrappers.Discover(client, "payments")
+}
@@ -0,0 +1,41 @@
+package enumerated // want package:`registers \[audit ledger\] discovers \[ledger ledger-us orders payments\]`
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// register registers an instance of a service.
+func register(client naming_client.INamingClient, name string) (bool, error) { // want register:`registers parameter 1` register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: name})
+}
+
+// registerAll registers every service of a slice through the register wrapper.
+func registerAll(client naming_client.INamingClient) { // want registerAll:`calls \[RegisterInstance\]`
+	for _, name := range []string{"ledger", "audit"} {
+		register(client, name)
+	}
+}
+
+// discover returns a healthy instance of a service.
+func discover(client naming_client.INamingClient, name string) (*model.Instance, error) { // want discover:`discovers parameter 1 with SelectOneHealthyInstance` discover:`calls \[SelectOneHealthyInstance\]`
+	return client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: name})
+}
+
+// discoverAll discovers every service of a map through the discover wrapper.
+func discoverAll(client naming_client.INamingClient, region string) { // want discoverAll:`calls \[SelectOneHealthyInstance\]`
+	backends := map[string]string{"eu": "orders", "us": "payments"}
+	discover(client, backends[region])
+}
+
+// discoverLedgers discovers the ledger of every region itself.
+func discoverLedgers(client naming_client.INamingClient) { // want discoverLedgers:`discovers \["ledger" "ledger-us"\] with SelectInstances` discoverLedgers:`calls \[SelectInstances\]`
+	for _, name := range []string{"ledger", "ledger-us"} {
+		client.SelectInstances(vo.SelectInstancesParam{ServiceName: name, HealthyOnly: true})
+	}
+}
+
+func run(client naming_client.INamingClient) { // want run:`calls \[SelectInstances\]`
+	discoverLedgers(client)
+}
@@ -0,0 +1,17 @@
+// Package naming_client stubs the Nacos SDK naming client.
+package naming_client
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+type INamingClient interface {
+	RegisterInstance(param vo.RegisterInstanceParam) (bool, error)
+	DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error)
+	GetService(param vo.GetServiceParam) (model.Service, error)
+	SelectAllInstances(param vo.SelectAllInstancesParam) ([]model.Instance, error)
+	SelectInstances(param vo.SelectInstancesParam) ([]model.Instance, error)
+	SelectOneHealthyInstance(param vo.SelectOneHealthInstanceParam) (*model.Instance, error)
+	Subscribe(param *vo.SubscribeParam) error
+}
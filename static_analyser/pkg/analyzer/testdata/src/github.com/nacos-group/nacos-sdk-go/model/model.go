@@ -0,0 +1,17 @@
+// Package model stubs the results of the Nacos SDK naming client.
+package model
+
+type Instance struct {
+	InstanceId  string
+	Ip          string
+	Port        uint64
+	Weight      float64
+	Healthy     bool
+	Enable      bool
+	ServiceName string
+}
+
+type Service struct {
+	Name  string
+	Hosts []Instance
+}
@@ -0,0 +1,58 @@
+// Package vo stubs the parameters of the Nacos SDK naming client.
+package vo
+
+import "github.com/nacos-group/nacos-sdk-go/model"
+
+type RegisterInstanceParam struct {
+	Ip          string
+	Port        uint64
+	Weight      float64
+	Enable      bool
+	Healthy     bool
+	Metadata    map[string]string
+	ClusterName string
+	ServiceName string
+	GroupName   string
+	Ephemeral   bool
+}
+
+type DeregisterInstanceParam struct {
+	Ip          string
+	Port        uint64
+	Cluster     string
+	ServiceName string
+	GroupName   string
+	Ephemeral   bool
+}
+
+type GetServiceParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupName   string
+}
+
+type SelectAllInstancesParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupName   string
+}
+
+type SelectInstancesParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupName   string
+	HealthyOnly bool
+}
+
+type SelectOneHealthInstanceParam struct {
+	Clusters    []string
+	ServiceName string
+	GroupName   string
+}
+
+type SubscribeParam struct {
+	ServiceName       string
+	Clusters          []string
+	GroupName         string
+	SubscribeCallback func(services []model.Instance, err error)
+}
@@ -0,0 +1,26 @@
+package main // want package:`registers \[orders\] discovers \[\]`
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func register(client naming_client.INamingClient) (bool, error) { // want register:`registers "orders"` register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"}) // want `NACOS001: the instance registered here is never deregistered: DeregisterInstance is only called after a statement that never returns`
+}
+
+func deregister(client naming_client.INamingClient) (bool, error) { // want deregister:`calls \[DeregisterInstance\]`
+	return client.DeregisterInstance(vo.DeregisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+}
+
+func main() { // want main:`calls \[DeregisterInstance RegisterInstance\]`
+	var client naming_client.INamingClient
+	if ok, err := register(client); !ok || err != nil {
+		return
+	}
+	log.Fatal(http.ListenAndServe(":8080", nil))
+	deregister(client)
+}
@@ -0,0 +1,34 @@
+package main // want package:`registers \[orders\] discovers \[\]`
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func register(client naming_client.INamingClient) (bool, error) { // want register:`registers "orders"` register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+}
+
+func deregister(client naming_client.INamingClient) (bool, error) { // want deregister:`calls \[DeregisterInstance\]`
+	return client.DeregisterInstance(vo.DeregisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+}
+
+// onShutdown runs a function once the process is asked to stop.
+func onShutdown(stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	<-signals
+	stop()
+}
+
+func main() { // want main:`calls \[DeregisterInstance RegisterInstance\]`
+	var client naming_client.INamingClient
+	if ok, err := register(client); !ok || err != nil {
+		return
+	}
+	onShutdown(func() { deregister(client) })
+}
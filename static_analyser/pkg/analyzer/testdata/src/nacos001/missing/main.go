@@ -0,0 +1,18 @@
+package main // want package:`registers \[orders\] discovers \[\]`
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func register(client naming_client.INamingClient) (bool, error) { // want register:`registers "orders"` register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"}) // want `NACOS001: the instance registered here is never deregistered; call DeregisterInstance on shutdown`
+}
+
+func main() { // want main:`calls \[RegisterInstance\]`
+	var client naming_client.INamingClient
+	if ok, err := register(client); !ok || err != nil {
+		return
+	}
+	select {}
+}
@@ -0,0 +1,21 @@
+package nacos002
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func orders(client naming_client.INamingClient) { // want orders:`registers "orders"` orders:`calls \[DeregisterInstance RegisterInstance\]`
+	client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders", GroupName: "shop"})
+	client.DeregisterInstance(vo.DeregisterInstanceParam{Ip: "10.0.0.1", Port: 8081, ServiceName: "orders"}) // want `NACOS002: DeregisterInstance Port is 8081 but the instance is registered with Port 8080` `NACOS002: DeregisterInstance GroupName is "DEFAULT_GROUP" but the instance is registered with GroupName "shop"`
+}
+
+func payments(client naming_client.INamingClient, ip string) { // want payments:`registers "payments"` payments:`calls \[DeregisterInstance RegisterInstance\]`
+	client.RegisterInstance(vo.RegisterInstanceParam{Ip: ip, Port: 9090, ServiceName: "payments"})
+	client.DeregisterInstance(vo.DeregisterInstanceParam{Ip: "10.0.0.2", Port: 9090, ServiceName: "payments"}) // want `NACOS002: DeregisterInstance Ip is hard-coded to "10.0.0.2" but the instance is registered with Ip ip`
+}
+
+func ledger(client naming_client.INamingClient) { // want ledger:`registers "ledger"` ledger:`calls \[DeregisterInstance RegisterInstance\]`
+	client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.3", Port: 7070, ServiceName: "ledger", ClusterName: "eu"})
+	client.DeregisterInstance(vo.DeregisterInstanceParam{Ip: "10.0.0.3", Port: 7070, ServiceName: "ledger", Cluster: "eu"})
+}
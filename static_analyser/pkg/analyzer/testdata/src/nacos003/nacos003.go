@@ -0,0 +1,72 @@
+package nacos003 // want package:`registers \[orders\] discovers \[\]`
+
+import (
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func busy(client naming_client.INamingClient) *model.Instance { // want busy:`discovers "orders" with SelectOneHealthyInstance` busy:`calls \[SelectOneHealthyInstance\]`
+	for { // want `NACOS003: SelectOneHealthyInstance is retried in a loop without waiting between attempts`
+		if instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "orders"}); err == nil {
+			return instance
+		}
+	}
+}
+
+func busyCondition(client naming_client.INamingClient) { // want busyCondition:`calls \[RegisterInstance\]`
+	var err error
+	ok := false
+	for !ok || err != nil { // want `NACOS003: register is retried in a loop without waiting between attempts`
+		ok, err = register(client)
+	}
+}
+
+func register(client naming_client.INamingClient) (bool, error) { // want register:`registers "orders"` register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+}
+
+func sleeping(client naming_client.INamingClient) { // want sleeping:`calls \[RegisterInstance\]`
+	for {
+		if ok, err := register(client); ok && err == nil {
+			return
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func ticking(client naming_client.INamingClient, tick <-chan time.Time) { // want ticking:`calls \[RegisterInstance\]`
+	for {
+		if ok, err := register(client); ok && err == nil {
+			return
+		}
+		<-tick
+	}
+}
+
+func withBackoff(client naming_client.INamingClient) { // want withBackoff:`calls \[RegisterInstance\]`
+	for {
+		if ok, err := register(client); ok && err == nil {
+			return
+		}
+		waitWithBackoff()
+	}
+}
+
+func waitWithBackoff() {}
+
+func numbered(client naming_client.INamingClient) { // want numbered:`calls \[RegisterInstance\]`
+	for attempt := 0; ; attempt++ {
+		if ok, err := register(client); ok && err == nil {
+			return
+		}
+	}
+}
+
+func counted(client naming_client.INamingClient) { // want counted:`calls \[RegisterInstance\]`
+	for i := 0; i < 3; i++ {
+		register(client)
+	}
+}
@@ -0,0 +1,24 @@
+package nacos004
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func register(client naming_client.INamingClient) error { // want register:`registers "orders", "orders", "orders", "orders"` register:`calls \[RegisterInstance\]`
+	client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})           // want `NACOS004: the success result of RegisterInstance is ignored`
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"}) // want `NACOS004: the success result of RegisterInstance is ignored`
+	go client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})        // want `NACOS004: the success result of RegisterInstance is ignored`
+	if ok, err := client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"}); !ok || err != nil {
+		return err
+	}
+	return err
+}
+
+func deregister(client naming_client.INamingClient) { // want deregister:`calls \[DeregisterInstance\]`
+	defer client.DeregisterInstance(vo.DeregisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"}) // want `NACOS004: the success result of DeregisterInstance is ignored`
+}
+
+func subscribe(client naming_client.INamingClient) error { // want subscribe:`discovers "orders" with Subscribe` subscribe:`calls \[Subscribe\]`
+	return client.Subscribe(&vo.SubscribeParam{ServiceName: "orders"})
+}
@@ -0,0 +1,34 @@
+package nacos005
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func first(client naming_client.INamingClient) string { // want first:`discovers "orders" with SelectInstances` first:`calls \[SelectInstances\]`
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "orders", HealthyOnly: true})
+	if err != nil || len(instances) == 0 {
+		return ""
+	}
+	return instances[0].Ip // want `NACOS005: instances\[0\] always uses the first instance`
+}
+
+func subscribe(client naming_client.INamingClient, target chan<- string) error { // want subscribe:`discovers "orders" with Subscribe` subscribe:`calls \[Subscribe\]`
+	return client.Subscribe(&vo.SubscribeParam{
+		ServiceName: "orders",
+		SubscribeCallback: func(services []model.Instance, err error) {
+			if len(services) > 0 {
+				target <- services[0].Ip // want `NACOS005: services\[0\] always uses the first instance`
+			}
+		},
+	})
+}
+
+func pointers(instances []*model.Instance) string {
+	return instances[0].Ip // want `NACOS005: instances\[0\] always uses the first instance`
+}
+
+func others(names []string, instances []model.Instance) (string, string) {
+	return names[0], instances[1].Ip
+}
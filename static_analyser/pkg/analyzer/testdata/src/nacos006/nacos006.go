@@ -0,0 +1,45 @@
+package nacos006 // want package:`registers \[orders\] discovers \[\]`
+
+import (
+	"log"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func logged(client naming_client.INamingClient) { // want logged:`registers "orders"` logged:`calls \[RegisterInstance\]`
+	ok, err := client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+	if err != nil { // want `NACOS006: execution continues after RegisterInstance fails`
+		log.Println(err)
+	}
+	log.Println(ok)
+}
+
+func register(client naming_client.INamingClient) (bool, error) { // want register:`registers "orders"` register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+}
+
+func throughFunction(client naming_client.INamingClient) { // want throughFunction:`calls \[RegisterInstance\]`
+	ok, err := register(client)
+	if err != nil { // want `NACOS006: execution continues after register fails`
+		log.Println(err)
+	}
+	log.Println(ok)
+}
+
+func returned(client naming_client.INamingClient) error { // want returned:`calls \[RegisterInstance\]`
+	ok, err := register(client)
+	if err != nil {
+		return err
+	}
+	log.Println(ok)
+	return nil
+}
+
+func fatal(client naming_client.INamingClient) { // want fatal:`calls \[RegisterInstance\]`
+	ok, err := register(client)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(ok)
+}
@@ -0,0 +1,15 @@
+package nacos007 // want package:`registers \[\] discovers \[ghost ledger orders\]`
+
+import (
+	"registry"
+	"wrappers"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+)
+
+func Run(client naming_client.INamingClient) { // want Run:`calls \[RegisterInstance SelectOneHealthyInstance\]`
+	registry.Start(client)
+	wrappers.Discover(client, "orders")
+	wrappers.Discover(client, "ledger")
+	wrappers.Discover(client, "ghost") // want `NACOS007: Discover will discover ghost but no workload registers it`
+}
@@ -0,0 +1,12 @@
+package registry // want package:`registers \[ledger\] discovers \[\]`
+
+import (
+	"wrappers"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+)
+
+func Start(client naming_client.INamingClient) error { // want Start:`calls \[RegisterInstance\]`
+	_, err := wrappers.Register(client, "ledger", 7070)
+	return err
+}
@@ -0,0 +1,17 @@
+package switches
+
+import (
+	"log"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func run(client naming_client.INamingClient) string { // want run:`discovers "orders" with SelectInstances` run:`registers "orders"` run:`calls \[RegisterInstance SelectInstances\]`
+	client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"}) // want `NACOS004: the success result of RegisterInstance is ignored`
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{ServiceName: "orders"})
+	if err != nil {
+		log.Println(err)
+	}
+	return instances[0].Ip
+}
@@ -0,0 +1,18 @@
+// Package wrappers registers and discovers services for the packages importing it.
+package wrappers
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// Register registers an instance of a service.
+func Register(client naming_client.INamingClient, name string, port uint64) (bool, error) { // want Register:`registers parameter 1` Register:`calls \[RegisterInstance\]`
+	return client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: port, ServiceName: name})
+}
+
+// Discover returns a healthy instance of a service.
+func Discover(client naming_client.INamingClient, name string) (*model.Instance, error) { // want Discover:`discovers parameter 1 with SelectOneHealthyInstance` Discover:`calls \[SelectOneHealthyInstance\]`
+	return client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: name})
+}
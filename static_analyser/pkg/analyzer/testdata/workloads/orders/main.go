@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func register(client naming_client.INamingClient) {
+	client.RegisterInstance(vo.RegisterInstanceParam{Ip: "10.0.0.1", Port: 8080, ServiceName: "orders"})
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client)
+}
@@ -0,0 +1,20 @@
+package diagnostics
+
+// Codes of the diagnostics found while analysing the sources
+const (
+	GoSyntaxError          = "SA001" // A Go file has a syntax error; the declarations that parsed are still analysed.
+	UnreadableSource       = "SA002" // A Go file or folder cannot be read and is skipped.
+	InvalidYaml            = "SA003" // A YAML file cannot be parsed and is skipped.
+	NotKubernetesResource  = "SA004" // A YAML file has no apiVersion or kind, so it is not a Kubernetes resource.
+	DuplicateRegistration  = "SA005" // A service is registered by several applications.
+	UnreadableConfigSource = "SA006" // The config snapshot cannot be read, so config values are not resolved.
+	UnresolvedPermission   = "SA007" // A Nacos permission is not generated, as the name of its service or configuration is computed at runtime.
+	UnregisteredService    = "SA008" // A service is discovered that no workload registers.
+	SimilarServiceName     = "SA009" // A service name differs from a registered one only in case, separators or a typo.
+	UnconsumedService      = "SA010" // A service is registered that no workload discovers.
+	PortMismatch           = "SA011" // The listen, registered, container and Service target ports of an application disagree.
+	UnmergedFlow           = "SA012" // An observed flow to a workload that was not analysed has no single IP address, so it is not merged into the manifests.
+)
+
+// Severities of diagnostics, from the least to the most severe
+var severities = []string{"info", "warning", "error"}
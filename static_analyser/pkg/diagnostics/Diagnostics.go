@@ -0,0 +1,52 @@
+package diagnostics
+
+import (
+	"sort"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+)
+
+func (c *Collector) Diagnostics() []t.Diagnostic {
+	// Diagnostics returns the diagnostics recorded so far.
+	//
+	// Returns:
+	// The distinct diagnostics, sorted by file, line, code, service and message so that the output is stable.
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[t.Diagnostic]bool)
+	result := []t.Diagnostic{}
+	for _, diagnostic := range c.diagnostics {
+		if !seen[diagnostic] {
+			seen[diagnostic] = true
+			result = append(result, diagnostic)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		fileA, lineA := util.SplitLocation(a.Location)
+		fileB, lineB := util.SplitLocation(b.Location)
+		if fileA != fileB {
+			return fileA < fileB
+		}
+		if lineA != lineB {
+			return lineA < lineB
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Message != b.Message {
+			return a.Message < b.Message
+		}
+		// Locations with the same file and line, such as ones with columns, and severities break the remaining ties
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Severity < b.Severity
+	})
+	return result
+}
@@ -0,0 +1,31 @@
+package diagnostics
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestDiagnosticsOrder(test *testing.T) {
+	// TestDiagnosticsOrder checks that diagnostics at the same location with the same code are sorted by service and
+	// message, whatever order they were recorded in.
+	//
+	// test: The test.
+
+	want := []t.Diagnostic{
+		{Code: DuplicateRegistration, Severity: "warning", Location: "../tests/shop", Message: "b", Service: "billing"},
+		{Code: DuplicateRegistration, Severity: "warning", Location: "../tests/shop", Message: "a", Service: "orders"},
+		{Code: DuplicateRegistration, Severity: "warning", Location: "../tests/shop", Message: "b", Service: "orders"},
+	}
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+		c := NewCollector()
+		for _, i := range order {
+			c.Add(want[i])
+		}
+		got := c.Diagnostics()
+		for i := range want {
+			if got[i] != want[i] {
+				test.Errorf("recorded in order %v, got %+v at %d, want %+v", order, got[i], i, want[i])
+			}
+		}
+	}
+}
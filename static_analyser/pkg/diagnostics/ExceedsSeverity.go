@@ -0,0 +1,38 @@
+package diagnostics
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+)
+
+func ExceedsSeverity(diagnostics []t.Diagnostic, failOn string) (bool, error) {
+	// ExceedsSeverity tells whether a run should fail because of its diagnostics.
+	//
+	// diagnostics: The diagnostics of the run.
+	// failOn: The least severity that fails the run, "error", "warning" or "info", or "never".
+	//
+	// Returns:
+	// True if a diagnostic is at least as severe as failOn.
+	// An error if failOn is not a known severity.
+
+	if failOn == "never" {
+		return false, nil
+	}
+	threshold := -1
+	for i, severity := range severities {
+		if severity == failOn {
+			threshold = i
+		}
+	}
+	if threshold < 0 {
+		return false, fmt.Errorf("unknown severity %q, expected error, warning, info or never", failOn)
+	}
+	for _, diagnostic := range diagnostics {
+		for i, severity := range severities {
+			if severity == diagnostic.Severity && i >= threshold {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,34 @@
+package diagnostics
+
+import (
+	t "static_analyser/pkg/types"
+	"sync"
+)
+
+// Collector gathers the diagnostics of a run. It is safe for concurrent use.
+type Collector struct {
+	mu          sync.Mutex
+	diagnostics []t.Diagnostic
+}
+
+func NewCollector() *Collector {
+	// NewCollector creates an empty Collector.
+	//
+	// Returns:
+	// A pointer to the Collector.
+
+	return &Collector{}
+}
+
+func (c *Collector) Add(diagnostics ...t.Diagnostic) {
+	// Add records diagnostics.
+	//
+	// diagnostics: The diagnostics to record.
+	//
+	// Returns:
+	// This function doesn't return a value.
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.diagnostics = append(c.diagnostics, diagnostics...)
+}
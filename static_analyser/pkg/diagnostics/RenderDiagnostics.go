@@ -0,0 +1,32 @@
+package diagnostics
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderDiagnostics(diagnostics []t.Diagnostic) string {
+	// RenderDiagnostics renders diagnostics as text, in the "file:line: severity code: message" form editors understand.
+	//
+	// diagnostics: The diagnostics to render.
+	//
+	// Returns:
+	// One line per diagnostic followed by a summary line, or an empty string if there are none.
+
+	if len(diagnostics) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	counts := make(map[string]int)
+	for _, diagnostic := range diagnostics {
+		counts[diagnostic.Severity]++
+		fmt.Fprintf(&b, "%s: %s %s: %s", diagnostic.Location, diagnostic.Severity, diagnostic.Code, diagnostic.Message)
+		if diagnostic.Service != "" {
+			fmt.Fprintf(&b, " [%s]", diagnostic.Service)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Diagnostics: %d errors, %d warnings, %d infos\n", counts["error"], counts["warning"], counts["info"])
+	return b.String()
+}
@@ -0,0 +1,213 @@
+package diff
+
+import (
+	"sort"
+	"static_analyser/pkg/graph"
+	"static_analyser/pkg/policy"
+	t "static_analyser/pkg/types"
+)
+
+// connection collects every edge between two nodes of the service graph.
+type connection struct {
+	from, to string
+	ports    map[string]bool
+	kinds    map[string]bool
+}
+
+func connections(parsedYamls map[string]*t.Yaml2Go, manifests map[string]t.TCPManifest) map[string]*connection {
+	// connections groups the edges of the service graph of a revision by their two endpoints.
+	//
+	// parsedYamls: The parsed YAML files of the revision, keyed by application.
+	// manifests: The TCPManifests of the revision, keyed by application.
+	//
+	// Returns:
+	// A map where the keys identify the endpoints and the values collect the ports and kinds of the calls between them.
+
+	grouped := make(map[string]*connection)
+	for _, edge := range graph.BuildServiceGraph(parsedYamls, manifests).Edges {
+		key := edge.From + " -> " + edge.To
+		c, ok := grouped[key]
+		if !ok {
+			c = &connection{from: edge.From, to: edge.To, ports: make(map[string]bool), kinds: make(map[string]bool)}
+			grouped[key] = c
+		}
+		c.ports[edge.Port] = true
+		if edge.Kind != "" {
+			c.kinds[edge.Kind] = true
+		}
+	}
+	return grouped
+}
+
+func externalHosts(manifests map[string]t.TCPManifest) map[string]bool {
+	// externalHosts collects the hosts that are called without belonging to an analysed workload.
+	//
+	// manifests: The TCPManifests of a revision, keyed by application.
+	//
+	// Returns:
+	// A set of host:port strings.
+
+	hosts := make(map[string]bool)
+	for _, manifest := range manifests {
+		for _, req := range manifest.Requests {
+			if req.Name != "" || req.URL == "" {
+				continue
+			}
+			host := req.URL
+			if req.Port != "" {
+				host += ":" + req.Port
+			}
+			hosts[host] = true
+		}
+	}
+	return hosts
+}
+
+func sortedKeys(set map[string]bool) []string {
+	// sortedKeys returns the members of a set in sorted order.
+	//
+	// set: The set.
+	//
+	// Returns:
+	// The sorted members, never nil.
+
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func policyRules(parsedYamls map[string]*t.Yaml2Go, manifests map[string]t.TCPManifest) map[string]map[string]bool {
+	// policyRules generates the NetworkPolicies of a revision and describes their egress rules.
+	//
+	// parsedYamls: The parsed YAML files of the revision, keyed by application.
+	// manifests: The TCPManifests of the revision, keyed by application.
+	//
+	// Returns:
+	// A map where the keys are "namespace/name" of each policy and the values are the set of its rule descriptions.
+
+	rules := make(map[string]map[string]bool)
+	for _, p := range policy.GenerateNetworkPolicies(parsedYamls, manifests) {
+		name := p.Metadata.Namespace + "/" + p.Metadata.Name
+		rules[name] = make(map[string]bool)
+		for _, rule := range p.Spec.Egress {
+			for _, line := range policy.DescribeEgressRule(rule) {
+				rules[name][line] = true
+			}
+		}
+	}
+	return rules
+}
+
+func DiffManifests(oldYamls map[string]*t.Yaml2Go, oldManifests map[string]t.TCPManifest, newYamls map[string]*t.Yaml2Go, newManifests map[string]t.TCPManifest) t.ManifestDiff {
+	// DiffManifests compares the analysis results of two revisions.
+	//
+	// oldYamls, newYamls: The parsed YAML files of the old and new revision, keyed by application. Either may be nil.
+	// oldManifests, newManifests: The TCPManifests of the old and new revision, keyed by application.
+	//
+	// Returns:
+	// A ManifestDiff with the added and removed calls, the calls whose ports changed, the new external hosts
+	// and the changes to the generated NetworkPolicies, all sorted.
+
+	result := t.ManifestDiff{
+		AddedEdges:       []t.EdgeChange{},
+		RemovedEdges:     []t.EdgeChange{},
+		ChangedPorts:     []t.PortChange{},
+		NewExternalHosts: []string{},
+		PolicyChanges:    []t.PolicyChange{},
+	}
+
+	oldConnections := connections(oldYamls, oldManifests)
+	newConnections := connections(newYamls, newManifests)
+	for key, c := range newConnections {
+		old, ok := oldConnections[key]
+		if !ok {
+			result.AddedEdges = append(result.AddedEdges, t.EdgeChange{From: c.from, To: c.to, Ports: sortedKeys(c.ports), Kinds: sortedKeys(c.kinds)})
+			continue
+		}
+		oldPorts, newPorts := sortedKeys(old.ports), sortedKeys(c.ports)
+		if !equal(oldPorts, newPorts) {
+			result.ChangedPorts = append(result.ChangedPorts, t.PortChange{From: c.from, To: c.to, OldPorts: oldPorts, NewPorts: newPorts})
+		}
+	}
+	for key, c := range oldConnections {
+		if _, ok := newConnections[key]; !ok {
+			result.RemovedEdges = append(result.RemovedEdges, t.EdgeChange{From: c.from, To: c.to, Ports: sortedKeys(c.ports), Kinds: sortedKeys(c.kinds)})
+		}
+	}
+
+	oldHosts := externalHosts(oldManifests)
+	for host := range externalHosts(newManifests) {
+		if !oldHosts[host] {
+			result.NewExternalHosts = append(result.NewExternalHosts, host)
+		}
+	}
+
+	oldRules := policyRules(oldYamls, oldManifests)
+	newRules := policyRules(newYamls, newManifests)
+	names := make(map[string]bool)
+	for name := range oldRules {
+		names[name] = true
+	}
+	for name := range newRules {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		change := t.PolicyChange{Policy: name, Added: []string{}, Removed: []string{}}
+		for _, rule := range sortedKeys(newRules[name]) {
+			if !oldRules[name][rule] {
+				change.Added = append(change.Added, rule)
+			}
+		}
+		for _, rule := range sortedKeys(oldRules[name]) {
+			if !newRules[name][rule] {
+				change.Removed = append(change.Removed, rule)
+			}
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			result.PolicyChanges = append(result.PolicyChanges, change)
+		}
+	}
+
+	sortEdgeChanges(result.AddedEdges)
+	sortEdgeChanges(result.RemovedEdges)
+	sort.Slice(result.ChangedPorts, func(i, j int) bool {
+		a, b := result.ChangedPorts[i], result.ChangedPorts[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	sort.Strings(result.NewExternalHosts)
+
+	return result
+}
+
+func sortEdgeChanges(changes []t.EdgeChange) {
+	// sortEdgeChanges sorts edge changes by their endpoints.
+	//
+	// changes: The changes to sort in place.
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].From != changes[j].From {
+			return changes[i].From < changes[j].From
+		}
+		return changes[i].To < changes[j].To
+	})
+}
+
+func equal(a []string, b []string) bool {
+	// equal reports whether two string slices hold the same elements in the same order.
+
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
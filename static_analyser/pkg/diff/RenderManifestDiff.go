@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func IsEmpty(d t.ManifestDiff) bool {
+	// IsEmpty reports whether a diff contains no changes at all.
+
+	return len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.ChangedPorts) == 0 &&
+		len(d.NewExternalHosts) == 0 && len(d.PolicyChanges) == 0
+}
+
+func RenderManifestDiff(d t.ManifestDiff, markdown bool) string {
+	// RenderManifestDiff renders a diff for people to read.
+	//
+	// d: The diff to render.
+	// markdown: Whether to render Markdown, for example for a pull request comment, instead of plain text.
+	//
+	// Returns:
+	// The rendered diff.
+
+	var sb strings.Builder
+	heading := func(title string) {
+		if markdown {
+			fmt.Fprintf(&sb, "\n### %s\n\n", title)
+		} else {
+			fmt.Fprintf(&sb, "\n%s:\n", title)
+		}
+	}
+	item := func(format string, args ...interface{}) {
+		if markdown {
+			sb.WriteString("- ")
+		} else {
+			sb.WriteString("  ")
+		}
+		fmt.Fprintf(&sb, format+"\n", args...)
+	}
+	subitem := func(format string, args ...interface{}) {
+		if markdown {
+			sb.WriteString("  - ")
+		} else {
+			sb.WriteString("    ")
+		}
+		fmt.Fprintf(&sb, format+"\n", args...)
+	}
+	code := func(s string) string {
+		if markdown {
+			return "`" + s + "`"
+		}
+		return s
+	}
+
+	if markdown {
+		sb.WriteString("## Service communication changes\n")
+	} else {
+		sb.WriteString("Service communication changes\n")
+	}
+	if IsEmpty(d) {
+		sb.WriteString("\nNo changes.\n")
+		return sb.String()
+	}
+
+	if len(d.AddedEdges) > 0 {
+		heading("Added calls")
+		for _, e := range d.AddedEdges {
+			item("%s -> %s on ports %s (%s)", code(e.From), code(e.To), strings.Join(e.Ports, ", "), strings.Join(e.Kinds, ", "))
+		}
+	}
+	if len(d.RemovedEdges) > 0 {
+		heading("Removed calls")
+		for _, e := range d.RemovedEdges {
+			item("%s -> %s on ports %s (%s)", code(e.From), code(e.To), strings.Join(e.Ports, ", "), strings.Join(e.Kinds, ", "))
+		}
+	}
+	if len(d.ChangedPorts) > 0 {
+		heading("Changed ports")
+		for _, c := range d.ChangedPorts {
+			item("%s -> %s: %s => %s", code(c.From), code(c.To), strings.Join(c.OldPorts, ", "), strings.Join(c.NewPorts, ", "))
+		}
+	}
+	if len(d.NewExternalHosts) > 0 {
+		heading("New external hosts")
+		for _, host := range d.NewExternalHosts {
+			item("%s", code(host))
+		}
+	}
+	if len(d.PolicyChanges) > 0 {
+		heading("NetworkPolicy changes")
+		for _, c := range d.PolicyChanges {
+			item("%s", code(c.Policy))
+			for _, rule := range c.Added {
+				subitem("+ allow egress to %s", rule)
+			}
+			for _, rule := range c.Removed {
+				subitem("- allow egress to %s", rule)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package fake_nacos
+
+import (
+	"encoding/json"
+	"net/http"
+	t "static_analyser/pkg/types"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory Nacos server implementing the v1 naming, config and auth HTTP Open API.
+// It is meant for running the analysed services locally, not for production use.
+type Server struct {
+	mu        sync.Mutex
+	instances map[string]t.NacosInstance // instances are keyed by namespace, group, service, cluster, IP and port
+	configs   map[string]t.NacosConfig   // configs are keyed by tenant, group and data ID
+	changed   chan struct{}              // changed is closed and replaced whenever a config changes, waking up listeners
+}
+
+func NewServer() *Server {
+	// NewServer creates an empty fake Nacos server.
+	//
+	// Returns:
+	// The server, ready to be passed to http.ListenAndServe. Requests are accepted under any context path, such as /nacos/v1/ns/instance.
+
+	return &Server{
+		instances: make(map[string]t.NacosInstance),
+		configs:   make(map[string]t.NacosConfig),
+		changed:   make(chan struct{}),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// ServeHTTP routes a request to the handler of its Open API endpoint.
+	//
+	// w: The writer for the response.
+	// r: The request.
+
+	i := strings.Index(r.URL.Path, "/v1/")
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "caused: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch endpoint := strings.TrimSuffix(r.URL.Path[i:], "/"); endpoint {
+	case "/v1/ns/instance":
+		s.handleInstance(w, r)
+	case "/v1/ns/instance/list":
+		s.handleInstanceList(w, r)
+	case "/v1/ns/instance/beat":
+		s.handleBeat(w, r)
+	case "/v1/ns/service/list":
+		s.handleServiceList(w, r)
+	case "/v1/cs/configs":
+		s.handleConfig(w, r)
+	case "/v1/cs/configs/listener":
+		s.handleListener(w, r)
+	case "/v1/auth/login", "/v1/auth/users/login":
+		writeJSON(w, map[string]interface{}{"accessToken": "fake-nacos-token", "tokenTtl": 18000, "globalAdmin": true})
+	case "/v1/console/health/liveness", "/v1/console/health/readiness":
+		w.Write([]byte("OK"))
+	case "/v1/fake/snapshot":
+		writeJSON(w, s.Snapshot())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	// writeJSON writes a value as a JSON response.
+	//
+	// w: The writer for the response.
+	// value: The value to write.
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, "caused: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func param(r *http.Request, name string, defaultValue string) string {
+	// param reads a query or form parameter of a request.
+	//
+	// r: The request, whose form has been parsed.
+	// name: The name of the parameter.
+	// defaultValue: The value to return if the parameter is missing or empty.
+	//
+	// Returns:
+	// The value of the parameter.
+
+	if value := r.Form.Get(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
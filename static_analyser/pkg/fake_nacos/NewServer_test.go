@@ -0,0 +1,130 @@
+package fake_nacos
+
+import (
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"static_analyser/pkg/parser"
+	t "static_analyser/pkg/types"
+	validator "static_analyser/pkg/validate"
+	"strconv"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/clients"
+	"github.com/nacos-group/nacos-sdk-go/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+func TestServerThroughSDK(test *testing.T) {
+	// TestServerThroughSDK registers the instances the parser predicts for a fixture with the server through the Nacos SDK,
+	// queries them back through the SDK, and checks the findings of the validator as the registrations drift from the
+	// predictions.
+	//
+	// test: The test.
+
+	server := httptest.NewServer(NewServer())
+	defer server.Close()
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		test.Fatal(err)
+	}
+	portNumber, err := strconv.ParseUint(port, 10, 64)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	cacheDir := test.TempDir()
+	client, err := clients.NewNamingClient(vo.NacosClientParam{
+		ClientConfig: &constant.ClientConfig{
+			TimeoutMs:           5000,
+			NotLoadCacheAtStart: true,
+			CacheDir:            cacheDir + "/cache",
+			LogDir:              cacheDir + "/log",
+			LogLevel:            "error",
+		},
+		ServerConfigs: []constant.ServerConfig{{IpAddr: host, Port: portNumber, ContextPath: "/nacos"}},
+	})
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	// The registrations predicted for the provider of tests/multi_wrapper, which registers "provider" on 8080 and 9090
+	// and "provider-admin" on 8081 through two wrappers
+	file, err := parser.ParseFile("../../../tests/multi_wrapper/provider/main.go")
+	if err != nil {
+		test.Fatal(err)
+	}
+	serviceDirectory := make(map[string][]t.ServiceInfo)
+	for _, wrapper := range parser.FindRegisterInstanceWrappers(file, nil) {
+		names, infos := parser.FindRegisterInstanceWrapperInvocations(file, wrapper, "provider")
+		for i, name := range names {
+			serviceDirectory[name] = append(serviceDirectory[name], infos[i])
+		}
+	}
+	if len(serviceDirectory["provider"]) != 2 || len(serviceDirectory["provider-admin"]) != 1 {
+		test.Fatalf("got predicted registrations %+v, want provider on two ports and provider-admin on one", serviceDirectory)
+	}
+
+	// register registers an instance of a service through the SDK
+	register := func(serviceName string, ip string, port uint64) {
+		instance := vo.RegisterInstanceParam{ServiceName: serviceName, Ip: ip, Port: port, Weight: 1, Enable: true, Healthy: true, Ephemeral: true}
+		if ok, err := client.RegisterInstance(instance); err != nil || !ok {
+			test.Fatalf("registering %s: %v, %v", serviceName, ok, err)
+		}
+	}
+
+	// validate queries the server for the predicted services and compares them with the predictions
+	validate := func() []t.RegistryFinding {
+		names := []string{}
+		for name := range serviceDirectory {
+			names = append(names, name)
+		}
+		registered, err := validator.QueryNacosServer(server.URL, "public", "DEFAULT_GROUP", names, "", "")
+		if err != nil {
+			test.Fatal(err)
+		}
+		return validator.ValidateRegistrations(serviceDirectory, registered)
+	}
+
+	// Every predicted instance registered as predicted, from the pod IP
+	for name, infos := range serviceDirectory {
+		for _, info := range infos {
+			port, err := strconv.ParseUint(info.Port, 10, 64)
+			if err != nil {
+				test.Fatal(err)
+			}
+			register(name, "10.0.0.1", port)
+		}
+	}
+	instances, err := client.SelectAllInstances(vo.SelectAllInstancesParam{ServiceName: "provider-admin"})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].Ip != "10.0.0.1" || instances[0].Port != 8081 {
+		test.Fatalf("got instances %+v of provider-admin, want 10.0.0.1:8081", instances)
+	}
+	if findings := validate(); len(findings) != 0 {
+		test.Errorf("got findings %+v for the predicted registrations, want none", findings)
+	}
+
+	// The gRPC port moved, the admin service gone and a service static analysis does not know of
+	for _, instance := range []vo.DeregisterInstanceParam{
+		{ServiceName: "provider", Ip: "10.0.0.1", Port: 9090, Ephemeral: true},
+		{ServiceName: "provider-admin", Ip: "10.0.0.1", Port: 8081, Ephemeral: true},
+	} {
+		if ok, err := client.DeregisterInstance(instance); err != nil || !ok {
+			test.Fatalf("deregistering %s: %v, %v", instance.ServiceName, ok, err)
+		}
+	}
+	register("provider", "10.0.0.1", 9091)
+	register("extraservice", "10.0.0.3", 7070)
+
+	want := []t.RegistryFinding{
+		{Kind: "unpredicted", ServiceName: "extraservice", Runtime: []string{"10.0.0.3:7070"}},
+		{Kind: "port-mismatch", ServiceName: "provider", Application: "provider", PredictedIP: "provider.default.svc", PredictedPort: "9090", Runtime: []string{"10.0.0.1:8080", "10.0.0.1:9091"}},
+		{Kind: "missing", ServiceName: "provider-admin", Application: "provider", PredictedIP: "provider.default.svc", PredictedPort: "8081", Runtime: []string{}},
+	}
+	if findings := validate(); !reflect.DeepEqual(findings, want) {
+		test.Errorf("got findings %+v, want %+v", findings, want)
+	}
+}
@@ -0,0 +1,21 @@
+package fake_nacos
+
+import (
+	t "static_analyser/pkg/types"
+)
+
+func (s *Server) PublishConfig(dataId string, group string, tenant string, content string) {
+	// PublishConfig publishes a configuration on the server, as a POST to /v1/cs/configs would,
+	// and wakes up the clients listening for it.
+	//
+	// dataId: The data ID of the configuration.
+	// group: The group of the configuration.
+	// tenant: The namespace of the configuration, empty for public.
+	// content: The content of the configuration.
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.configs[configKey(tenant, group, dataId)] = t.NacosConfig{DataId: dataId, Group: group, Tenant: tenant, Content: content}
+	s.notify()
+}
@@ -0,0 +1,43 @@
+package fake_nacos
+
+import (
+	"sort"
+	t "static_analyser/pkg/types"
+)
+
+func (s *Server) Snapshot() t.NacosSnapshot {
+	// Snapshot returns the current state of the server.
+	//
+	// Returns:
+	// The registered instances, sorted by namespace, group, service, cluster, IP and port,
+	// and the published configurations, sorted by tenant, group and data ID.
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := t.NacosSnapshot{Instances: s.sortedInstances(), Configs: []t.NacosConfig{}}
+	keys := []string{}
+	for key := range s.configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		snapshot.Configs = append(snapshot.Configs, s.configs[key])
+	}
+	return snapshot
+}
+
+func (s *Server) sortedInstances() []t.NacosInstance {
+	// sortedInstances returns the registered instances sorted by their key. The caller must hold s.mu.
+
+	keys := []string{}
+	for key := range s.instances {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	instances := []t.NacosInstance{}
+	for _, key := range keys {
+		instances = append(instances, s.instances[key])
+	}
+	return instances
+}
@@ -0,0 +1,131 @@
+package fake_nacos
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func configKey(tenant string, group string, dataId string) string {
+	// configKey returns the key of a configuration in the configs map of a Server. The public namespace is the empty tenant.
+
+	if tenant == "public" {
+		tenant = ""
+	}
+	return tenant + "@@" + group + "@@" + dataId
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	// handleConfig returns, publishes or deletes a configuration.
+	//
+	// w: The writer for the response.
+	// r: The request. GET returns the content, POST publishes it and DELETE deletes it.
+
+	dataId := param(r, "dataId", "")
+	group := param(r, "group", "DEFAULT_GROUP")
+	tenant := param(r, "tenant", "")
+	if dataId == "" {
+		http.Error(w, "caused: dataId is required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		config, ok := s.configs[configKey(tenant, group, dataId)]
+		s.mu.Unlock()
+		if !ok {
+			http.Error(w, "config data not exist", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-MD5", contentMD5(config.Content))
+		w.Write([]byte(config.Content))
+	case http.MethodPost:
+		s.PublishConfig(dataId, group, tenant, param(r, "content", ""))
+		w.Write([]byte("true"))
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.configs, configKey(tenant, group, dataId))
+		s.notify()
+		s.mu.Unlock()
+		w.Write([]byte("true"))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleListener(w http.ResponseWriter, r *http.Request) {
+	// handleListener long-polls for configuration changes. The Listening-Configs parameter lists the
+	// configurations as "dataId^2group^2md5[^2tenant]^1"; the response lists those whose content no longer
+	// has the given MD5, as soon as there is one or when the Long-Pulling-Timeout header expires.
+	//
+	// w: The writer for the response.
+	// r: The request.
+
+	timeout, err := strconv.Atoi(r.Header.Get("Long-Pulling-Timeout"))
+	if err != nil || timeout <= 0 {
+		timeout = 30000
+	}
+	deadline := time.After(time.Duration(timeout) * time.Millisecond)
+
+	for {
+		s.mu.Lock()
+		changed := []string{}
+		for _, listening := range strings.Split(param(r, "Listening-Configs", ""), "\x01") {
+			fields := strings.Split(listening, "\x02")
+			if len(fields) < 3 {
+				continue
+			}
+			tenant := ""
+			if len(fields) > 3 {
+				tenant = fields[3]
+			}
+			content := ""
+			if config, ok := s.configs[configKey(tenant, fields[1], fields[0])]; ok {
+				content = contentMD5(config.Content)
+			}
+			if content != fields[2] {
+				key := fields[0] + "\x02" + fields[1]
+				if tenant != "" {
+					key += "\x02" + tenant
+				}
+				changed = append(changed, key+"\x01")
+			}
+		}
+		wait := s.changed
+		s.mu.Unlock()
+
+		if len(changed) > 0 {
+			w.Write([]byte(url.QueryEscape(strings.Join(changed, ""))))
+			return
+		}
+		select {
+		case <-wait:
+		case <-deadline:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func contentMD5(content string) string {
+	// contentMD5 returns the MD5 of a configuration content as the clients compute it, or "" for no content.
+
+	if content == "" {
+		return ""
+	}
+	sum := md5.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Server) notify() {
+	// notify wakes up the listeners waiting for a configuration change. The caller must hold s.mu.
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,231 @@
+package fake_nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	t "static_analyser/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func serviceParams(r *http.Request) (string, string, string) {
+	// serviceParams reads the namespace, group and service name of a naming request.
+	// The SDK sends the service name as "group@@service"; the group defaults to DEFAULT_GROUP and the namespace to public.
+	//
+	// r: The request, whose form has been parsed.
+	//
+	// Returns:
+	// The namespace, group and service name.
+
+	namespace := param(r, "namespaceId", "public")
+	group := param(r, "groupName", "DEFAULT_GROUP")
+	service := param(r, "serviceName", "")
+	if i := strings.Index(service, "@@"); i >= 0 {
+		group, service = service[:i], service[i+2:]
+	}
+	return namespace, group, service
+}
+
+func instanceKey(instance t.NacosInstance) string {
+	// instanceKey returns the key of an instance in the instances map of a Server.
+
+	return strings.Join([]string{instance.NamespaceId, instance.GroupName, instance.ServiceName, instance.ClusterName, instance.Ip, strconv.FormatUint(instance.Port, 10)}, "@@")
+}
+
+func (s *Server) handleInstance(w http.ResponseWriter, r *http.Request) {
+	// handleInstance registers, updates, deregisters or looks up an instance.
+	//
+	// w: The writer for the response.
+	// r: The request. POST registers, PUT updates, DELETE deregisters and GET returns the instance.
+
+	namespace, group, service := serviceParams(r)
+	port, err := strconv.ParseUint(param(r, "port", ""), 10, 64)
+	if service == "" || param(r, "ip", "") == "" || err != nil {
+		http.Error(w, "caused: serviceName, ip and port are required", http.StatusBadRequest)
+		return
+	}
+	instance := t.NacosInstance{
+		ServiceName: service,
+		GroupName:   group,
+		NamespaceId: namespace,
+		ClusterName: param(r, "clusterName", param(r, "cluster", "DEFAULT")),
+		Ip:          param(r, "ip", ""),
+		Port:        port,
+	}
+	key := instanceKey(instance)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		instance.Weight, err = strconv.ParseFloat(param(r, "weight", "1"), 64)
+		if err != nil {
+			http.Error(w, "caused: invalid weight", http.StatusBadRequest)
+			return
+		}
+		instance.Healthy = param(r, "healthy", "true") == "true"
+		instance.Enabled = param(r, "enabled", param(r, "enable", "true")) == "true"
+		instance.Ephemeral = param(r, "ephemeral", "true") == "true"
+		if metadata := param(r, "metadata", ""); metadata != "" {
+			if err := json.Unmarshal([]byte(metadata), &instance.Metadata); err != nil {
+				http.Error(w, "caused: invalid metadata", http.StatusBadRequest)
+				return
+			}
+		}
+		if _, ok := s.instances[key]; !ok && r.Method == http.MethodPut {
+			http.Error(w, "caused: instance not found", http.StatusNotFound)
+			return
+		}
+		s.instances[key] = instance
+		w.Write([]byte("ok"))
+	case http.MethodDelete:
+		delete(s.instances, key)
+		w.Write([]byte("ok"))
+	case http.MethodGet:
+		existing, ok := s.instances[key]
+		if !ok {
+			http.Error(w, "caused: no matched ip found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, hostJSON(existing))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func hostJSON(instance t.NacosInstance) map[string]interface{} {
+	// hostJSON converts an instance into the form the Open API returns it in.
+
+	return map[string]interface{}{
+		"instanceId":  fmt.Sprintf("%s#%d#%s#%s@@%s", instance.Ip, instance.Port, instance.ClusterName, instance.GroupName, instance.ServiceName),
+		"ip":          instance.Ip,
+		"port":        instance.Port,
+		"weight":      instance.Weight,
+		"healthy":     instance.Healthy,
+		"enabled":     instance.Enabled,
+		"ephemeral":   instance.Ephemeral,
+		"clusterName": instance.ClusterName,
+		"serviceName": instance.GroupName + "@@" + instance.ServiceName,
+		"metadata":    instance.Metadata,
+
+		"instanceHeartBeatInterval": 5000,
+		"instanceHeartBeatTimeOut":  15000,
+		"ipDeleteTimeout":           30000,
+	}
+}
+
+func (s *Server) handleInstanceList(w http.ResponseWriter, r *http.Request) {
+	// handleInstanceList returns the instances of a service, optionally limited to some clusters and to healthy instances.
+	//
+	// w: The writer for the response.
+	// r: The request.
+
+	namespace, group, service := serviceParams(r)
+	clusters := param(r, "clusters", "")
+	healthyOnly := param(r, "healthyOnly", "false") == "true"
+
+	s.mu.Lock()
+	hosts := []map[string]interface{}{}
+	for _, instance := range s.sortedInstances() {
+		if instance.NamespaceId != namespace || instance.GroupName != group || instance.ServiceName != service {
+			continue
+		}
+		if clusters != "" && !strings.Contains(","+clusters+",", ","+instance.ClusterName+",") {
+			continue
+		}
+		if healthyOnly && !instance.Healthy {
+			continue
+		}
+		hosts = append(hosts, hostJSON(instance))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, map[string]interface{}{
+		"name":                     group + "@@" + service,
+		"groupName":                group,
+		"clusters":                 clusters,
+		"cacheMillis":              10000,
+		"hosts":                    hosts,
+		"lastRefTime":              time.Now().UnixMilli(),
+		"checksum":                 "",
+		"allIPs":                   false,
+		"reachProtectionThreshold": false,
+		"valid":                    true,
+	})
+}
+
+func (s *Server) handleBeat(w http.ResponseWriter, r *http.Request) {
+	// handleBeat accepts an instance heartbeat. A heartbeat for an unknown instance registers it again,
+	// as Nacos does after an instance has expired.
+	//
+	// w: The writer for the response.
+	// r: The request, carrying the heartbeat as JSON in the beat parameter.
+
+	var beat struct {
+		Ip          string            `json:"ip"`
+		Port        uint64            `json:"port"`
+		Weight      float64           `json:"weight"`
+		ServiceName string            `json:"serviceName"`
+		Cluster     string            `json:"cluster"`
+		Metadata    map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(param(r, "beat", "{}")), &beat); err == nil && beat.Ip != "" {
+		namespace, group, service := serviceParams(r)
+		if i := strings.Index(beat.ServiceName, "@@"); i >= 0 {
+			group, service = beat.ServiceName[:i], beat.ServiceName[i+2:]
+		}
+		cluster := beat.Cluster
+		if cluster == "" {
+			cluster = "DEFAULT"
+		}
+		instance := t.NacosInstance{ServiceName: service, GroupName: group, NamespaceId: namespace, ClusterName: cluster, Ip: beat.Ip, Port: beat.Port,
+			Weight: beat.Weight, Healthy: true, Enabled: true, Ephemeral: true, Metadata: beat.Metadata}
+
+		s.mu.Lock()
+		if _, ok := s.instances[instanceKey(instance)]; !ok {
+			s.instances[instanceKey(instance)] = instance
+		}
+		s.mu.Unlock()
+	}
+	writeJSON(w, map[string]interface{}{"clientBeatInterval": 5000, "code": 10200, "lightBeatEnabled": false})
+}
+
+func (s *Server) handleServiceList(w http.ResponseWriter, r *http.Request) {
+	// handleServiceList returns one page of the names of the services of a namespace and group.
+	//
+	// w: The writer for the response.
+	// r: The request.
+
+	namespace := param(r, "namespaceId", "public")
+	group := param(r, "groupName", "DEFAULT_GROUP")
+	pageNo, _ := strconv.Atoi(param(r, "pageNo", "1"))
+	pageSize, _ := strconv.Atoi(param(r, "pageSize", "20"))
+	if pageNo < 1 {
+		pageNo = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	s.mu.Lock()
+	seen := make(map[string]bool)
+	services := []string{}
+	for _, instance := range s.sortedInstances() {
+		if instance.NamespaceId == namespace && instance.GroupName == group && !seen[instance.ServiceName] {
+			seen[instance.ServiceName] = true
+			services = append(services, instance.ServiceName)
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(services)
+
+	page := []string{}
+	if start := (pageNo - 1) * pageSize; start < len(services) {
+		page = services[start:min(start+pageSize, len(services))]
+	}
+	writeJSON(w, map[string]interface{}{"count": len(services), "doms": page})
+}
@@ -0,0 +1,123 @@
+package file_utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func ExtractGitRevision(path string, revision string) (string, string, error) {
+	// ExtractGitRevision extracts a revision of the git repository containing a path into a temporary directory.
+	// The revision is read from the local git objects with "git archive", so the working tree is left untouched.
+	// Only the path is extracted, streamed from git without holding the archive in memory.
+	//
+	// path: A path inside a git working tree.
+	// revision: Any revision understood by git, such as a branch name or commit hash.
+	//
+	// Returns:
+	// The equivalent of path inside the extracted revision.
+	// The temporary directory, which the caller should remove.
+	// An error if git failed or the archive could not be extracted.
+
+	gitOutput := func(dir string, args ...string) (string, error) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return string(out), nil
+	}
+
+	prefix, err := gitOutput(path, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", "", err
+	}
+	topLevel, err := gitOutput(path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", "", err
+	}
+	// Run from the top level, since git archive only archives the current directory otherwise, and archive no more than
+	// the path. The archive is streamed into the tar reader, so the revision is never held in memory.
+	gitArgs := []string{"-C", strings.TrimSpace(topLevel), "archive", "--format=tar", revision}
+	if prefix = strings.TrimSpace(prefix); prefix != "" {
+		gitArgs = append(gitArgs, "--", prefix)
+	}
+	cmd := exec.Command("git", gitArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	archive, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", "", fmt.Errorf("git %s: %w", strings.Join(gitArgs[2:], " "), err)
+	}
+	if err := cmd.Start(); err != nil {
+		return "", "", fmt.Errorf("git %s: %w", strings.Join(gitArgs[2:], " "), err)
+	}
+	// wait waits for git to exit, reporting what it printed if it failed
+	wait := func() error {
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("git %s: %v: %s", strings.Join(gitArgs[2:], " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "static_analyser-")
+	if err != nil {
+		io.Copy(io.Discard, archive)
+		wait()
+		return "", "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			io.Copy(io.Discard, archive)
+			os.RemoveAll(tmpDir)
+			if waitErr := wait(); waitErr != nil {
+				return "", "", waitErr
+			}
+			return "", "", fmt.Errorf("failed to read archive of %s: %w", revision, err)
+		}
+
+		target := filepath.Join(tmpDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(tmpDir)+string(os.PathSeparator)) {
+			continue
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+				var file *os.File
+				if file, err = os.Create(target); err == nil {
+					_, err = io.Copy(file, reader)
+					if closeErr := file.Close(); err == nil {
+						err = closeErr
+					}
+				}
+			}
+		}
+		if err != nil {
+			io.Copy(io.Discard, archive)
+			wait()
+			os.RemoveAll(tmpDir)
+			return "", "", fmt.Errorf("failed to extract %s of %s: %w", header.Name, revision, err)
+		}
+	}
+	if err := wait(); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", "", err
+	}
+
+	return filepath.Join(tmpDir, filepath.FromSlash(prefix)), tmpDir, nil
+}
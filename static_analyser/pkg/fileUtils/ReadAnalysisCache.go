@@ -0,0 +1,28 @@
+package file_utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	t "static_analyser/pkg/types"
+)
+
+func ReadAnalysisCache(dir string, hash string) (t.ApplicationAnalysis, bool) {
+	// ReadAnalysisCache reads the analysis of an application from the cache written by WriteAnalysisCache.
+	//
+	// dir: The cache directory.
+	// hash: The hash of the inputs of the analysis.
+	//
+	// Returns:
+	// The cached ApplicationAnalysis, and true if the cache holds a valid entry for the hash.
+
+	var analysis t.ApplicationAnalysis
+	jsonData, err := os.ReadFile(filepath.Join(dir, hash+".json"))
+	if err != nil {
+		return analysis, false
+	}
+	if err := json.Unmarshal(jsonData, &analysis); err != nil || analysis.Hash != hash {
+		return t.ApplicationAnalysis{}, false
+	}
+	return analysis, true
+}
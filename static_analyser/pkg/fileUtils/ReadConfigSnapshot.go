@@ -0,0 +1,48 @@
+package file_utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func ReadConfigSnapshot(dir string) (map[string]string, error) {
+	// ReadConfigSnapshot reads a local snapshot of Nacos configurations from a directory.
+	// Three layouts are understood: "<dataId>@@<group>[@@<tenant>]" files as written by the Nacos Go SDK cache,
+	// "<group>/<dataId>" files as written by the Java client snapshot, and plain "<dataId>" files in DEFAULT_GROUP.
+	//
+	// dir: The directory holding the snapshot.
+	//
+	// Returns:
+	// A map where the keys are "<dataId>@@<group>" and the values are the contents of the configurations.
+	// An error if the directory or one of its files could not be read.
+
+	configs := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		dataId, group := info.Name(), "DEFAULT_GROUP"
+		if parts := strings.Split(info.Name(), "@@"); len(parts) >= 2 {
+			dataId, group = parts[0], parts[1]
+		} else if parent := filepath.Dir(path); filepath.Clean(parent) != filepath.Clean(dir) {
+			group = filepath.Base(parent)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file '%s': %w", path, err)
+		}
+		configs[dataId+"@@"+group] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config snapshot '%s': %w", dir, err)
+	}
+	return configs, nil
+}
@@ -0,0 +1,78 @@
+package file_utils
+
+import (
+	"fmt"
+	"os"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+
+	"gopkg.in/yaml.v2"
+)
+
+func ReadKubernetesInventory(path string) (map[string]t.FlowEndpoint, error) {
+	// ReadKubernetesInventory reads the pods and services of a cluster, as printed by "kubectl get pods,services -A -o json" or "-o yaml".
+	//
+	// path: The path of the inventory file.
+	//
+	// Returns:
+	// A map from the IP addresses of pods and services, and from "<namespace>/<pod>" names, to the endpoints they belong to.
+	// A service belongs to the workload its selector selects.
+	// An error if the file could not be read or parsed.
+
+	type item struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Name      string            `yaml:"name"`
+			Namespace string            `yaml:"namespace"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			ClusterIP  string            `yaml:"clusterIP"`
+			ClusterIPs []string          `yaml:"clusterIPs"`
+			Selector   map[string]string `yaml:"selector"`
+		} `yaml:"spec"`
+		Status struct {
+			PodIP  string `yaml:"podIP"`
+			PodIPs []struct {
+				IP string `yaml:"ip"`
+			} `yaml:"podIPs"`
+		} `yaml:"status"`
+	}
+	var list struct {
+		Items []item `yaml:"items"`
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory '%s': %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory '%s': %w", path, err)
+	}
+
+	inventory := make(map[string]t.FlowEndpoint)
+	for _, it := range list.Items {
+		switch it.Kind {
+		case "Pod":
+			endpoint := t.FlowEndpoint{Workload: util.WorkloadLabel(it.Metadata.Labels), Namespace: it.Metadata.Namespace, Pod: it.Metadata.Name}
+			ips := []string{it.Status.PodIP}
+			for _, podIP := range it.Status.PodIPs {
+				ips = append(ips, podIP.IP)
+			}
+			for _, ip := range ips {
+				if ip != "" {
+					endpoint.IP = ip
+					inventory[ip] = endpoint
+				}
+			}
+			inventory[it.Metadata.Namespace+"/"+it.Metadata.Name] = endpoint
+		case "Service":
+			for _, ip := range append([]string{it.Spec.ClusterIP}, it.Spec.ClusterIPs...) {
+				if ip != "" && ip != "None" {
+					inventory[ip] = t.FlowEndpoint{IP: ip, Workload: util.WorkloadLabel(it.Spec.Selector), Namespace: it.Metadata.Namespace}
+				}
+			}
+		}
+	}
+	return inventory, nil
+}
@@ -0,0 +1,77 @@
+package file_utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	t "static_analyser/pkg/types"
+
+	"gopkg.in/yaml.v2"
+)
+
+func ReadKubernetesNamespaces(path string) ([]t.KubernetesNamespace, error) {
+	// ReadKubernetesNamespaces reads the Kubernetes Namespaces of a YAML file, whose labels namespace selectors of
+	// NetworkPolicies match. Files may hold several documents, and List documents are searched for Namespace items.
+	// Documents of other kinds are skipped.
+	//
+	// path: The file to read.
+	//
+	// Returns:
+	// The Namespaces, in the order they were read, with their File set to path.
+	// An error if the file could not be read or holds invalid YAML.
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+
+	// namespace is a Namespace document or List item
+	type namespace struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Name   string            `yaml:"name"`
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+	}
+
+	namespaces := []t.KubernetesNamespace{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw interface{}
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse file '%s': %w", path, err)
+		}
+		// Round trip the document to decode it by kind; documents of other kinds may not fit
+		encoded, err := yaml.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse file '%s': %w", path, err)
+		}
+		var document struct {
+			namespace `yaml:",inline"`
+			Items     []namespace `yaml:"items"`
+		}
+		if err := yaml.Unmarshal(encoded, &document); err != nil {
+			continue
+		}
+		items := []namespace{}
+		switch document.Kind {
+		case "Namespace":
+			items = append(items, document.namespace)
+		case "List", "NamespaceList":
+			for _, item := range document.Items {
+				if item.Kind == "" || item.Kind == "Namespace" {
+					items = append(items, item)
+				}
+			}
+		}
+		for _, item := range items {
+			if item.Metadata.Name != "" {
+				namespaces = append(namespaces, t.KubernetesNamespace{Name: item.Metadata.Name, Labels: item.Metadata.Labels, File: path})
+			}
+		}
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,70 @@
+package file_utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	t "static_analyser/pkg/types"
+
+	"gopkg.in/yaml.v2"
+)
+
+func ReadKubernetesServices(path string) ([]t.KubernetesService, error) {
+	// ReadKubernetesServices reads the Kubernetes Services of a YAML file. Files may hold several documents, such as a
+	// Deployment followed by its Service, and List documents are searched for Service items. Documents of other kinds are skipped.
+	//
+	// path: The file to read.
+	//
+	// Returns:
+	// The Services, in the order they were read, with their File set to path.
+	// An error if the file could not be read or holds invalid YAML.
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+
+	services := []t.KubernetesService{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse file '%s': %w", path, err)
+		}
+		// Round trip the document to decode it by kind
+		raw, err := yaml.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse file '%s': %w", path, err)
+		}
+		var list struct {
+			Kind  string `yaml:"kind"`
+			Items []struct {
+				Kind                string `yaml:"kind"`
+				t.KubernetesService `yaml:",inline"`
+			} `yaml:"items"`
+		}
+		if err := yaml.Unmarshal(raw, &list); err != nil {
+			continue
+		}
+		switch list.Kind {
+		case "Service":
+			var service t.KubernetesService
+			if err := yaml.Unmarshal(raw, &service); err != nil {
+				return nil, fmt.Errorf("invalid Service in '%s': %w", path, err)
+			}
+			service.File = path
+			services = append(services, service)
+		case "List", "ServiceList":
+			for _, item := range list.Items {
+				if item.Kind == "" || item.Kind == "Service" {
+					item.KubernetesService.File = path
+					services = append(services, item.KubernetesService)
+				}
+			}
+		}
+	}
+	return services, nil
+}
@@ -0,0 +1,28 @@
+package file_utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	t "static_analyser/pkg/types"
+)
+
+func ReadNacosSnapshot(path string) (t.NacosSnapshot, error) {
+	// ReadNacosSnapshot reads the state of a Nacos server from a JSON dump, as written by the fake-nacos command.
+	//
+	// path: The path of the JSON file.
+	//
+	// Returns:
+	// The registered instances and published configurations of the dump.
+	// An error if the file could not be read or is not a valid dump.
+
+	var snapshot t.NacosSnapshot
+	jsonData, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to read Nacos dump '%s': %w", path, err)
+	}
+	if err := json.Unmarshal(jsonData, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("failed to parse Nacos dump '%s': %w", path, err)
+	}
+	return snapshot, nil
+}
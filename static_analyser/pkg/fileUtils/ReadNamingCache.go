@@ -0,0 +1,80 @@
+package file_utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func ReadNamingCache(dir string) ([]t.NacosInstance, error) {
+	// ReadNamingCache reads the service instances cached on disk by the Nacos SDK, such as cache/naming/DEFAULT_GROUP@@HelloService.
+	// Every file below dir that holds a cached service is read, so dir may be a whole source tree.
+	// Files directly inside a "naming" directory belong to the public namespace; others to the namespace named by their directory.
+	//
+	// dir: The directory to search.
+	//
+	// Returns:
+	// The cached instances, each listed once even if several clients cached it.
+	// An error if the directory could not be walked or a cache file could not be read.
+
+	// cachedService is the form the SDK caches a service in
+	type cachedService struct {
+		Name  string `json:"name"`
+		Hosts []struct {
+			Ip          string            `json:"ip"`
+			Port        uint64            `json:"port"`
+			Weight      float64           `json:"weight"`
+			Healthy     bool              `json:"healthy"`
+			Enabled     bool              `json:"enabled"`
+			Ephemeral   bool              `json:"ephemeral"`
+			ClusterName string            `json:"clusterName"`
+			ServiceName string            `json:"serviceName"`
+			Metadata    map[string]string `json:"metadata"`
+		} `json:"hosts"`
+	}
+
+	instances := []t.NacosInstance{}
+	seen := make(map[string]bool)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.Contains(info.Name(), "@@") || !strings.Contains(filepath.ToSlash(path), "/naming/") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read naming cache file '%s': %w", path, err)
+		}
+		var service cachedService
+		if err := json.Unmarshal(data, &service); err != nil {
+			return nil
+		}
+
+		namespace := filepath.Base(filepath.Dir(path))
+		if namespace == "naming" {
+			namespace = "public"
+		}
+		for _, host := range service.Hosts {
+			group, name, found := strings.Cut(host.ServiceName, "@@")
+			if !found {
+				group, name, _ = strings.Cut(info.Name(), "@@")
+			}
+			instance := t.NacosInstance{ServiceName: name, GroupName: group, NamespaceId: namespace, ClusterName: host.ClusterName, Ip: host.Ip, Port: host.Port,
+				Weight: host.Weight, Healthy: host.Healthy, Enabled: host.Enabled, Ephemeral: host.Ephemeral, Metadata: host.Metadata}
+			key := fmt.Sprintf("%s@@%s@@%s@@%s@@%s:%d", namespace, group, name, host.ClusterName, host.Ip, host.Port)
+			if !seen[key] {
+				seen[key] = true
+				instances = append(instances, instance)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read naming cache '%s': %w", dir, err)
+	}
+	return instances, nil
+}
@@ -0,0 +1,85 @@
+package file_utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	t "static_analyser/pkg/types"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+func ReadNetworkPolicies(paths []string) ([]t.NetworkPolicy, error) {
+	// ReadNetworkPolicies reads the NetworkPolicies of YAML or JSON files, such as those written by kubectl or applied with it.
+	// Files may hold several documents, and List documents are searched for NetworkPolicy items. Documents of other kinds are skipped.
+	//
+	// paths: The files to read. Directories are searched for .yaml, .yml and .json files.
+	//
+	// Returns:
+	// The NetworkPolicies, in the order they were read.
+	// An error if a file could not be read or holds invalid YAML.
+
+	files := []string{}
+	for _, path := range paths {
+		err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(file))
+			if !info.IsDir() && (file == path || ext == ".yaml" || ext == ".yml" || ext == ".json") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policies '%s': %w", path, err)
+		}
+	}
+
+	policies := []t.NetworkPolicy{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file '%s': %w", file, err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var document interface{}
+			if err := decoder.Decode(&document); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to parse policy file '%s': %w", file, err)
+			}
+			// Round trip the document to decode it by kind
+			raw, err := yaml.Marshal(document)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse policy file '%s': %w", file, err)
+			}
+			var list struct {
+				Kind  string            `yaml:"kind"`
+				Items []t.NetworkPolicy `yaml:"items"`
+			}
+			if err := yaml.Unmarshal(raw, &list); err != nil {
+				continue
+			}
+			switch list.Kind {
+			case "NetworkPolicy":
+				var policy t.NetworkPolicy
+				if err := yaml.Unmarshal(raw, &policy); err != nil {
+					return nil, fmt.Errorf("invalid NetworkPolicy in '%s': %w", file, err)
+				}
+				policies = append(policies, policy)
+			case "List", "NetworkPolicyList":
+				for _, item := range list.Items {
+					if item.Kind == "" || item.Kind == "NetworkPolicy" {
+						policies = append(policies, item)
+					}
+				}
+			}
+		}
+	}
+	return policies, nil
+}
@@ -0,0 +1,45 @@
+package file_utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func ReadTCPManifestsFromJSON(dir string) (map[string]t.TCPManifest, error) {
+	// ReadTCPManifestsFromJSON reads the TCPManifests written by WriteTCPManifestToJSON from a directory.
+	//
+	// dir: The directory holding the manifest files.
+	//
+	// Returns:
+	// A map where the keys are the names of the services and the values are the corresponding TCPManifests.
+	// JSON files that are not manifests are skipped.
+	// An error if the directory or one of its JSON files could not be read.
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest directory '%s': %w", dir, err)
+	}
+
+	manifests := make(map[string]t.TCPManifest)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		jsonData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest file '%s': %w", path, err)
+		}
+
+		var manifest t.TCPManifest
+		if err := json.Unmarshal(jsonData, &manifest); err != nil || manifest.Service == "" {
+			continue
+		}
+		manifests[manifest.Service] = manifest
+	}
+	return manifests, nil
+}
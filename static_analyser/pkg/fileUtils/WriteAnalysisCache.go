@@ -0,0 +1,46 @@
+package file_utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	t "static_analyser/pkg/types"
+)
+
+func WriteAnalysisCache(dir string, analysis t.ApplicationAnalysis) error {
+	// WriteAnalysisCache writes the analysis of an application to the cache, in a file named after the hash of its inputs.
+	//
+	// dir: The cache directory. It is created if missing.
+	// analysis: The ApplicationAnalysis to write.
+	//
+	// Returns:
+	// An error if there was a problem converting the analysis to JSON or writing the file.
+
+	jsonData, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis of '%s': %w", analysis.Application, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory '%s': %w", dir, err)
+	}
+
+	// Write to a temporary file first, so that concurrent runs never read a partial entry
+	filename := filepath.Join(dir, analysis.Hash+".json")
+	temp, err := os.CreateTemp(dir, analysis.Hash+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache file '%s': %w", filename, err)
+	}
+	_, err = temp.Write(jsonData)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), filename)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write cache file '%s': %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package flows
+
+import (
+	"net"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strconv"
+	"strings"
+)
+
+func CompareFlows(g t.ServiceGraph, flows []t.ObservedFlow) t.FlowReport {
+	// CompareFlows compares the flows observed at runtime with the edges of the service graph.
+	// A flow matches an edge between the same nodes whose port is the flow's port, a range holding it, or could not be
+	// resolved.
+	// Only flows opened by analysed workloads are compared, since the graph only holds their calls, and only edges
+	// that flows can be matched against, to workloads or to external IP addresses, can be reported as never observed.
+	//
+	// g: The service graph.
+	// flows: The observed flows, resolved with ResolveFlows.
+	//
+	// Returns:
+	// The confirmed flows, the observed flows no edge predicts, and the edges no flow confirms.
+
+	workloads := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if !node.External {
+			workloads[node.ID] = true
+		}
+	}
+
+	matches := func(edge t.GraphEdge, flow t.ObservedFlow) bool {
+		if edge.From != nodeID(flow.Source) || edge.To != nodeID(flow.Destination) {
+			return false
+		}
+		if edge.Port == flow.Port || !util.IsResolved(edge.Port) {
+			return true
+		}
+		first, last, ok := util.ParsePortRange(edge.Port)
+		port, err := strconv.Atoi(flow.Port)
+		return ok && err == nil && port >= first && port <= last
+	}
+
+	report := t.FlowReport{Confirmed: []t.ObservedFlow{}, ObservedNotPredicted: []t.ObservedFlow{}, PredictedNotObserved: []t.GraphEdge{}}
+	observed := make(map[int]bool)
+	for _, flow := range flows {
+		if !workloads[nodeID(flow.Source)] {
+			continue
+		}
+		confirmed := false
+		for i, edge := range g.Edges {
+			if matches(edge, flow) {
+				observed[i] = true
+				confirmed = true
+			}
+		}
+		if confirmed {
+			report.Confirmed = append(report.Confirmed, flow)
+		} else {
+			report.ObservedNotPredicted = append(report.ObservedNotPredicted, flow)
+		}
+	}
+
+	for i, edge := range g.Edges {
+		verifiable := workloads[edge.To] || (strings.HasPrefix(edge.To, graph.ExternalPrefix) && net.ParseIP(strings.TrimPrefix(edge.To, graph.ExternalPrefix)) != nil)
+		if verifiable && !observed[i] {
+			report.PredictedNotObserved = append(report.PredictedNotObserved, edge)
+		}
+	}
+	return report
+}
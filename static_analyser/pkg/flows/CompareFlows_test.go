@@ -0,0 +1,64 @@
+package flows
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestCompareFlows(test *testing.T) {
+	// TestCompareFlows checks that flows confirm the edges whose port is theirs, a range holding it or unresolved, and
+	// that the other flows and edges are reported.
+	//
+	// test: The test.
+
+	g := t.ServiceGraph{
+		Nodes: []t.GraphNode{{ID: "gateway"}, {ID: "orders"}, {ID: "payments"}, {ID: "ledger"}},
+		Edges: []t.GraphEdge{
+			{From: "gateway", To: "orders", Protocol: "TCP", Port: "8080-8089"},
+			{From: "gateway", To: "payments", Protocol: "TCP", Port: "9000"},
+			{From: "gateway", To: "ledger", Protocol: "TCP", Port: "{port}"},
+		},
+	}
+	flow := func(destination string, port string) t.ObservedFlow {
+		return t.ObservedFlow{Source: t.FlowEndpoint{Workload: "gateway"}, Destination: t.FlowEndpoint{Workload: destination}, Port: port, Protocol: "tcp", Count: 1}
+	}
+
+	cases := []struct {
+		name        string
+		flows       []t.ObservedFlow
+		unpredicted []string
+		unobserved  []string
+	}{
+		{"port in the range", []t.ObservedFlow{flow("orders", "8083"), flow("payments", "9000"), flow("ledger", "5432")}, nil, nil},
+		{"first and last ports of the range", []t.ObservedFlow{flow("orders", "8080"), flow("orders", "8089"), flow("payments", "9000"), flow("ledger", "1")}, nil, nil},
+		{"port past the range", []t.ObservedFlow{flow("orders", "8090"), flow("payments", "9000"), flow("ledger", "5432")}, []string{"orders:8090"}, []string{"orders:8080-8089"}},
+		{"other port", []t.ObservedFlow{flow("orders", "8083"), flow("payments", "9001"), flow("ledger", "5432")}, []string{"payments:9001"}, []string{"payments:9000"}},
+		{"no flows", nil, nil, []string{"orders:8080-8089", "payments:9000", "ledger:{port}"}},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			report := CompareFlows(g, c.flows)
+			unpredicted := []string{}
+			for _, flow := range report.ObservedNotPredicted {
+				unpredicted = append(unpredicted, flow.Destination.Workload+":"+flow.Port)
+			}
+			unobserved := []string{}
+			for _, edge := range report.PredictedNotObserved {
+				unobserved = append(unobserved, edge.To+":"+edge.Port)
+			}
+			if len(unpredicted) != len(c.unpredicted) || len(unobserved) != len(c.unobserved) {
+				test.Fatalf("got observed not predicted %v and predicted not observed %v, want %v and %v", unpredicted, unobserved, c.unpredicted, c.unobserved)
+			}
+			for i := range unpredicted {
+				if unpredicted[i] != c.unpredicted[i] {
+					test.Errorf("got observed not predicted %v, want %v", unpredicted, c.unpredicted)
+				}
+			}
+			for i := range unobserved {
+				if unobserved[i] != c.unobserved[i] {
+					test.Errorf("got predicted not observed %v, want %v", unobserved, c.unobserved)
+				}
+			}
+		})
+	}
+}
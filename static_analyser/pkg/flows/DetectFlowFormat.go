@@ -0,0 +1,33 @@
+package flows
+
+import (
+	"strings"
+)
+
+func DetectFlowFormat(content string) string {
+	// DetectFlowFormat guesses the format of a flow log from its first non-empty line.
+	//
+	// content: The content of the flow log.
+	//
+	// Returns:
+	// "hubble" for Cilium Hubble JSON, "calico" for Calico flow logs, "conntrack" for conntrack dumps,
+	// "vpc" for AWS VPC flow logs, or an empty string if the format is not recognised.
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "{") && (strings.Contains(line, `"source_name_aggr"`) || strings.Contains(line, `"dest_port"`)):
+			return "calico"
+		case strings.HasPrefix(line, "{"):
+			return "hubble"
+		case strings.Contains(line, "src=") && strings.Contains(line, "dst="):
+			return "conntrack"
+		case strings.Contains(line, "srcaddr") || len(strings.Fields(line)) >= 14:
+			return "vpc"
+		}
+		return ""
+	}
+	return ""
+}
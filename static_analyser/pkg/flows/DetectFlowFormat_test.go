@@ -0,0 +1,34 @@
+package flows
+
+import (
+	"testing"
+)
+
+func TestDetectFlowFormat(test *testing.T) {
+	// TestDetectFlowFormat checks the format guessed from the first non-empty line of each kind of flow log.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"hubble", `{"flow":{"verdict":"FORWARDED"}}`, "hubble"},
+		{"calico", `{"source_name_aggr":"orders-*","dest_port":8080}`, "calico"},
+		{"calico without aggregation", `{"source_ip":"10.0.0.1","dest_port":8080}`, "calico"},
+		{"conntrack", "ipv4 2 tcp 6 431999 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 sport=51000 dport=8080", "conntrack"},
+		{"vpc with header", "version account-id interface-id srcaddr dstaddr srcport dstport protocol packets bytes start end action log-status", "vpc"},
+		{"vpc without header", "2 123456789010 eni-1235b8ca 10.0.0.1 10.0.0.2 51000 8080 6 20 4249 1418530010 1418530070 ACCEPT OK", "vpc"},
+		{"leading blank lines", "\n  \n" + `{"flow":{}}`, "hubble"},
+		{"unknown", "10.0.0.1 -> 10.0.0.2", ""},
+		{"empty", "", ""},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := DetectFlowFormat(c.content); got != c.want {
+				test.Errorf("got format %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package flows
+
+import (
+	"encoding/json"
+	"fmt"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func ParseCalicoFlows(content string) ([]t.ObservedFlow, error) {
+	// ParseCalicoFlows parses Calico flow logs, one JSON object per line, as written to /var/log/calico/flowlogs or exported from Elasticsearch.
+	// Denied flows are skipped. Flows between two pods are reported by both ends, so destination reports are only used
+	// when the source is not a pod.
+	//
+	// content: The content of the flow log.
+	//
+	// Returns:
+	// The observed flows, with the workloads taken from the app labels of the endpoints.
+	// An error if a line is not valid JSON.
+
+	type labels struct {
+		Labels []string `json:"labels"`
+	}
+	type flow struct {
+		SourceIP        *string `json:"source_ip"`
+		SourceName      string  `json:"source_name"`
+		SourceNamespace string  `json:"source_namespace"`
+		SourceType      string  `json:"source_type"`
+		SourceLabels    *labels `json:"source_labels"`
+		DestIP          *string `json:"dest_ip"`
+		DestName        string  `json:"dest_name"`
+		DestNamespace   string  `json:"dest_namespace"`
+		DestLabels      *labels `json:"dest_labels"`
+		DestPort        *int    `json:"dest_port"`
+		Proto           string  `json:"proto"`
+		Action          string  `json:"action"`
+		Reporter        string  `json:"reporter"`
+		NumFlows        int     `json:"num_flows"`
+	}
+
+	toEndpoint := func(ip *string, name string, namespace string, l *labels) t.FlowEndpoint {
+		endpoint := t.FlowEndpoint{}
+		if ip != nil {
+			endpoint.IP = *ip
+		}
+		if name != "-" && !strings.HasSuffix(name, "*") {
+			endpoint.Pod = name
+		}
+		if namespace != "-" {
+			endpoint.Namespace = namespace
+		}
+		if l != nil {
+			values := make(map[string]string)
+			for _, label := range l.Labels {
+				key, value, _ := strings.Cut(label, "=")
+				values[key] = value
+			}
+			endpoint.Workload = util.WorkloadLabel(values)
+		}
+		return endpoint
+	}
+
+	flows := []t.ObservedFlow{}
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var f flow
+		if err := json.Unmarshal([]byte(line), &f); err != nil {
+			return nil, fmt.Errorf("invalid Calico flow on line %d: %w", i+1, err)
+		}
+		if f.Action == "deny" || f.DestPort == nil || (f.Reporter == "dst" && f.SourceType == "wep") {
+			continue
+		}
+		count := f.NumFlows
+		if count == 0 {
+			count = 1
+		}
+		flows = append(flows, t.ObservedFlow{
+			Source:      toEndpoint(f.SourceIP, f.SourceName, f.SourceNamespace, f.SourceLabels),
+			Destination: toEndpoint(f.DestIP, f.DestName, f.DestNamespace, f.DestLabels),
+			Port:        fmt.Sprint(*f.DestPort),
+			Protocol:    strings.ToLower(f.Proto),
+			Count:       count,
+		})
+	}
+	return flows, nil
+}
@@ -0,0 +1,47 @@
+package flows
+
+import (
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func ParseConntrack(content string) ([]t.ObservedFlow, error) {
+	// ParseConntrack parses a connection tracking table, as printed by "conntrack -L" or read from /proc/net/nf_conntrack.
+	// The first tuple of each entry is the original direction, so its source opened the connection.
+	//
+	// content: The content of the dump.
+	//
+	// Returns:
+	// The observed flows, identified by IP address only.
+	// An error is never returned; malformed entries are skipped.
+
+	flows := []t.ObservedFlow{}
+	for _, line := range strings.Split(content, "\n") {
+		protocol := ""
+		tuple := make(map[string]string)
+		for _, field := range strings.Fields(line) {
+			switch field {
+			case "tcp", "udp", "sctp":
+				if protocol == "" {
+					protocol = field
+				}
+				continue
+			}
+			key, value, found := strings.Cut(field, "=")
+			if _, seen := tuple[key]; found && !seen {
+				tuple[key] = value
+			}
+		}
+		if protocol == "" || tuple["src"] == "" || tuple["dst"] == "" || tuple["dport"] == "" {
+			continue
+		}
+		flows = append(flows, t.ObservedFlow{
+			Source:      t.FlowEndpoint{IP: tuple["src"]},
+			Destination: t.FlowEndpoint{IP: tuple["dst"]},
+			Port:        tuple["dport"],
+			Protocol:    protocol,
+			Count:       1,
+		})
+	}
+	return flows, nil
+}
@@ -0,0 +1,34 @@
+package flows
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+)
+
+func ParseFlowLog(content string, format string) ([]t.ObservedFlow, error) {
+	// ParseFlowLog parses a flow log in one of the supported formats.
+	//
+	// content: The content of the flow log.
+	// format: "hubble", "calico", "vpc", "conntrack", or "auto" to detect the format with DetectFlowFormat.
+	//
+	// Returns:
+	// The observed flows.
+	// An error if the format is unknown or could not be detected, or if the flow log is malformed.
+
+	if format == "auto" {
+		if format = DetectFlowFormat(content); format == "" {
+			return nil, fmt.Errorf("could not detect the flow log format")
+		}
+	}
+	switch format {
+	case "hubble":
+		return ParseHubbleFlows(content)
+	case "calico":
+		return ParseCalicoFlows(content)
+	case "vpc":
+		return ParseVPCFlowLogs(content)
+	case "conntrack":
+		return ParseConntrack(content)
+	}
+	return nil, fmt.Errorf("unknown flow log format %q", format)
+}
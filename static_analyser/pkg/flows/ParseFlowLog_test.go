@@ -0,0 +1,118 @@
+package flows
+
+import (
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestParseFlowLog(test *testing.T) {
+	// TestParseFlowLog checks the flows read from each supported format, given explicitly or detected, and the records
+	// each parser skips: replies, denied and rejected flows, flows reported twice and malformed entries.
+	//
+	// test: The test.
+
+	flow := func(source t.FlowEndpoint, destination t.FlowEndpoint, port string, protocol string, count int) t.ObservedFlow {
+		return t.ObservedFlow{Source: source, Destination: destination, Port: port, Protocol: protocol, Count: count}
+	}
+	ip := func(address string) t.FlowEndpoint {
+		return t.FlowEndpoint{IP: address}
+	}
+	orders := t.FlowEndpoint{IP: "10.0.0.1", Workload: "orders", Namespace: "shop", Pod: "orders-1"}
+	payments := t.FlowEndpoint{IP: "10.0.0.2", Workload: "payments", Namespace: "shop", Pod: "payments-1"}
+
+	cases := []struct {
+		name    string
+		format  string
+		content string
+		want    []t.ObservedFlow
+		wantErr bool
+	}{
+		{
+			name:   "hubble",
+			format: "hubble",
+			content: `{"flow":{"verdict":"FORWARDED","IP":{"source":"10.0.0.1","destination":"10.0.0.2"},"l4":{"TCP":{"destination_port":8080}},"source":{"namespace":"shop","labels":["k8s:app=orders"],"pod_name":"orders-1"},"destination":{"namespace":"shop","labels":["k8s:app=payments"],"pod_name":"payments-1"}}}
+{"flow":{"verdict":"FORWARDED","IP":{"source":"10.0.0.2","destination":"10.0.0.1"},"l4":{"TCP":{"destination_port":51000}},"is_reply":true}}
+{"flow":{"verdict":"DROPPED","IP":{"source":"10.0.0.1","destination":"10.0.0.3"},"l4":{"TCP":{"destination_port":9090}}}}
+{"verdict":"FORWARDED","IP":{"source":"10.0.0.1","destination":"10.0.0.4"},"l4":{"UDP":{"destination_port":53}}}
+{"flow":{"verdict":"FORWARDED","IP":{"source":"10.0.0.1","destination":"10.0.0.5"},"l4":{"ICMPv4":{}}}}`,
+			want: []t.ObservedFlow{
+				flow(orders, payments, "8080", "tcp", 1),
+				flow(ip("10.0.0.1"), ip("10.0.0.4"), "53", "udp", 1),
+			},
+		},
+		{
+			name:    "invalid hubble",
+			format:  "hubble",
+			content: `{"flow":`,
+			wantErr: true,
+		},
+		{
+			name:   "calico",
+			format: "calico",
+			content: `{"source_ip":"10.0.0.1","source_name":"orders-1","source_namespace":"shop","source_type":"wep","source_labels":{"labels":["app=orders"]},"dest_ip":"10.0.0.2","dest_name":"payments-1","dest_namespace":"shop","dest_labels":{"labels":["app=payments"]},"dest_port":8080,"proto":"TCP","action":"allow","reporter":"src","num_flows":3}
+{"source_ip":"10.0.0.1","source_name":"orders-1","source_namespace":"shop","source_type":"wep","dest_ip":"10.0.0.2","dest_port":8080,"proto":"TCP","action":"allow","reporter":"dst"}
+{"source_ip":"10.0.0.1","source_name":"orders-1","source_namespace":"shop","source_type":"wep","dest_ip":"10.0.0.3","dest_port":9090,"proto":"TCP","action":"deny","reporter":"src"}
+{"source_ip":"192.168.1.10","source_name":"-","source_namespace":"-","source_type":"net","dest_ip":"10.0.0.2","dest_name":"payments-*","dest_namespace":"shop","dest_port":8080,"proto":"TCP","action":"allow","reporter":"dst"}`,
+			want: []t.ObservedFlow{
+				flow(orders, payments, "8080", "tcp", 3),
+				flow(ip("192.168.1.10"), t.FlowEndpoint{IP: "10.0.0.2", Namespace: "shop"}, "8080", "tcp", 1),
+			},
+		},
+		{
+			name:   "vpc",
+			format: "vpc",
+			content: `2 123456789010 eni-1235b8ca 10.0.0.1 10.0.0.2 51000 8080 6 20 4249 1418530010 1418530070 ACCEPT OK
+2 123456789010 eni-1235b8ca 10.0.0.2 10.0.0.1 8080 51000 6 20 4249 1418530010 1418530070 ACCEPT OK
+2 123456789010 eni-1235b8ca 10.0.0.1 10.0.0.3 51001 22 6 20 4249 1418530010 1418530070 REJECT OK
+2 123456789010 eni-1235b8ca - - - - - - - 1418530010 1418530070 - NODATA
+version srcaddr dstaddr srcport dstport protocol action
+5 10.0.0.1 10.0.0.4 51002 53 17 ACCEPT`,
+			want: []t.ObservedFlow{
+				flow(ip("10.0.0.1"), ip("10.0.0.2"), "8080", "tcp", 1),
+				flow(ip("10.0.0.1"), ip("10.0.0.4"), "53", "udp", 1),
+			},
+		},
+		{
+			name:   "conntrack",
+			format: "conntrack",
+			content: `ipv4     2 tcp      6 431999 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 sport=51000 dport=8080 src=10.0.0.2 dst=10.0.0.1 sport=8080 dport=51000 [ASSURED] mark=0 use=1
+udp      17 29 src=10.0.0.1 dst=10.0.0.4 sport=51002 dport=53 [UNREPLIED] src=10.0.0.4 dst=10.0.0.1 sport=53 dport=51002 mark=0 use=1
+icmp     1 29 src=10.0.0.1 dst=10.0.0.5 type=8 code=0 id=1
+conntrack v1.4.6 (conntrack-tools): 2 flow entries have been shown.`,
+			want: []t.ObservedFlow{
+				flow(ip("10.0.0.1"), ip("10.0.0.2"), "8080", "tcp", 1),
+				flow(ip("10.0.0.1"), ip("10.0.0.4"), "53", "udp", 1),
+			},
+		},
+		{
+			name:    "detected",
+			format:  "auto",
+			content: "tcp 6 431999 ESTABLISHED src=10.0.0.1 dst=10.0.0.2 sport=51000 dport=8080",
+			want:    []t.ObservedFlow{flow(ip("10.0.0.1"), ip("10.0.0.2"), "8080", "tcp", 1)},
+		},
+		{
+			name:    "undetectable",
+			format:  "auto",
+			content: "10.0.0.1 -> 10.0.0.2",
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "netflow",
+			content: "",
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			got, err := ParseFlowLog(c.content, c.format)
+			if (err != nil) != c.wantErr {
+				test.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got flows %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package flows
+
+import (
+	"encoding/json"
+	"fmt"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func ParseHubbleFlows(content string) ([]t.ObservedFlow, error) {
+	// ParseHubbleFlows parses Cilium Hubble flows, as printed by "hubble observe -o json", one JSON object per line.
+	// Replies and flows with a verdict other than FORWARDED are skipped.
+	//
+	// content: The content of the flow log.
+	//
+	// Returns:
+	// The observed flows, with the workloads taken from the k8s:app labels of the endpoints.
+	// An error if a line is not valid JSON.
+
+	type endpoint struct {
+		Namespace string   `json:"namespace"`
+		Labels    []string `json:"labels"`
+		PodName   string   `json:"pod_name"`
+	}
+	type ports struct {
+		DestinationPort uint32 `json:"destination_port"`
+	}
+	type flow struct {
+		Verdict string `json:"verdict"`
+		IP      struct {
+			Source      string `json:"source"`
+			Destination string `json:"destination"`
+		} `json:"IP"`
+		L4 struct {
+			TCP  *ports `json:"TCP"`
+			UDP  *ports `json:"UDP"`
+			SCTP *ports `json:"SCTP"`
+		} `json:"l4"`
+		Source      endpoint `json:"source"`
+		Destination endpoint `json:"destination"`
+		IsReply     bool     `json:"is_reply"`
+	}
+
+	toEndpoint := func(ip string, e endpoint) t.FlowEndpoint {
+		labels := make(map[string]string)
+		for _, label := range e.Labels {
+			key, value, _ := strings.Cut(strings.TrimPrefix(label, "k8s:"), "=")
+			labels[key] = value
+		}
+		return t.FlowEndpoint{IP: ip, Workload: util.WorkloadLabel(labels), Namespace: e.Namespace, Pod: e.PodName}
+	}
+
+	flows := []t.ObservedFlow{}
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var wrapper struct {
+			Flow *flow `json:"flow"`
+		}
+		if err := json.Unmarshal([]byte(line), &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid Hubble flow on line %d: %w", i+1, err)
+		}
+		f := wrapper.Flow
+		if f == nil {
+			// Older versions of hubble print the flow without the wrapping object
+			f = &flow{}
+			json.Unmarshal([]byte(line), f)
+		}
+		if f.IsReply || (f.Verdict != "" && f.Verdict != "FORWARDED") {
+			continue
+		}
+
+		observed := t.ObservedFlow{Source: toEndpoint(f.IP.Source, f.Source), Destination: toEndpoint(f.IP.Destination, f.Destination), Count: 1}
+		switch {
+		case f.L4.TCP != nil:
+			observed.Protocol, observed.Port = "tcp", fmt.Sprint(f.L4.TCP.DestinationPort)
+		case f.L4.UDP != nil:
+			observed.Protocol, observed.Port = "udp", fmt.Sprint(f.L4.UDP.DestinationPort)
+		case f.L4.SCTP != nil:
+			observed.Protocol, observed.Port = "sctp", fmt.Sprint(f.L4.SCTP.DestinationPort)
+		default:
+			continue
+		}
+		flows = append(flows, observed)
+	}
+	return flows, nil
+}
@@ -0,0 +1,58 @@
+package flows
+
+import (
+	t "static_analyser/pkg/types"
+	"strconv"
+	"strings"
+)
+
+func ParseVPCFlowLogs(content string) ([]t.ObservedFlow, error) {
+	// ParseVPCFlowLogs parses AWS VPC flow log records, in the default version 2 format or in a custom format
+	// introduced by a header line naming the fields. Rejected records, records without data and records that look
+	// like the reply direction of a connection, from a low port to an ephemeral port, are skipped.
+	//
+	// content: The content of the flow log.
+	//
+	// Returns:
+	// The observed flows, identified by IP address only.
+	// An error is never returned; malformed records are skipped.
+
+	fields := []string{"version", "account-id", "interface-id", "srcaddr", "dstaddr", "srcport", "dstport", "protocol", "packets", "bytes", "start", "end", "action", "log-status"}
+	protocols := map[string]string{"6": "tcp", "17": "udp", "132": "sctp"}
+
+	flows := []t.ObservedFlow{}
+	for _, line := range strings.Split(content, "\n") {
+		values := strings.Fields(line)
+		if len(values) == 0 {
+			continue
+		}
+		if strings.Contains(line, "srcaddr") {
+			fields = values
+			continue
+		}
+		record := make(map[string]string)
+		for i, field := range fields {
+			if i < len(values) {
+				record[field] = values[i]
+			}
+		}
+
+		protocol, ok := protocols[record["protocol"]]
+		if !ok || record["action"] == "REJECT" || record["srcaddr"] == "-" || record["dstaddr"] == "-" {
+			continue
+		}
+		srcPort, err1 := strconv.Atoi(record["srcport"])
+		dstPort, err2 := strconv.Atoi(record["dstport"])
+		if err1 != nil || err2 != nil || (dstPort >= 32768 && srcPort < dstPort) {
+			continue
+		}
+		flows = append(flows, t.ObservedFlow{
+			Source:      t.FlowEndpoint{IP: record["srcaddr"]},
+			Destination: t.FlowEndpoint{IP: record["dstaddr"]},
+			Port:        record["dstport"],
+			Protocol:    protocol,
+			Count:       1,
+		})
+	}
+	return flows, nil
+}
@@ -0,0 +1,43 @@
+package flows
+
+import (
+	"fmt"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderFlowReport(report t.FlowReport) string {
+	// RenderFlowReport renders a FlowReport as text.
+	//
+	// report: The report to render.
+	//
+	// Returns:
+	// The observed flows no edge predicts and the predicted edges no flow confirms, one per line, followed by a summary line.
+
+	describe := func(endpoint t.FlowEndpoint) string {
+		if endpoint.Workload != "" {
+			return endpoint.Workload
+		}
+		return endpoint.IP
+	}
+
+	var b strings.Builder
+	if len(report.ObservedNotPredicted) > 0 {
+		b.WriteString("Observed but not predicted:\n")
+		for _, flow := range report.ObservedNotPredicted {
+			fmt.Fprintf(&b, "  %s -> %s %s/%s (%d flows)\n", describe(flow.Source), describe(flow.Destination), flow.Protocol, flow.Port, flow.Count)
+		}
+		b.WriteString("\n")
+	}
+	if len(report.PredictedNotObserved) > 0 {
+		b.WriteString("Predicted but never observed:\n")
+		for _, edge := range report.PredictedNotObserved {
+			fmt.Fprintf(&b, "  %s -> %s %s\n", edge.From, strings.TrimPrefix(edge.To, graph.ExternalPrefix), graph.EdgeLabel(edge))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d confirmed, %d observed but not predicted, %d predicted but never observed\n",
+		len(report.Confirmed), len(report.ObservedNotPredicted), len(report.PredictedNotObserved))
+	return b.String()
+}
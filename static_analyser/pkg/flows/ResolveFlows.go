@@ -0,0 +1,85 @@
+package flows
+
+import (
+	"sort"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func ResolveFlows(flows []t.ObservedFlow, inventory map[string]t.FlowEndpoint) []t.ObservedFlow {
+	// ResolveFlows maps the endpoints of observed flows to workloads and merges the flows between the same endpoints.
+	//
+	// flows: The observed flows.
+	// inventory: The pods and services of the cluster, see f_util.ReadKubernetesInventory. May be nil.
+	//
+	// Returns:
+	// One flow per source, destination, protocol and port, with the counts summed. Endpoints without a workload
+	// are looked up in the inventory by pod name and then by IP address. The IP address and pod of an endpoint are kept
+	// if every merged flow has the same. The flows are sorted by source, destination and port.
+
+	resolve := func(endpoint t.FlowEndpoint) t.FlowEndpoint {
+		if endpoint.Workload != "" {
+			return endpoint
+		}
+		known, ok := inventory[endpoint.Namespace+"/"+endpoint.Pod]
+		if !ok || endpoint.Pod == "" {
+			known, ok = inventory[endpoint.IP]
+		}
+		if ok {
+			endpoint.Workload = known.Workload
+			if endpoint.Namespace == "" {
+				endpoint.Namespace = known.Namespace
+			}
+			if endpoint.Pod == "" {
+				endpoint.Pod = known.Pod
+			}
+		}
+		return endpoint
+	}
+
+	merged := make(map[string]*t.ObservedFlow)
+	keys := []string{}
+	for _, flow := range flows {
+		flow.Source = resolve(flow.Source)
+		flow.Destination = resolve(flow.Destination)
+		key := strings.Join([]string{nodeID(flow.Source), nodeID(flow.Destination), flow.Protocol, flow.Port}, "\x00")
+		if existing, ok := merged[key]; ok {
+			existing.Count += flow.Count
+			// Merged flows keep only what their endpoints have in common, such as the IP of a workload with a single pod
+			existing.Source = common(existing.Source, flow.Source)
+			existing.Destination = common(existing.Destination, flow.Destination)
+			continue
+		}
+		merged[key] = &flow
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := []t.ObservedFlow{}
+	for _, key := range keys {
+		result = append(result, *merged[key])
+	}
+	return result
+}
+
+func common(a t.FlowEndpoint, b t.FlowEndpoint) t.FlowEndpoint {
+	// common returns what two endpoints of the same node have in common: the IP address and pod are cleared if they differ.
+
+	if a.IP != b.IP {
+		a.IP = ""
+	}
+	if a.Pod != b.Pod {
+		a.Pod = ""
+	}
+	return a
+}
+
+func nodeID(endpoint t.FlowEndpoint) string {
+	// nodeID returns the ID of the service graph node an endpoint corresponds to: its workload, or its IP as an external host.
+
+	if endpoint.Workload != "" {
+		return endpoint.Workload
+	}
+	return graph.ExternalPrefix + endpoint.IP
+}
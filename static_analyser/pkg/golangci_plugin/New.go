@@ -0,0 +1,65 @@
+package golangci_plugin
+
+import (
+	"fmt"
+	"static_analyser/pkg/analyzer"
+	t "static_analyser/pkg/types"
+	"strings"
+
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+)
+
+// plugin adapts the Nacos usage analyzer to the module plugin system of golangci-lint.
+type plugin struct {
+	settings t.LintSettings
+}
+
+func init() {
+	register.Plugin("nacoslint", New)
+}
+
+func New(settings interface{}) (register.LinterPlugin, error) {
+	// New creates the golangci-lint plugin from the settings of the "nacoslint" custom linter.
+	//
+	// settings: The settings of the linter in .golangci.yml, see t.LintSettings.
+	//
+	// Returns:
+	// The plugin.
+	// An error if the settings are invalid.
+
+	lintSettings, err := register.DecodeSettings[t.LintSettings](settings)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin{settings: lintSettings}, nil
+}
+
+func (p *plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	// BuildAnalyzers configures the analyzer with the settings of the plugin.
+	//
+	// Returns:
+	// The analyzers of the plugin.
+	// An error if a setting is invalid, such as an unknown rule.
+
+	flags := map[string]string{
+		"root":    p.settings.Root,
+		"enable":  strings.Join(p.settings.Enable, ","),
+		"disable": strings.Join(p.settings.Disable, ","),
+	}
+	for name, value := range flags {
+		if err := analyzer.Analyzer.Flags.Set(name, value); err != nil {
+			return nil, fmt.Errorf("invalid nacoslint setting %s: %w", name, err)
+		}
+	}
+	return []*analysis.Analyzer{analyzer.Analyzer}, nil
+}
+
+func (p *plugin) GetLoadMode() string {
+	// GetLoadMode tells golangci-lint the analyzer needs type information, which facts depend on.
+	//
+	// Returns:
+	// The load mode.
+
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"sort"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+// UnresolvedPrefix prefixes the IDs of nodes for discovered services that no workload registers.
+const UnresolvedPrefix = "unresolved:"
+
+// ExternalPrefix prefixes the IDs of nodes for hosts that are called directly and belong to no analysed workload.
+const ExternalPrefix = "external:"
+
+func BuildServiceGraph(parsedYamls map[string]*t.Yaml2Go, manifests map[string]t.TCPManifest) t.ServiceGraph {
+	// BuildServiceGraph builds the service graph from the parsed YAML files and the TCPManifests of the applications.
+	//
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	// manifests: A map where the keys are the names of the applications and the values are the corresponding TCPManifests.
+	//
+	// Returns:
+	// A ServiceGraph with one node per application, one node per unresolved discovery target or external host and one edge per distinct call.
+	// Nodes and edges are sorted so that the output is stable.
+
+	nodes := make(map[string]t.GraphNode)
+	edges := make(map[string]*t.GraphEdge)
+
+	for application, manifest := range manifests {
+		namespace := "default"
+		if conf, ok := parsedYamls[application]; ok && conf.Metadata.Namespace != "" {
+			namespace = conf.Metadata.Namespace
+		}
+		nodes[application] = t.GraphNode{ID: application, Label: application, Namespace: namespace, Version: manifest.Version}
+	}
+
+	for application, manifest := range manifests {
+		for _, req := range manifest.Requests {
+			to := req.Name
+			resolved := to != ""
+			if !resolved {
+				// The target is not an analysed workload, so it gets a node of its own
+				label := req.ServiceName
+				to = UnresolvedPrefix + req.ServiceName
+				if req.ServiceName == "" && req.URL != "" {
+					// A host called directly is known even though no workload owns it
+					label = req.URL
+					to = ExternalPrefix + req.URL
+					resolved = true
+				}
+				if _, ok := nodes[to]; !ok {
+					nodes[to] = t.GraphNode{ID: to, Label: label, External: true}
+				}
+			}
+
+			key := strings.Join([]string{application, to, req.Type, req.Port, req.Kind, req.ServiceName, req.Method, req.Path}, "\x00")
+			edge, ok := edges[key]
+			if !ok {
+				edge = &t.GraphEdge{From: application, To: to, Protocol: req.Type, Port: req.Port, Kind: req.Kind, Method: req.Method, Path: req.Path, ServiceName: req.ServiceName, Resolved: resolved}
+				edges[key] = edge
+			}
+			if req.Location != "" {
+				edge.Locations = append(edge.Locations, req.Location)
+			}
+		}
+	}
+
+	g := t.ServiceGraph{}
+	for _, node := range nodes {
+		g.Nodes = append(g.Nodes, node)
+	}
+	for _, edge := range edges {
+		sort.Strings(edge.Locations)
+		g.Edges = append(g.Edges, *edge)
+	}
+	sortGraph(&g)
+
+	return g
+}
+
+func sortGraph(g *t.ServiceGraph) {
+	// sortGraph sorts the nodes of a graph by ID and its edges by endpoints, port and kind.
+	//
+	// g: The graph to sort in place.
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Path < b.Path
+	})
+}
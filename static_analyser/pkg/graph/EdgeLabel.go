@@ -0,0 +1,30 @@
+package graph
+
+import (
+	t "static_analyser/pkg/types"
+)
+
+func EdgeLabel(edge t.GraphEdge) string {
+	// EdgeLabel builds the display label of an edge from its protocol, port and call kind.
+	//
+	// edge: The edge to label.
+	//
+	// Returns:
+	// A label such as "tcp/8080 SelectInstances GET /hello". Unknown ports are shown as "?".
+
+	port := edge.Port
+	if port == "" {
+		port = "?"
+	}
+	label := edge.Protocol + "/" + port
+	if edge.Kind != "" {
+		label += " " + edge.Kind
+	}
+	if edge.Method != "" {
+		label += " " + edge.Method
+	}
+	if edge.Path != "" {
+		label += " " + edge.Path
+	}
+	return label
+}
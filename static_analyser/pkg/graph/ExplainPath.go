@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func ExplainPath(path t.GraphPath) string {
+	// ExplainPath describes a path hop by hop, citing the source locations of the calls behind each hop.
+	//
+	// path: The path to describe.
+	//
+	// Returns:
+	// A multi-line description of the path.
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s -> %s (%d hops)\n", path.From, path.To, len(path.Hops))
+	for i, hop := range path.Hops {
+		fmt.Fprintf(&sb, "  %d. %s -> %s\n", i+1, hop.From, hop.To)
+		for _, edge := range hop.Edges {
+			fmt.Fprintf(&sb, "     via %s", EdgeLabel(edge))
+			if edge.ServiceName != "" {
+				fmt.Fprintf(&sb, " (discovers %s)", edge.ServiceName)
+			}
+			if !edge.Resolved {
+				sb.WriteString(" [unresolved]")
+			}
+			sb.WriteString("\n")
+			for _, location := range edge.Locations {
+				fmt.Fprintf(&sb, "       at %s\n", location)
+			}
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestExplainPath(test *testing.T) {
+	// TestExplainPath checks that each hop of a path is described with its edges, the services they discover, whether
+	// they are resolved and the source locations of their calls.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name string
+		path t.GraphPath
+		want string
+	}{
+		{
+			name: "no hops",
+			path: t.GraphPath{From: "gateway", To: "gateway"},
+			want: "gateway -> gateway (0 hops)\n",
+		},
+		{
+			name: "hops with parallel and unresolved edges",
+			path: t.GraphPath{From: "gateway", To: "unresolved:ledger", Hops: []t.GraphHop{
+				{From: "gateway", To: "payments", Edges: []t.GraphEdge{
+					{Protocol: "tcp", Port: "8080", Kind: "SelectInstances", Method: "POST", Path: "/pay", ServiceName: "payments", Resolved: true, Locations: []string{"gateway/main.go:10", "gateway/main.go:12"}},
+					{Protocol: "tcp", Port: "9090", Kind: "grpc", Resolved: true},
+				}},
+				{From: "payments", To: "unresolved:ledger", Edges: []t.GraphEdge{
+					{Protocol: "tcp", Kind: "SelectOneHealthyInstance", ServiceName: "ledger", Locations: []string{"payments/main.go:30"}},
+				}},
+			}},
+			want: "gateway -> unresolved:ledger (2 hops)\n" +
+				"  1. gateway -> payments\n" +
+				"     via tcp/8080 SelectInstances POST /pay (discovers payments)\n" +
+				"       at gateway/main.go:10\n" +
+				"       at gateway/main.go:12\n" +
+				"     via tcp/9090 grpc\n" +
+				"  2. payments -> unresolved:ledger\n" +
+				"     via tcp/? SelectOneHealthyInstance (discovers ledger) [unresolved]\n" +
+				"       at payments/main.go:30\n",
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := ExplainPath(c.path); got != c.want {
+				test.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
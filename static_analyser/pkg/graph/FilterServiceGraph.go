@@ -0,0 +1,61 @@
+package graph
+
+import (
+	t "static_analyser/pkg/types"
+)
+
+func FilterServiceGraph(g t.ServiceGraph, service string, depth int) t.ServiceGraph {
+	// FilterServiceGraph restricts a service graph to the neighbourhood of a single service.
+	//
+	// g: The graph to filter.
+	// service: The ID of the service at the centre of the neighbourhood.
+	// depth: The maximum number of hops, in either direction, from the service.
+	//
+	// Returns:
+	// A ServiceGraph containing the nodes within depth hops of the service and the edges between them.
+	// The graph is empty if the service is not part of g.
+
+	neighbours := make(map[string][]string)
+	for _, edge := range g.Edges {
+		neighbours[edge.From] = append(neighbours[edge.From], edge.To)
+		neighbours[edge.To] = append(neighbours[edge.To], edge.From)
+	}
+
+	keep := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if node.ID == service {
+			keep[service] = true
+		}
+	}
+
+	// Breadth-first search outwards from the service, one hop per iteration
+	frontier := []string{}
+	if keep[service] {
+		frontier = append(frontier, service)
+	}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		next := []string{}
+		for _, id := range frontier {
+			for _, neighbour := range neighbours[id] {
+				if !keep[neighbour] {
+					keep[neighbour] = true
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	filtered := t.ServiceGraph{}
+	for _, node := range g.Nodes {
+		if keep[node.ID] {
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if keep[edge.From] && keep[edge.To] {
+			filtered.Edges = append(filtered.Edges, edge)
+		}
+	}
+	return filtered
+}
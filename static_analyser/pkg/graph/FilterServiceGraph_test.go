@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestFilterServiceGraph(test *testing.T) {
+	// TestFilterServiceGraph checks the nodes and edges kept around a service, following calls in both directions.
+	//
+	// test: The test.
+
+	// ids lists the IDs of the nodes of a graph and the ends of its edges
+	ids := func(g t.ServiceGraph) []string {
+		result := []string{}
+		for _, node := range g.Nodes {
+			result = append(result, node.ID)
+		}
+		for _, edge := range g.Edges {
+			result = append(result, edge.From+"->"+edge.To)
+		}
+		return result
+	}
+
+	cases := []struct {
+		name    string
+		service string
+		depth   int
+		want    []string
+	}{
+		{"service alone", "orders", 0, []string{"orders"}},
+		{"callers and callees", "orders", 1, []string{"gateway", "orders", "payments", "gateway->orders", "gateway->payments", "gateway->payments", "orders->payments"}},
+		{"two hops", "audit", 2, []string{"audit", "ledger", "payments", "payments->ledger", "audit->ledger"}},
+		{"whole graph", "audit", 10, []string{"audit", "gateway", "ledger", "orders", "payments", "gateway->orders", "gateway->payments", "gateway->payments", "orders->payments", "payments->ledger", "audit->ledger"}},
+		{"unknown service", "unknown", 2, []string{}},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := ids(FilterServiceGraph(queryGraph, c.service, c.depth)); !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"sort"
+	t "static_analyser/pkg/types"
+)
+
+func shortestPaths(g t.ServiceGraph, source string, reverse bool) map[string]t.GraphPath {
+	// shortestPaths runs a breadth-first search from a node and records the shortest path to every node it reaches.
+	//
+	// g: The graph to search.
+	// source: The ID of the node to start from.
+	// reverse: Whether to follow edges against their direction, to find callers instead of callees.
+	//
+	// Returns:
+	// A map where the keys are the IDs of the reached nodes and the values are the shortest paths, always in calling order.
+	// The source itself is not included.
+
+	// Group the parallel edges between each pair of nodes into a single hop
+	hops := make(map[string]map[string]*t.GraphHop)
+	for _, edge := range g.Edges {
+		from, to := edge.From, edge.To
+		if reverse {
+			from, to = to, from
+		}
+		if hops[from] == nil {
+			hops[from] = make(map[string]*t.GraphHop)
+		}
+		hop, ok := hops[from][to]
+		if !ok {
+			hop = &t.GraphHop{From: edge.From, To: edge.To}
+			hops[from][to] = hop
+		}
+		hop.Edges = append(hop.Edges, edge)
+	}
+
+	paths := make(map[string]t.GraphPath)
+	visited := map[string]bool{source: true}
+	frontier := []string{source}
+	for len(frontier) > 0 {
+		next := []string{}
+		for _, id := range frontier {
+			neighbours := []string{}
+			for neighbour := range hops[id] {
+				neighbours = append(neighbours, neighbour)
+			}
+			sort.Strings(neighbours)
+
+			for _, neighbour := range neighbours {
+				if visited[neighbour] {
+					continue
+				}
+				visited[neighbour] = true
+				next = append(next, neighbour)
+
+				hop := *hops[id][neighbour]
+				previous := paths[id].Hops
+				var path t.GraphPath
+				if reverse {
+					// Callers are found backwards, so the new hop goes in front
+					path = t.GraphPath{From: neighbour, To: source, Hops: append([]t.GraphHop{hop}, previous...)}
+				} else {
+					path = t.GraphPath{From: source, To: neighbour, Hops: append(append([]t.GraphHop{}, previous...), hop)}
+				}
+				paths[neighbour] = path
+			}
+		}
+		frontier = next
+	}
+	return paths
+}
+
+func sortedPaths(paths map[string]t.GraphPath) []t.GraphPath {
+	// sortedPaths orders paths by length and then by the ID of their far end.
+	//
+	// paths: A map of paths keyed by the ID of their far end.
+	//
+	// Returns:
+	// The paths as a sorted slice.
+
+	ids := []string{}
+	for id := range paths {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := len(paths[ids[i]].Hops), len(paths[ids[j]].Hops)
+		if a != b {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+
+	sorted := []t.GraphPath{}
+	for _, id := range ids {
+		sorted = append(sorted, paths[id])
+	}
+	return sorted
+}
+
+func FindReachable(g t.ServiceGraph, service string) []t.GraphPath {
+	// FindReachable finds every node a service can reach transitively.
+	//
+	// g: The graph to search.
+	// service: The ID of the service to start from.
+	//
+	// Returns:
+	// The shortest path from the service to each reachable node, nearest first.
+
+	return sortedPaths(shortestPaths(g, service, false))
+}
+
+func FindCallers(g t.ServiceGraph, service string, transitive bool) []t.GraphPath {
+	// FindCallers finds the nodes that call a service.
+	//
+	// g: The graph to search.
+	// service: The ID of the called service.
+	// transitive: Whether to include indirect callers as well as direct ones.
+	//
+	// Returns:
+	// The shortest path from each caller to the service, nearest first.
+
+	paths := shortestPaths(g, service, true)
+	if !transitive {
+		for id, path := range paths {
+			if len(path.Hops) > 1 {
+				delete(paths, id)
+			}
+		}
+	}
+	return sortedPaths(paths)
+}
+
+func FindShortestPath(g t.ServiceGraph, from string, to string) (t.GraphPath, bool) {
+	// FindShortestPath finds the shortest path between two nodes.
+	//
+	// g: The graph to search.
+	// from: The ID of the calling node.
+	// to: The ID of the called node.
+	//
+	// Returns:
+	// The shortest path, and false if to cannot be reached from from.
+
+	path, ok := shortestPaths(g, from, false)[to]
+	return path, ok
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"fmt"
+	"reflect"
+	t "static_analyser/pkg/types"
+	"strings"
+	"testing"
+)
+
+// queryGraph is a graph of calls with two ways from the gateway to the ledger, and parallel edges from the gateway to
+// the payments service
+var queryGraph = t.ServiceGraph{
+	Nodes: []t.GraphNode{{ID: "audit"}, {ID: "gateway"}, {ID: "ledger"}, {ID: "orders"}, {ID: "payments"}},
+	Edges: []t.GraphEdge{
+		{From: "gateway", To: "orders", Protocol: "tcp", Port: "8080", Resolved: true},
+		{From: "gateway", To: "payments", Protocol: "tcp", Port: "8080", Kind: "http", Resolved: true},
+		{From: "gateway", To: "payments", Protocol: "tcp", Port: "9090", Kind: "grpc", Resolved: true},
+		{From: "orders", To: "payments", Protocol: "tcp", Port: "8080", Resolved: true},
+		{From: "payments", To: "ledger", Protocol: "tcp", Port: "8080", Resolved: true},
+		{From: "audit", To: "ledger", Protocol: "tcp", Port: "8080", Resolved: true},
+	},
+}
+
+func describePaths(paths []t.GraphPath) []string {
+	// describePaths describes paths by the nodes they pass, with the number of edges of each hop.
+	//
+	// paths: The paths.
+	//
+	// Returns:
+	// One description per path, such as "gateway -2-> payments -1-> ledger".
+
+	descriptions := []string{}
+	for _, path := range paths {
+		var sb strings.Builder
+		sb.WriteString(path.From)
+		for _, hop := range path.Hops {
+			fmt.Fprintf(&sb, " -%d-> %s", len(hop.Edges), hop.To)
+		}
+		if len(path.Hops) > 0 && (path.Hops[0].From != path.From || path.Hops[len(path.Hops)-1].To != path.To) {
+			sb.WriteString(" (ends " + path.To + ")")
+		}
+		descriptions = append(descriptions, sb.String())
+	}
+	return descriptions
+}
+
+func TestFindPaths(test *testing.T) {
+	// TestFindPaths checks the nodes a service reaches, its direct and transitive callers, and the shortest path between
+	// two nodes, with parallel edges grouped into a hop and paths sorted nearest first.
+	//
+	// test: The test.
+
+	shortest := func(from string, to string) []t.GraphPath {
+		if path, ok := FindShortestPath(queryGraph, from, to); ok {
+			return []t.GraphPath{path}
+		}
+		return []t.GraphPath{}
+	}
+
+	cases := []struct {
+		name  string
+		paths []t.GraphPath
+		want  []string
+	}{
+		{
+			name:  "reachable",
+			paths: FindReachable(queryGraph, "gateway"),
+			want:  []string{"gateway -1-> orders", "gateway -2-> payments", "gateway -2-> payments -1-> ledger"},
+		},
+		{
+			name:  "reachable from a leaf",
+			paths: FindReachable(queryGraph, "ledger"),
+			want:  []string{},
+		},
+		{
+			name:  "direct callers",
+			paths: FindCallers(queryGraph, "ledger", false),
+			want:  []string{"audit -1-> ledger", "payments -1-> ledger"},
+		},
+		{
+			name:  "transitive callers",
+			paths: FindCallers(queryGraph, "ledger", true),
+			want:  []string{"audit -1-> ledger", "payments -1-> ledger", "gateway -2-> payments -1-> ledger", "orders -1-> payments -1-> ledger"},
+		},
+		{
+			name:  "shortest path",
+			paths: shortest("gateway", "ledger"),
+			want:  []string{"gateway -2-> payments -1-> ledger"},
+		},
+		{
+			name:  "no path against the calls",
+			paths: shortest("ledger", "gateway"),
+			want:  []string{},
+		},
+		{
+			name:  "unknown service",
+			paths: FindReachable(queryGraph, "unknown"),
+			want:  []string{},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := describePaths(c.paths); !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got paths %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	t "static_analyser/pkg/types"
+)
+
+func namespaces(g t.ServiceGraph) ([]string, map[string][]t.GraphNode) {
+	// namespaces groups the workload nodes of a graph by namespace.
+	//
+	// g: The graph to group.
+	//
+	// Returns:
+	// The namespaces in order of first appearance, and a map from each namespace to its nodes.
+	// External nodes have no namespace and are grouped under the empty string.
+
+	order := []string{}
+	members := make(map[string][]t.GraphNode)
+	for _, node := range g.Nodes {
+		if _, ok := members[node.Namespace]; !ok {
+			order = append(order, node.Namespace)
+		}
+		members[node.Namespace] = append(members[node.Namespace], node)
+	}
+	return order, members
+}
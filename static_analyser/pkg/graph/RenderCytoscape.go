@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+// cytoscapeElement is a node or edge in the Cytoscape.js elements JSON format.
+type cytoscapeElement struct {
+	Data    map[string]interface{} `json:"data"`
+	Classes string                 `json:"classes,omitempty"`
+}
+
+func RenderCytoscape(g t.ServiceGraph) (string, error) {
+	// RenderCytoscape renders a service graph in the Cytoscape.js elements JSON format.
+	//
+	// g: The graph to render.
+	//
+	// Returns:
+	// The JSON document of the graph. Namespaces are rendered as compound parent nodes and unresolved edges and targets carry the "unresolved" class.
+	// An error if the graph could not be converted to JSON.
+
+	nodes := []cytoscapeElement{}
+	edges := []cytoscapeElement{}
+
+	order, _ := namespaces(g)
+	for _, namespace := range order {
+		if namespace == "" {
+			continue
+		}
+		nodes = append(nodes, cytoscapeElement{Data: map[string]interface{}{"id": "namespace:" + namespace, "label": namespace}, Classes: "namespace"})
+	}
+
+	for _, n := range g.Nodes {
+		data := map[string]interface{}{"id": n.ID, "label": n.Label, "version": n.Version}
+		classes := "service"
+		if n.Namespace != "" {
+			data["parent"] = "namespace:" + n.Namespace
+		}
+		if strings.HasPrefix(n.ID, UnresolvedPrefix) {
+			classes = "unresolved"
+		} else if n.External {
+			classes = "external"
+		}
+		nodes = append(nodes, cytoscapeElement{Data: data, Classes: classes})
+	}
+
+	for i, e := range g.Edges {
+		data := map[string]interface{}{
+			"id":          fmt.Sprintf("e%d", i),
+			"source":      e.From,
+			"target":      e.To,
+			"label":       EdgeLabel(e),
+			"protocol":    e.Protocol,
+			"port":        e.Port,
+			"kind":        e.Kind,
+			"serviceName": e.ServiceName,
+			"locations":   e.Locations,
+		}
+		classes := ""
+		if !e.Resolved {
+			classes = "unresolved"
+		}
+		edges = append(edges, cytoscapeElement{Data: data, Classes: classes})
+	}
+
+	document := map[string]interface{}{
+		"elements": map[string]interface{}{"nodes": nodes, "edges": edges},
+	}
+	jsonData, err := json.MarshalIndent(document, "", " ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cytoscape graph: %w", err)
+	}
+	return string(jsonData) + "\n", nil
+}
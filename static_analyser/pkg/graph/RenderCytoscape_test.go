@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"encoding/json"
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestRenderCytoscape(test *testing.T) {
+	// TestRenderCytoscape checks the Cytoscape.js elements of a graph: namespaces as compound parents, classes for
+	// external hosts and unresolved targets, and the attributes of each edge.
+	//
+	// test: The test.
+
+	type element struct {
+		Data    map[string]interface{} `json:"data"`
+		Classes string                 `json:"classes"`
+	}
+	// summary describes each element by its ID, parent or endpoints, and classes
+	summary := func(elements []element) []string {
+		result := []string{}
+		for _, e := range elements {
+			s := e.Data["id"].(string)
+			if parent, ok := e.Data["parent"]; ok {
+				s += " in " + parent.(string)
+			}
+			if source, ok := e.Data["source"]; ok {
+				s += " " + source.(string) + " -> " + e.Data["target"].(string)
+			}
+			result = append(result, s+" ."+e.Classes)
+		}
+		return result
+	}
+
+	cases := []struct {
+		name  string
+		graph t.ServiceGraph
+		nodes []string
+		edges []string
+	}{
+		{
+			name:  "empty",
+			graph: t.ServiceGraph{},
+			nodes: []string{},
+			edges: []string{},
+		},
+		{
+			name:  "namespaces, external hosts and unresolved targets",
+			graph: sampleGraph(),
+			nodes: []string{
+				"namespace:default .namespace",
+				"namespace:shop .namespace",
+				"caller in namespace:default .service",
+				"hello in namespace:shop .service",
+				"external:api.example.com .external",
+				"unresolved:ghost .unresolved",
+			},
+			edges: []string{
+				"e0 caller -> hello .",
+				"e1 caller -> unresolved:ghost .unresolved",
+				"e2 hello -> external:api.example.com .",
+			},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			rendered, err := RenderCytoscape(c.graph)
+			if err != nil {
+				test.Fatal(err)
+			}
+			var document struct {
+				Elements struct {
+					Nodes []element `json:"nodes"`
+					Edges []element `json:"edges"`
+				} `json:"elements"`
+			}
+			if err := json.Unmarshal([]byte(rendered), &document); err != nil {
+				test.Fatalf("got invalid JSON %s: %v", rendered, err)
+			}
+			if got := summary(document.Elements.Nodes); !reflect.DeepEqual(got, c.nodes) {
+				test.Errorf("got nodes %q, want %q", got, c.nodes)
+			}
+			if got := summary(document.Elements.Edges); !reflect.DeepEqual(got, c.edges) {
+				test.Errorf("got edges %q, want %q", got, c.edges)
+			}
+			if len(document.Elements.Edges) > 0 {
+				want := map[string]interface{}{
+					"id": "e0", "source": "caller", "target": "hello", "label": "tcp/8080 SelectInstances GET /hello",
+					"protocol": "tcp", "port": "8080", "kind": "SelectInstances", "serviceName": "hello",
+					"locations": []interface{}{"caller/main.go:10", "caller/main.go:20"},
+				}
+				if got := document.Elements.Edges[0].Data; !reflect.DeepEqual(got, want) {
+					test.Errorf("got edge data %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderDOT(g t.ServiceGraph) string {
+	// RenderDOT renders a service graph in the Graphviz DOT language.
+	//
+	// g: The graph to render.
+	//
+	// Returns:
+	// The DOT source of the graph. Namespaces are rendered as clusters and unresolved edges and targets are drawn dashed and red.
+
+	quote := func(s string) string {
+		return "\"" + strings.ReplaceAll(s, "\"", "\\\"") + "\""
+	}
+
+	node := func(sb *strings.Builder, indent string, n t.GraphNode) {
+		label := n.Label
+		if n.Version != "" {
+			label += "\\n" + n.Version
+		}
+		attrs := "label=" + quote(label)
+		if n.External {
+			attrs += ", shape=box"
+		}
+		if strings.HasPrefix(n.ID, UnresolvedPrefix) {
+			attrs += ", style=dashed, color=red, fontcolor=red"
+		}
+		fmt.Fprintf(sb, "%s%s [%s];\n", indent, quote(n.ID), attrs)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph services {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=ellipse];\n")
+
+	order, members := namespaces(g)
+	for i, namespace := range order {
+		if namespace == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&sb, "    label=%s;\n", quote(namespace))
+		for _, n := range members[namespace] {
+			node(&sb, "    ", n)
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, n := range members[""] {
+		node(&sb, "  ", n)
+	}
+
+	for _, e := range g.Edges {
+		attrs := "label=" + quote(EdgeLabel(e))
+		if !e.Resolved {
+			attrs += ", style=dashed, color=red, fontcolor=red"
+		}
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", quote(e.From), quote(e.To), attrs)
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
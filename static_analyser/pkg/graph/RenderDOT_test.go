@@ -0,0 +1,76 @@
+package graph
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func sampleGraph() t.ServiceGraph {
+	// sampleGraph builds a graph with workloads in two namespaces, one of them with a quote in its label, an external
+	// host and an unresolved discovery target, for the renderers to render.
+	//
+	// Returns:
+	// The graph.
+
+	return t.ServiceGraph{
+		Nodes: []t.GraphNode{
+			{ID: "caller", Label: "caller", Namespace: "default", Version: "v1"},
+			{ID: "hello", Label: `say "hello"`, Namespace: "shop", Version: "v2"},
+			{ID: ExternalPrefix + "api.example.com", Label: "api.example.com", External: true},
+			{ID: UnresolvedPrefix + "ghost", Label: "ghost", External: true},
+		},
+		Edges: []t.GraphEdge{
+			{From: "caller", To: "hello", Protocol: "tcp", Port: "8080", Kind: "SelectInstances", Method: "GET", Path: "/hello", ServiceName: "hello", Resolved: true, Locations: []string{"caller/main.go:10", "caller/main.go:20"}},
+			{From: "caller", To: UnresolvedPrefix + "ghost", Protocol: "tcp", Kind: "SelectOneHealthyInstance", ServiceName: "ghost"},
+			{From: "hello", To: ExternalPrefix + "api.example.com", Protocol: "tcp", Port: "443", Kind: "http", Resolved: true},
+		},
+	}
+}
+
+func TestRenderDOT(test *testing.T) {
+	// TestRenderDOT checks the DOT source of a graph: namespaces as clusters, external hosts as boxes, and unresolved
+	// targets and edges dashed and red.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name  string
+		graph t.ServiceGraph
+		want  string
+	}{
+		{
+			name:  "empty",
+			graph: t.ServiceGraph{},
+			want:  "digraph services {\n  rankdir=LR;\n  node [shape=ellipse];\n}\n",
+		},
+		{
+			name:  "namespaces, external hosts and unresolved targets",
+			graph: sampleGraph(),
+			want: `digraph services {
+  rankdir=LR;
+  node [shape=ellipse];
+  subgraph cluster_0 {
+    label="default";
+    "caller" [label="caller\nv1"];
+  }
+  subgraph cluster_1 {
+    label="shop";
+    "hello" [label="say \"hello\"\nv2"];
+  }
+  "external:api.example.com" [label="api.example.com", shape=box];
+  "unresolved:ghost" [label="ghost", shape=box, style=dashed, color=red, fontcolor=red];
+  "caller" -> "hello" [label="tcp/8080 SelectInstances GET /hello"];
+  "caller" -> "unresolved:ghost" [label="tcp/? SelectOneHealthyInstance", style=dashed, color=red, fontcolor=red];
+  "hello" -> "external:api.example.com" [label="tcp/443 http"];
+}
+`,
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := RenderDOT(c.graph); got != c.want {
+				test.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+// graphMLKey declares an attribute that nodes or edges of a GraphML document can carry.
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+// graphMLData is the value of a declared attribute.
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNode is a node of a GraphML graph, optionally containing a nested graph.
+type graphMLNode struct {
+	ID    string        `xml:"id,attr"`
+	Data  []graphMLData `xml:"data"`
+	Graph *graphMLGraph `xml:"graph,omitempty"`
+}
+
+// graphMLEdge is an edge of a GraphML graph.
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLGraph is a graph of a GraphML document.
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+// graphMLDocument is the root element of a GraphML document.
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+func RenderGraphML(g t.ServiceGraph) (string, error) {
+	// RenderGraphML renders a service graph as a GraphML document.
+	//
+	// g: The graph to render.
+	//
+	// Returns:
+	// The XML of the graph. Namespaces are rendered as nodes containing nested graphs and unresolved edges and targets have "resolved" set to false.
+	// An error if the graph could not be converted to XML.
+
+	nodeData := func(n t.GraphNode) []graphMLData {
+		return []graphMLData{
+			{Key: "label", Value: n.Label},
+			{Key: "version", Value: n.Version},
+			{Key: "namespace", Value: n.Namespace},
+			{Key: "external", Value: fmt.Sprint(n.External)},
+		}
+	}
+
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "version", For: "node", AttrName: "version", AttrType: "string"},
+			{ID: "namespace", For: "node", AttrName: "namespace", AttrType: "string"},
+			{ID: "external", For: "node", AttrName: "external", AttrType: "boolean"},
+			{ID: "elabel", For: "edge", AttrName: "label", AttrType: "string"},
+			{ID: "protocol", For: "edge", AttrName: "protocol", AttrType: "string"},
+			{ID: "port", For: "edge", AttrName: "port", AttrType: "string"},
+			{ID: "kind", For: "edge", AttrName: "kind", AttrType: "string"},
+			{ID: "serviceName", For: "edge", AttrName: "serviceName", AttrType: "string"},
+			{ID: "resolved", For: "edge", AttrName: "resolved", AttrType: "boolean"},
+			{ID: "locations", For: "edge", AttrName: "locations", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "services", EdgeDefault: "directed"},
+	}
+
+	order, members := namespaces(g)
+	for _, namespace := range order {
+		if namespace == "" {
+			for _, n := range members[namespace] {
+				doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: n.ID, Data: nodeData(n)})
+			}
+			continue
+		}
+
+		// Each namespace is a node holding a nested graph of its workloads
+		cluster := graphMLNode{
+			ID:    "namespace:" + namespace,
+			Data:  []graphMLData{{Key: "label", Value: namespace}},
+			Graph: &graphMLGraph{ID: "namespace:" + namespace + ":", EdgeDefault: "directed"},
+		}
+		for _, n := range members[namespace] {
+			cluster.Graph.Nodes = append(cluster.Graph.Nodes, graphMLNode{ID: n.ID, Data: nodeData(n)})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, cluster)
+	}
+
+	for i, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: e.From,
+			Target: e.To,
+			Data: []graphMLData{
+				{Key: "elabel", Value: EdgeLabel(e)},
+				{Key: "protocol", Value: e.Protocol},
+				{Key: "port", Value: e.Port},
+				{Key: "kind", Value: e.Kind},
+				{Key: "serviceName", Value: e.ServiceName},
+				{Key: "resolved", Value: fmt.Sprint(e.Resolved)},
+				{Key: "locations", Value: strings.Join(e.Locations, " ")},
+			},
+		})
+	}
+
+	xmlData, err := xml.MarshalIndent(doc, "", " ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal graphml graph: %w", err)
+	}
+	return xml.Header + string(xmlData) + "\n", nil
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"encoding/xml"
+	"reflect"
+	t "static_analyser/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestRenderGraphML(test *testing.T) {
+	// TestRenderGraphML checks the GraphML document of a graph: namespaces as nodes holding nested graphs, external
+	// hosts at the top level, and the attributes of nodes and edges.
+	//
+	// test: The test.
+
+	// data joins the attributes of a node or edge as key=value pairs
+	data := func(values []graphMLData) string {
+		pairs := []string{}
+		for _, value := range values {
+			pairs = append(pairs, value.Key+"="+value.Value)
+		}
+		return strings.Join(pairs, " ")
+	}
+	// summary describes each node of a graph by its ID and attributes, followed by the nodes of its nested graph
+	var summary func(g graphMLGraph) []string
+	summary = func(g graphMLGraph) []string {
+		result := []string{}
+		for _, node := range g.Nodes {
+			result = append(result, node.ID+": "+data(node.Data))
+			if node.Graph != nil {
+				for _, nested := range summary(*node.Graph) {
+					result = append(result, "  "+nested)
+				}
+			}
+		}
+		for _, edge := range g.Edges {
+			result = append(result, edge.ID+" "+edge.Source+" -> "+edge.Target+": "+data(edge.Data))
+		}
+		return result
+	}
+
+	cases := []struct {
+		name  string
+		graph t.ServiceGraph
+		want  []string
+	}{
+		{
+			name:  "empty",
+			graph: t.ServiceGraph{},
+			want:  []string{},
+		},
+		{
+			name:  "namespaces, external hosts and unresolved targets",
+			graph: sampleGraph(),
+			want: []string{
+				"namespace:default: label=default",
+				"  caller: label=caller version=v1 namespace=default external=false",
+				"namespace:shop: label=shop",
+				`  hello: label=say "hello" version=v2 namespace=shop external=false`,
+				"external:api.example.com: label=api.example.com version= namespace= external=true",
+				"unresolved:ghost: label=ghost version= namespace= external=true",
+				"e0 caller -> hello: elabel=tcp/8080 SelectInstances GET /hello protocol=tcp port=8080 kind=SelectInstances serviceName=hello resolved=true locations=caller/main.go:10 caller/main.go:20",
+				"e1 caller -> unresolved:ghost: elabel=tcp/? SelectOneHealthyInstance protocol=tcp port= kind=SelectOneHealthyInstance serviceName=ghost resolved=false locations=",
+				"e2 hello -> external:api.example.com: elabel=tcp/443 http protocol=tcp port=443 kind=http serviceName= resolved=true locations=",
+			},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			rendered, err := RenderGraphML(c.graph)
+			if err != nil {
+				test.Fatal(err)
+			}
+			if !strings.HasPrefix(rendered, xml.Header) {
+				test.Errorf("got document %s, want an XML header", rendered)
+			}
+			var document graphMLDocument
+			if err := xml.Unmarshal([]byte(rendered), &document); err != nil {
+				test.Fatalf("got invalid XML %s: %v", rendered, err)
+			}
+			if document.XMLNS != "http://graphml.graphdrawing.org/xmlns" || len(document.Keys) != 11 || document.Graph.EdgeDefault != "directed" {
+				test.Errorf("got namespace %s, %d keys and edge default %s", document.XMLNS, len(document.Keys), document.Graph.EdgeDefault)
+			}
+			if got := summary(document.Graph); !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderMermaid(g t.ServiceGraph) string {
+	// RenderMermaid renders a service graph as a Mermaid flowchart.
+	//
+	// g: The graph to render.
+	//
+	// Returns:
+	// The Mermaid source of the graph. Namespaces are rendered as subgraphs and unresolved edges and targets are drawn dashed and red.
+
+	// Mermaid identifiers are restricted, so nodes are numbered and the real names are only used as labels
+	ids := make(map[string]string)
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "\"", "#quot;")
+	}
+
+	node := func(sb *strings.Builder, indent string, n t.GraphNode) {
+		label := n.Label
+		if n.Version != "" {
+			label += " " + n.Version
+		}
+		fmt.Fprintf(sb, "%s%s[\"%s\"]", indent, ids[n.ID], escape(label))
+		if strings.HasPrefix(n.ID, UnresolvedPrefix) {
+			sb.WriteString(":::unresolved")
+		} else if n.External {
+			sb.WriteString(":::external")
+		}
+		sb.WriteString("\n")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+
+	order, members := namespaces(g)
+	for i, namespace := range order {
+		if namespace == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "  subgraph ns%d [\"%s\"]\n", i, escape(namespace))
+		for _, n := range members[namespace] {
+			node(&sb, "    ", n)
+		}
+		sb.WriteString("  end\n")
+	}
+	for _, n := range members[""] {
+		node(&sb, "  ", n)
+	}
+
+	unresolved := []string{}
+	for i, e := range g.Edges {
+		arrow := "-->"
+		if !e.Resolved {
+			arrow = "-.->"
+			unresolved = append(unresolved, fmt.Sprint(i))
+		}
+		fmt.Fprintf(&sb, "  %s %s|\"%s\"| %s\n", ids[e.From], arrow, escape(EdgeLabel(e)), ids[e.To])
+	}
+
+	sb.WriteString("  classDef external stroke-dasharray:2\n")
+	sb.WriteString("  classDef unresolved stroke:#d00,stroke-dasharray:4,color:#d00\n")
+	if len(unresolved) > 0 {
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:#d00,color:#d00\n", strings.Join(unresolved, ","))
+	}
+
+	return sb.String()
+}
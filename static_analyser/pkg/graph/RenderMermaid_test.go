@@ -0,0 +1,54 @@
+package graph
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestRenderMermaid(test *testing.T) {
+	// TestRenderMermaid checks the Mermaid flowchart of a graph: numbered node IDs, namespaces as subgraphs, escaped
+	// quotes, and classes and link styles for external hosts and unresolved targets.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name  string
+		graph t.ServiceGraph
+		want  string
+	}{
+		{
+			name:  "empty",
+			graph: t.ServiceGraph{},
+			want: "flowchart LR\n" +
+				"  classDef external stroke-dasharray:2\n" +
+				"  classDef unresolved stroke:#d00,stroke-dasharray:4,color:#d00\n",
+		},
+		{
+			name:  "namespaces, external hosts and unresolved targets",
+			graph: sampleGraph(),
+			want: `flowchart LR
+  subgraph ns0 ["default"]
+    n0["caller v1"]
+  end
+  subgraph ns1 ["shop"]
+    n1["say #quot;hello#quot; v2"]
+  end
+  n2["api.example.com"]:::external
+  n3["ghost"]:::unresolved
+  n0 -->|"tcp/8080 SelectInstances GET /hello"| n1
+  n0 -.->|"tcp/? SelectOneHealthyInstance"| n3
+  n1 -->|"tcp/443 http"| n2
+  classDef external stroke-dasharray:2
+  classDef unresolved stroke:#d00,stroke-dasharray:4,color:#d00
+  linkStyle 1 stroke:#d00,color:#d00
+`,
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := RenderMermaid(c.graph); got != c.want {
+				test.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package nacos_auth
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	diag "static_analyser/pkg/diagnostics"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+// defaultGroup is the group the Nacos SDK uses when a call names none
+const defaultGroup = "DEFAULT_GROUP"
+
+// unsafeChars are the characters that cannot appear in the name of an environment variable
+var unsafeChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+func GenerateAuthConfig(analyses map[string]t.ApplicationAnalysis, serviceDirectory map[string][]t.ServiceInfo, applicationFolders map[string]string, namespace string) (t.NacosAuthConfig, []t.Diagnostic) {
+	// GenerateAuthConfig generates the Nacos users, roles and permissions that let each application do what its source
+	// does, and nothing more: register its own services, discover the services it calls, and read and write its
+	// configurations. Each application using Nacos gets a user and a role of its name.
+	//
+	// analyses: A map where the keys are the names of the applications and the values are their ApplicationAnalysis.
+	// serviceDirectory: A map where the keys are the names of the services and the values are their registered instances.
+	// Only the application a service is taken from may register it.
+	// applicationFolders: A map where the keys are the names of the applications and the values are the corresponding folder paths.
+	// namespace: The namespace of the applications whose client does not name one in the source, such as one read from
+	// an environment variable.
+	//
+	// Returns:
+	// The users, roles and permissions, sorted by application, resource and action. Resources are written as
+	// "namespace:group:naming/service" and "namespace:group:config/dataId"; groups computed at runtime are written as "*".
+	// The diagnostics of the permissions that are not generated, as the name of their service or configuration is
+	// computed at runtime.
+
+	applications := []string{}
+	for application := range analyses {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+
+	config := t.NacosAuthConfig{Users: []t.NacosUser{}, Roles: []t.NacosRole{}, Permissions: []t.NacosPermission{}}
+	diagnostics := []t.Diagnostic{}
+	for _, application := range applications {
+		analysis := analyses[application]
+		namespaces := namespacesOf(analysis, namespace)
+		permissions := make(map[string]*t.NacosPermission)
+		grant := func(group string, resourceType string, name string, action string, location string) {
+			// grant adds an action on a resource to the permissions of the application, in each of its namespaces.
+			//
+			// group: The group of the resource, empty for the default group.
+			// resourceType: "naming" or "config".
+			// name: The name of the service or the DataId of the configuration.
+			// action: "r" or "w".
+			// location: The source location of the call that needs the permission, empty if it is not known.
+			//
+			// Returns:
+			// This function doesn't return a value. Names computed at runtime are reported as diagnostics instead.
+
+			if !resolved(name) {
+				if location == "" {
+					location = applicationFolders[application]
+				}
+				field := "service name"
+				if resourceType == "config" {
+					field = "DataId"
+				}
+				message := fmt.Sprintf("no %s permission is generated, as the %s is computed at runtime", resourceType, field)
+				diagnostics = append(diagnostics, t.Diagnostic{Code: diag.UnresolvedPermission, Severity: "warning", Location: location, Message: message, Service: application})
+				return
+			}
+			if group == "" {
+				group = defaultGroup
+			} else if !resolved(group) {
+				group = "*"
+			}
+			for _, ns := range namespaces {
+				resource := ns + ":" + group + ":" + resourceType + "/" + name
+				permission, ok := permissions[resource]
+				if !ok {
+					permission = &t.NacosPermission{Role: roleOf(application), Resource: resource}
+					permissions[resource] = permission
+				}
+				switch permission.Action {
+				case "":
+					permission.Action = action
+				case action, "rw":
+					// The permission already allows the action
+				default:
+					permission.Action = "rw"
+				}
+				if location != "" && !util.Contains(permission.Locations, location) {
+					permission.Locations = append(permission.Locations, location)
+				}
+			}
+		}
+
+		names := []string{}
+		for name := range serviceDirectory {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, info := range serviceDirectory[name] {
+				if info.Application == application {
+					grant(info.Group, "naming", name, "w", "")
+				}
+			}
+		}
+		for _, call := range analysis.Discoveries {
+			grant(call.Group, "naming", call.ServiceName, "r", call.Location)
+		}
+		for _, access := range analysis.Configs {
+			action := "r"
+			if access.Access == "write" {
+				action = "w"
+			}
+			grant(access.Group, "config", access.DataId, action, access.Location)
+		}
+
+		// Applications that do not use Nacos need no user
+		if len(permissions) == 0 {
+			continue
+		}
+		config.Users = append(config.Users, t.NacosUser{Username: application, Password: "$NACOS_PASSWORD_" + upperSnake(application)})
+		config.Roles = append(config.Roles, t.NacosRole{Role: roleOf(application), Username: application})
+		resources := []string{}
+		for resource := range permissions {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+		for _, resource := range resources {
+			sort.Strings(permissions[resource].Locations)
+			config.Permissions = append(config.Permissions, *permissions[resource])
+		}
+	}
+	return config, diagnostics
+}
+
+func namespacesOf(analysis t.ApplicationAnalysis, fallback string) []string {
+	// namespacesOf works out the namespaces the Nacos clients of an application use.
+	//
+	// analysis: The analysis of the application.
+	// fallback: The namespace of clients whose namespace is computed at runtime, and of applications without a client
+	// configuration in their source.
+	//
+	// Returns:
+	// The namespaces, sorted. A client that names no namespace uses "public".
+
+	set := make(map[string]bool)
+	for _, config := range analysis.ClientConfigs {
+		if config.Kind != "client" {
+			continue
+		}
+		namespace := "public"
+		for _, setting := range config.Settings {
+			if setting.Name != "NamespaceId" {
+				continue
+			}
+			switch {
+			case !setting.Literal:
+				namespace = fallback
+			case setting.Value != "":
+				namespace = setting.Value
+			}
+		}
+		set[namespace] = true
+	}
+	if len(set) == 0 {
+		set[fallback] = true
+	}
+	namespaces := []string{}
+	for namespace := range set {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func resolved(name string) bool {
+	// resolved checks if the name of a service, group or configuration is known statically.
+	//
+	// name: The name, as resolved by the parser. Arguments that are not literals are resolved to "nil".
+	//
+	// Returns:
+	// True if the name is neither empty, "nil" nor a template with placeholders, false otherwise.
+
+	return name != "nil" && util.IsResolved(name)
+}
+
+func roleOf(application string) string {
+	// roleOf names the role of an application.
+	//
+	// application: The name of the application.
+	//
+	// Returns:
+	// The name of the role, such as "ROLE_CALLER_SERVICE" for "caller-service".
+
+	return "ROLE_" + upperSnake(application)
+}
+
+func upperSnake(name string) string {
+	// upperSnake turns a name into one fit for roles and environment variables.
+	//
+	// name: The name, such as the name of an application.
+	//
+	// Returns:
+	// The name in upper case, with every character other than a letter, a digit or an underscore replaced by an underscore.
+
+	return unsafeChars.ReplaceAllString(strings.ToUpper(name), "_")
+}
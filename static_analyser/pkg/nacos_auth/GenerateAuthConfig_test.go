@@ -0,0 +1,117 @@
+package nacos_auth
+
+import (
+	"reflect"
+	diag "static_analyser/pkg/diagnostics"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestGenerateAuthConfig(test *testing.T) {
+	// TestGenerateAuthConfig checks the permissions generated for registrations, discoveries and configuration accesses,
+	// in the namespaces and groups the source names, and the diagnostics of names computed at runtime.
+	//
+	// test: The test.
+
+	client := func(settings ...t.NacosConfigSetting) []t.NacosClientConfig {
+		return []t.NacosClientConfig{{Kind: "client", Location: "main.go:10", Settings: settings}}
+	}
+	namespaceId := func(value string, literal bool) t.NacosConfigSetting {
+		return t.NacosConfigSetting{Name: "NamespaceId", Value: value, Literal: literal}
+	}
+	folders := map[string]string{"orders": "orders", "gateway": "gateway", "static": "static"}
+
+	cases := []struct {
+		name             string
+		analyses         map[string]t.ApplicationAnalysis
+		serviceDirectory map[string][]t.ServiceInfo
+		want             []t.NacosPermission
+		diagnostics      []string
+	}{
+		{
+			name:             "registration and discovery",
+			analyses:         map[string]t.ApplicationAnalysis{"orders": {}, "gateway": {Discoveries: []t.DiscoveryCall{{ServiceName: "orders", Location: "gateway/main.go:20"}, {ServiceName: "orders", Location: "gateway/main.go:25"}}}},
+			serviceDirectory: map[string][]t.ServiceInfo{"orders": {{Application: "orders", Port: "8080"}, {Application: "orders", Port: "9090"}}},
+			want: []t.NacosPermission{
+				{Role: "ROLE_GATEWAY", Resource: "prod:DEFAULT_GROUP:naming/orders", Action: "r", Locations: []string{"gateway/main.go:20", "gateway/main.go:25"}},
+				{Role: "ROLE_ORDERS", Resource: "prod:DEFAULT_GROUP:naming/orders", Action: "w"},
+			},
+		},
+		{
+			name: "configurations read and written",
+			analyses: map[string]t.ApplicationAnalysis{"orders": {Configs: []t.ConfigAccess{
+				{DataId: "orders.yaml", Group: "SHOP", Access: "read", Location: "orders/main.go:30"},
+				{DataId: "orders.yaml", Group: "SHOP", Access: "write", Location: "orders/main.go:40"},
+				{DataId: "flags.yaml", Group: "{group}", Access: "read", Location: "orders/main.go:50"},
+			}}},
+			want: []t.NacosPermission{
+				{Role: "ROLE_ORDERS", Resource: "prod:*:config/flags.yaml", Action: "r", Locations: []string{"orders/main.go:50"}},
+				{Role: "ROLE_ORDERS", Resource: "prod:SHOP:config/orders.yaml", Action: "rw", Locations: []string{"orders/main.go:30", "orders/main.go:40"}},
+			},
+		},
+		{
+			name: "namespaces of the clients",
+			analyses: map[string]t.ApplicationAnalysis{
+				"gateway": {ClientConfigs: append(client(namespaceId("staging", true)), client()...), Discoveries: []t.DiscoveryCall{{ServiceName: "orders", Group: "SHOP", Location: "gateway/main.go:20"}}},
+				"orders":  {ClientConfigs: client(namespaceId("{env}", false))},
+			},
+			serviceDirectory: map[string][]t.ServiceInfo{"orders": {{Application: "orders", Port: "8080", Group: "SHOP"}}},
+			want: []t.NacosPermission{
+				{Role: "ROLE_GATEWAY", Resource: "public:SHOP:naming/orders", Action: "r", Locations: []string{"gateway/main.go:20"}},
+				{Role: "ROLE_GATEWAY", Resource: "staging:SHOP:naming/orders", Action: "r", Locations: []string{"gateway/main.go:20"}},
+				{Role: "ROLE_ORDERS", Resource: "prod:SHOP:naming/orders", Action: "w"},
+			},
+		},
+		{
+			name: "names computed at runtime",
+			analyses: map[string]t.ApplicationAnalysis{
+				"gateway": {Discoveries: []t.DiscoveryCall{{ServiceName: "orders-{shard}", Location: "gateway/main.go:20"}, {ServiceName: "nil", Location: "gateway/main.go:21"}}},
+				"orders":  {Configs: []t.ConfigAccess{{DataId: "{dataId}", Access: "read", Location: "orders/main.go:30"}}},
+			},
+			serviceDirectory: map[string][]t.ServiceInfo{"orders-{shard}": {{Application: "orders", Port: "8080"}}},
+			want:             []t.NacosPermission{},
+			diagnostics:      []string{"gateway/main.go:20", "gateway/main.go:21", "orders", "orders/main.go:30"},
+		},
+		{
+			name:     "no Nacos use",
+			analyses: map[string]t.ApplicationAnalysis{"static": {}},
+			want:     []t.NacosPermission{},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			config, diagnostics := GenerateAuthConfig(c.analyses, c.serviceDirectory, folders, "prod")
+			if !reflect.DeepEqual(config.Permissions, c.want) {
+				test.Errorf("got permissions %+v, want %+v", config.Permissions, c.want)
+			}
+
+			// Every application with a permission has a user and a role of its own
+			users := map[string]bool{}
+			for _, permission := range c.want {
+				users[permission.Role] = true
+			}
+			if len(config.Users) != len(users) || len(config.Roles) != len(users) {
+				test.Errorf("got users %+v and roles %+v, want one each for %v", config.Users, config.Roles, users)
+			}
+			for i, user := range config.Users {
+				if role := config.Roles[i]; !users[role.Role] || role.Username != user.Username || user.Password != "$NACOS_PASSWORD_"+role.Role[len("ROLE_"):] {
+					test.Errorf("got user %+v with role %+v", user, role)
+				}
+			}
+
+			locations := []string{}
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Code != diag.UnresolvedPermission {
+					test.Errorf("got diagnostic %+v, want %s", diagnostic, diag.UnresolvedPermission)
+				}
+				locations = append(locations, diagnostic.Location)
+			}
+			if c.diagnostics == nil {
+				c.diagnostics = []string{}
+			}
+			if !reflect.DeepEqual(locations, c.diagnostics) {
+				test.Errorf("got diagnostics at %q, want %q", locations, c.diagnostics)
+			}
+		})
+	}
+}
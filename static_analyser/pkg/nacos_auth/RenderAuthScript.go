@@ -0,0 +1,64 @@
+package nacos_auth
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderAuthScript(config t.NacosAuthConfig) string {
+	// RenderAuthScript renders a NacosAuthConfig as a shell script creating its users, roles and permissions through the
+	// auth Open API of a Nacos server.
+	//
+	// config: The configuration to render.
+	//
+	// Returns:
+	// The script. It reads the address of the server from NACOS_ADDR, the access token of an administrator from
+	// NACOS_TOKEN, and the password of each user from the environment variable named by the user. Requests that fail,
+	// such as the creation of a user that exists, are reported and the script carries on, so that it can be rerun.
+
+	quote := func(value string) string {
+		// quote quotes a value for the shell.
+		//
+		// value: The value.
+		//
+		// Returns:
+		// The value in single quotes, with the single quotes it holds escaped.
+
+		return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Creates the Nacos users, roles and permissions generated by the static analyser.\n")
+	b.WriteString("# Set NACOS_ADDR, NACOS_TOKEN and the password variable of each user before running it.\n")
+	b.WriteString(": \"${NACOS_ADDR:=http://127.0.0.1:8848}\"\n")
+	b.WriteString(": \"${NACOS_TOKEN:?set NACOS_TOKEN to the access token of a Nacos administrator}\"\n")
+	b.WriteString("api() {\n")
+	b.WriteString("  path=$1\n")
+	b.WriteString("  shift\n")
+	b.WriteString("  curl -sS -X POST \"$NACOS_ADDR/nacos/v1/auth/$path\" --data-urlencode \"accessToken=$NACOS_TOKEN\" \"$@\"\n")
+	b.WriteString("  echo\n")
+	b.WriteString("}\n")
+
+	if len(config.Users) > 0 {
+		b.WriteString("\n# Users\n")
+	}
+	for _, user := range config.Users {
+		variable := strings.TrimPrefix(user.Password, "$")
+		fmt.Fprintf(&b, "api users --data-urlencode %s --data-urlencode \"password=${%s:?}\"\n", quote("username="+user.Username), variable)
+	}
+	if len(config.Roles) > 0 {
+		b.WriteString("\n# Roles\n")
+	}
+	for _, role := range config.Roles {
+		fmt.Fprintf(&b, "api roles --data-urlencode %s --data-urlencode %s\n", quote("role="+role.Role), quote("username="+role.Username))
+	}
+	if len(config.Permissions) > 0 {
+		b.WriteString("\n# Permissions\n")
+	}
+	for _, permission := range config.Permissions {
+		fmt.Fprintf(&b, "api permissions --data-urlencode %s --data-urlencode %s --data-urlencode %s\n", quote("role="+permission.Role), quote("resource="+permission.Resource), quote("action="+permission.Action))
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package nacos_auth
+
+import (
+	t "static_analyser/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestRenderAuthScript(test *testing.T) {
+	// TestRenderAuthScript checks the requests of the script creating users, roles and permissions, with the values
+	// quoted for the shell.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name   string
+		config t.NacosAuthConfig
+		want   []string
+	}{
+		{
+			name:   "empty",
+			config: t.NacosAuthConfig{},
+			want:   []string{},
+		},
+		{
+			name: "users, roles and permissions",
+			config: t.NacosAuthConfig{
+				Users:       []t.NacosUser{{Username: "orders", Password: "$NACOS_PASSWORD_ORDERS"}},
+				Roles:       []t.NacosRole{{Role: "ROLE_ORDERS", Username: "orders"}},
+				Permissions: []t.NacosPermission{{Role: "ROLE_ORDERS", Resource: "prod:SHOP:config/it's.yaml", Action: "rw"}},
+			},
+			want: []string{
+				"",
+				"# Users",
+				`api users --data-urlencode 'username=orders' --data-urlencode "password=${NACOS_PASSWORD_ORDERS:?}"`,
+				"",
+				"# Roles",
+				"api roles --data-urlencode 'role=ROLE_ORDERS' --data-urlencode 'username=orders'",
+				"",
+				"# Permissions",
+				`api permissions --data-urlencode 'role=ROLE_ORDERS' --data-urlencode 'resource=prod:SHOP:config/it'\''s.yaml' --data-urlencode 'action=rw'`,
+			},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			script := RenderAuthScript(c.config)
+			if !strings.HasPrefix(script, "#!/bin/sh\n") {
+				test.Fatalf("got script\n%s\nwant a shell script", script)
+			}
+			// The requests follow the definition of the api function
+			_, requests, found := strings.Cut(script, "\n}\n")
+			if !found {
+				test.Fatalf("got script\n%s\nwant an api function", script)
+			}
+			want := strings.Join(c.want, "\n")
+			if want != "" {
+				want += "\n"
+			}
+			if requests != want {
+				test.Errorf("got requests\n%s\nwant\n%s", requests, want)
+			}
+		})
+	}
+}
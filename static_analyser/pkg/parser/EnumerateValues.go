@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"static_analyser/pkg/util"
+	"strconv"
+)
+
+func EnumerateValues(root ast.Node, fn *ast.FuncDecl, expr ast.Expr) []string {
+	// EnumerateValues enumerates the strings an expression can take when it is drawn from a finite set, such as the
+	// service names an aggregator discovers in a loop. The expression may be a string literal, an element of a slice,
+	// array or map literal, a variable ranging over such a literal, or a variable every assignment of which is
+	// enumerable, such as one assigned a literal in each case of a switch. Collections may be held in variables and
+	// grown with append, and variables and constants may be declared in the function or at the top level of the file.
+	//
+	// root: The root node of the AST, the file holding the function.
+	// fn: The function the expression is evaluated in. May be nil for top-level expressions.
+	// expr: The expression to enumerate.
+	//
+	// Returns:
+	// The distinct values, in source order, or nil if any value the expression can take is not known statically.
+
+	visiting := make(map[string]bool)
+
+	// sources returns the expressions assigned to a variable or constant in the function, or else at the top level,
+	// and the range statements that define it
+	sources := func(name string) ([]ast.Expr, []*ast.RangeStmt) {
+		assigned := []ast.Expr{}
+		ranges := []*ast.RangeStmt{}
+		collect := func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					ident, ok := lhs.(*ast.Ident)
+					if !ok || ident.Name != name {
+						continue
+					}
+					// Results of multi-value calls and compound assignments such as += are not enumerable,
+					// which the call or the statement's expression stands for
+					switch {
+					case len(n.Lhs) != len(n.Rhs):
+						assigned = append(assigned, n.Rhs[0])
+					case n.Tok != token.ASSIGN && n.Tok != token.DEFINE:
+						assigned = append(assigned, &ast.BadExpr{})
+					default:
+						assigned = append(assigned, n.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				for i, ident := range n.Names {
+					if ident.Name == name && i < len(n.Values) {
+						assigned = append(assigned, n.Values[i])
+					}
+				}
+			case *ast.RangeStmt:
+				for _, v := range []ast.Expr{n.Key, n.Value} {
+					if ident, ok := v.(*ast.Ident); ok && ident.Name == name {
+						ranges = append(ranges, n)
+					}
+				}
+			}
+			return true
+		}
+		if fn != nil && fn.Body != nil {
+			// A parameter takes the values of every call, which are not known here
+			for _, field := range fn.Type.Params.List {
+				for _, param := range field.Names {
+					if param.Name == name {
+						assigned = append(assigned, &ast.BadExpr{})
+					}
+				}
+			}
+			ast.Inspect(fn.Body, collect)
+		}
+		if file, ok := root.(*ast.File); ok && len(assigned) == 0 && len(ranges) == 0 {
+			for _, decl := range file.Decls {
+				if gen, ok := decl.(*ast.GenDecl); ok && (gen.Tok == token.VAR || gen.Tok == token.CONST) {
+					ast.Inspect(gen, collect)
+				}
+			}
+		}
+		return assigned, ranges
+	}
+
+	var values func(expr ast.Expr) []string
+	var elements func(expr ast.Expr, keys bool) []string
+
+	// union appends the values of several expressions, or returns nil if one of them is not enumerable
+	union := func(found []string, exprs []ast.Expr, enumerate func(ast.Expr) []string) []string {
+		for _, expr := range exprs {
+			more := enumerate(expr)
+			if more == nil {
+				return nil
+			}
+			for _, value := range more {
+				if !util.Contains(found, value) {
+					found = append(found, value)
+				}
+			}
+		}
+		return found
+	}
+
+	values = func(expr ast.Expr) []string {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if value, err := strconv.Unquote(e.Value); err == nil && e.Kind == token.STRING {
+				return []string{value}
+			}
+		case *ast.ParenExpr:
+			return values(e.X)
+		case *ast.IndexExpr:
+			return elements(e.X, false)
+		case *ast.Ident:
+			if visiting[e.Name] {
+				return nil
+			}
+			visiting[e.Name] = true
+			defer delete(visiting, e.Name)
+			assigned, ranges := sources(e.Name)
+			if len(assigned) == 0 && len(ranges) == 0 {
+				return nil
+			}
+			found := union([]string{}, assigned, values)
+			for _, rs := range ranges {
+				if found == nil {
+					return nil
+				}
+				// The key of a slice is an index, the key of a map one of its keys
+				keys := false
+				if ident, ok := rs.Key.(*ast.Ident); ok && ident.Name == e.Name {
+					keys = true
+				}
+				found = union(found, []ast.Expr{rs.X}, func(x ast.Expr) []string { return elements(x, keys) })
+			}
+			return found
+		}
+		return nil
+	}
+
+	elements = func(expr ast.Expr, keys bool) []string {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			return elements(e.X, keys)
+		case *ast.CompositeLit:
+			_, isMap := e.Type.(*ast.MapType)
+			if keys && !isMap {
+				return nil
+			}
+			exprs := []ast.Expr{}
+			for _, elt := range e.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				switch {
+				case ok && keys:
+					exprs = append(exprs, kv.Key)
+				case ok:
+					exprs = append(exprs, kv.Value)
+				default:
+					exprs = append(exprs, elt)
+				}
+			}
+			return union([]string{}, exprs, values)
+		case *ast.CallExpr:
+			// append(names, "a", "b") holds the elements of names and the appended values
+			if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "append" && len(e.Args) > 0 && !keys && !e.Ellipsis.IsValid() {
+				// Appending to the collection being enumerated adds to the elements its other assignments give
+				found := []string{}
+				if base, ok := e.Args[0].(*ast.Ident); !ok || !visiting[base.Name] {
+					found = elements(e.Args[0], false)
+				}
+				if found == nil {
+					return nil
+				}
+				return union(found, e.Args[1:], values)
+			}
+		case *ast.Ident:
+			if visiting[e.Name] {
+				return nil
+			}
+			visiting[e.Name] = true
+			defer delete(visiting, e.Name)
+			assigned, ranges := sources(e.Name)
+			if len(assigned) == 0 || len(ranges) > 0 {
+				return nil
+			}
+			return union([]string{}, assigned, func(x ast.Expr) []string { return elements(x, keys) })
+		}
+		return nil
+	}
+
+	found := values(expr)
+	if len(found) == 0 {
+		return nil
+	}
+	return found
+}
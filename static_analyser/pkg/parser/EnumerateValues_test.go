@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"go/ast"
+	"go/parser"
+	"reflect"
+	"testing"
+)
+
+// enumerateSource holds a function per shape of finite set, each passing the value to enumerate to use
+const enumerateSource = `package main
+
+var regions = []string{"eu", "us"}
+
+const primary = "orders"
+
+func use(name string) {}
+
+func literal() {
+	use("orders")
+}
+
+func constant() {
+	use(primary)
+}
+
+func sliceRange() {
+	for _, name := range []string{"orders", "payments"} {
+		use(name)
+	}
+}
+
+func arrayRange() {
+	names := [...]string{"orders", "payments"}
+	for _, name := range names {
+		use(name)
+	}
+}
+
+func appended() {
+	names := []string{"orders"}
+	names = append(names, "payments", "ledger")
+	for _, name := range names {
+		use(name)
+	}
+}
+
+func topLevelSlice() {
+	for _, region := range regions {
+		use(region)
+	}
+}
+
+func mapKeys() {
+	for name := range map[string]int{"orders": 1, "payments": 2} {
+		use(name)
+	}
+}
+
+func mapValues() {
+	backends := map[string]string{"a": "orders", "b": "payments"}
+	for _, name := range backends {
+		use(name)
+	}
+}
+
+func mapIndex(key string) {
+	backends := map[string]string{"a": "orders", "b": "payments"}
+	use(backends[key])
+}
+
+func switchCases(env string) {
+	var name string
+	switch env {
+	case "prod":
+		name = "orders"
+	case "staging":
+		name = "orders-staging"
+	default:
+		name = "orders-dev"
+	}
+	use(name)
+}
+
+func parameter(name string) {
+	use(name)
+}
+
+func result() {
+	name := lookup()
+	use(name)
+}
+
+func compound() {
+	name := "orders"
+	name += "-v2"
+	use(name)
+}
+
+func partlyKnown(extra string) {
+	names := []string{"orders"}
+	names = append(names, extra)
+	for _, name := range names {
+		use(name)
+	}
+}
+
+func multiValue() {
+	name, _ := pair()
+	use(name)
+}
+
+func lookup() string { return "" }
+
+func pair() (string, error) { return "", nil }
+`
+
+func TestEnumerateValues(test *testing.T) {
+	// TestEnumerateValues checks the values enumerated for each shape of finite set, and that sets holding a value not
+	// known statically are not enumerated.
+	//
+	// test: The test.
+
+	file, err := parser.ParseFile(FileSet, "enumerate.go", enumerateSource, 0)
+	if err != nil {
+		test.Fatal(err)
+	}
+
+	cases := []struct {
+		function string
+		want     []string
+	}{
+		{"literal", []string{"orders"}},
+		{"constant", []string{"orders"}},
+		{"sliceRange", []string{"orders", "payments"}},
+		{"arrayRange", []string{"orders", "payments"}},
+		{"appended", []string{"orders", "payments", "ledger"}},
+		{"topLevelSlice", []string{"eu", "us"}},
+		{"mapKeys", []string{"orders", "payments"}},
+		{"mapValues", []string{"orders", "payments"}},
+		{"mapIndex", []string{"orders", "payments"}},
+		{"switchCases", []string{"orders", "orders-staging", "orders-dev"}},
+		{"parameter", nil},
+		{"result", nil},
+		{"compound", nil},
+		{"partlyKnown", nil},
+		{"multiValue", nil},
+	}
+	for _, c := range cases {
+		var fn *ast.FuncDecl
+		for _, decl := range file.Decls {
+			if f, ok := decl.(*ast.FuncDecl); ok && f.Name.Name == c.function {
+				fn = f
+			}
+		}
+		if fn == nil {
+			test.Fatalf("no function %s", c.function)
+		}
+		var arg ast.Expr
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && arg == nil {
+				if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "use" {
+					arg = call.Args[0]
+				}
+			}
+			return true
+		})
+		if got := EnumerateValues(file, fn, arg); !reflect.DeepEqual(got, c.want) {
+			test.Errorf("%s: got %q, want %q", c.function, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"go/ast"
+	t "static_analyser/pkg/types"
+)
+
+func FindConfigAccesses(node ast.Node) []t.ConfigAccess {
+	// FindConfigAccesses traverses the AST to find calls to the Nacos configuration center.
+	//
+	// node: The root node of the AST.
+	//
+	// Returns:
+	// A slice of ConfigAccess structs, one per GetConfig, ListenConfig, PublishConfig or DeleteConfig call,
+	// with the DataId and Group resolved as far as possible. A missing Group defaults to DEFAULT_GROUP.
+
+	accesses := map[string]string{"GetConfig": "read", "ListenConfig": "read", "PublishConfig": "write", "DeleteConfig": "write"}
+
+	var configs []t.ConfigAccess
+	inspectFunctions(node, nil, nil, func(n ast.Node, scope *functionScope) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || accesses[sel.Sel.Name] == "" {
+			return
+		}
+
+		for _, arg := range call.Args {
+			if unary, ok := arg.(*ast.UnaryExpr); ok {
+				arg = unary.X
+			}
+			if ident, ok := arg.(*ast.Ident); ok {
+				arg = scope.env[ident.Name]
+			}
+			lit, ok := arg.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			if typ, ok := lit.Type.(*ast.SelectorExpr); !ok || typ.Sel.Name != "ConfigParam" {
+				continue
+			}
+
+			config := t.ConfigAccess{Group: "DEFAULT_GROUP", Access: accesses[sel.Sel.Name], Function: sel.Sel.Name, Location: Location(call.Pos())}
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				switch key.Name {
+				case "DataId":
+					config.DataId = scope.Resolve(kv.Value)
+				case "Group":
+					if group := scope.Resolve(kv.Value); group != "" {
+						config.Group = group
+					}
+				}
+			}
+			configs = append(configs, config)
+		}
+	})
+	return configs
+}
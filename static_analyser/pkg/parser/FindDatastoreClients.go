@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"go/ast"
+	"path"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func FindDatastoreClients(node ast.Node, values map[string]string) []t.ClientCall {
+	// FindDatastoreClients traverses the AST to find connections to databases, caches and message brokers.
+	// Supported are database/sql, gorm, go-redis, sarama, kafka-go, amqp and the mongo driver.
+	//
+	// node: The root node of the AST.
+	// values: The configuration values the service reads, used to resolve placeholders. May be nil.
+	//
+	// Returns:
+	// A slice of ClientCall structs, one per address connected to, with Kind set to the kind of datastore,
+	// such as "mysql", "redis" or "kafka". Addresses are taken from DSNs, URLs and client options.
+
+	importName := func(paths []string, defaultName string) string {
+		for _, p := range paths {
+			if name := util.ImportName(node, p, defaultName); name != "" {
+				return name
+			}
+		}
+		return ""
+	}
+	sqlName := importName([]string{"database/sql"}, "sql")
+	gormName := importName([]string{"github.com/jinzhu/gorm", "gorm.io/gorm"}, "gorm")
+	redisName := importName([]string{"github.com/redis/go-redis/v9", "github.com/go-redis/redis/v8", "github.com/go-redis/redis/v7", "github.com/go-redis/redis"}, "redis")
+	saramaName := importName([]string{"github.com/IBM/sarama", "github.com/Shopify/sarama"}, "sarama")
+	kafkaName := importName([]string{"github.com/segmentio/kafka-go"}, "kafka")
+	amqpName := importName([]string{"github.com/streadway/amqp"}, "amqp")
+	if amqpName == "" {
+		amqpName = importName([]string{"github.com/rabbitmq/amqp091-go"}, "amqp091")
+	}
+	mongoOptionsName := importName([]string{"go.mongodb.org/mongo-driver/mongo/options", "go.mongodb.org/mongo-driver/v2/mongo/options"}, "options")
+
+	// gormDrivers maps the local names of gorm v2 driver packages to the kind of database they open
+	gormDrivers := make(map[string]string)
+	if file, ok := node.(*ast.File); ok {
+		for _, spec := range file.Imports {
+			p := strings.Trim(spec.Path.Value, "\"")
+			if !strings.HasPrefix(p, "gorm.io/driver/") {
+				continue
+			}
+			name := path.Base(p)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			gormDrivers[name] = path.Base(p)
+		}
+	}
+
+	sqlDrivers := map[string]string{
+		"mysql": "mysql", "postgres": "postgres", "postgresql": "postgres", "pgx": "postgres",
+		"sqlserver": "sqlserver", "mssql": "sqlserver", "clickhouse": "clickhouse", "oracle": "oracle", "godror": "oracle",
+	}
+
+	isPackage := func(expr ast.Expr, name string) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && name != "" && ident.Name == name
+	}
+
+	var calls []t.ClientCall
+
+	inspectFunctions(node, nil, values, func(n ast.Node, scope *functionScope) {
+		// addresses resolves an expression holding one or more addresses, such as a []string literal or a variable holding one
+		var addresses func(expr ast.Expr, depth int) []string
+		addresses = func(expr ast.Expr, depth int) []string {
+			if unary, ok := expr.(*ast.UnaryExpr); ok {
+				expr = unary.X
+			}
+			switch e := expr.(type) {
+			case *ast.Ident:
+				if value, ok := scope.env[e.Name]; ok && depth < 5 {
+					if _, ok := value.(*ast.CompositeLit); ok {
+						return addresses(value, depth+1)
+					}
+				}
+			case *ast.CompositeLit:
+				result := []string{}
+				for _, elt := range e.Elts {
+					result = append(result, scope.Resolve(elt))
+				}
+				return result
+			case *ast.CallExpr:
+				if sel, ok := e.Fun.(*ast.SelectorExpr); ok && isPackage(sel.X, "strings") && sel.Sel.Name == "Split" && len(e.Args) == 2 {
+					return strings.Split(scope.Resolve(e.Args[0]), scope.Resolve(e.Args[1]))
+				}
+			}
+			return []string{scope.Resolve(expr)}
+		}
+
+		// field finds the value of a field in a composite literal, following & and variables
+		field := func(expr ast.Expr, name string) ast.Expr {
+			if unary, ok := expr.(*ast.UnaryExpr); ok {
+				expr = unary.X
+			}
+			if ident, ok := expr.(*ast.Ident); ok {
+				expr = scope.env[ident.Name]
+				if unary, ok := expr.(*ast.UnaryExpr); ok {
+					expr = unary.X
+				}
+			}
+			lit, ok := expr.(*ast.CompositeLit)
+			if !ok {
+				return nil
+			}
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == name {
+						return kv.Value
+					}
+				}
+			}
+			return nil
+		}
+
+		record := func(kind string, library string, location ast.Node, hostPorts []string) {
+			for _, hostPort := range hostPorts {
+				for _, address := range util.ParseDSN(kind, hostPort) {
+					host, port := util.SplitHostPort(address)
+					calls = append(calls, t.ClientCall{Kind: kind, Library: library, URL: address, Host: host, Port: port, Location: Location(location.Pos())})
+				}
+			}
+		}
+
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			// kafka-go writers can be built directly: &kafka.Writer{Addr: kafka.TCP("host:9092")}
+			sel, ok := n.Type.(*ast.SelectorExpr)
+			if !ok || !isPackage(sel.X, kafkaName) || sel.Sel.Name != "Writer" {
+				return
+			}
+			if addr, ok := field(n, "Addr").(*ast.CallExpr); ok {
+				hostPorts := []string{}
+				for _, arg := range addr.Args {
+					hostPorts = append(hostPorts, addresses(arg, 0)...)
+				}
+				record("kafka", "kafka-go", n, hostPorts)
+			}
+
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			name := sel.Sel.Name
+			arg := func(i int) ast.Expr {
+				if i < len(n.Args) {
+					return n.Args[i]
+				}
+				return nil
+			}
+
+			switch {
+			case isPackage(sel.X, sqlName) && name == "Open" && arg(1) != nil:
+				if kind, ok := sqlDrivers[scope.Resolve(arg(0))]; ok {
+					record(kind, "database/sql", n, []string{scope.Resolve(arg(1))})
+				}
+
+			case isPackage(sel.X, gormName) && name == "Open" && arg(0) != nil:
+				if arg(1) != nil {
+					// gorm v1: gorm.Open("mysql", dsn)
+					if kind, ok := sqlDrivers[scope.Resolve(arg(0))]; ok {
+						record(kind, "gorm", n, []string{scope.Resolve(arg(1))})
+						return
+					}
+				}
+				// gorm v2: gorm.Open(mysql.Open(dsn), &gorm.Config{})
+				if open, ok := arg(0).(*ast.CallExpr); ok && len(open.Args) > 0 {
+					if driver, ok := open.Fun.(*ast.SelectorExpr); ok {
+						if pkg, ok := driver.X.(*ast.Ident); ok {
+							if kind, ok := sqlDrivers[gormDrivers[pkg.Name]]; ok {
+								record(kind, "gorm", n, []string{scope.Resolve(open.Args[0])})
+							}
+						}
+					}
+				}
+
+			case isPackage(sel.X, redisName):
+				switch name {
+				case "NewClient", "NewFailoverClient":
+					if addr := field(arg(0), "Addr"); addr != nil {
+						record("redis", "go-redis", n, addresses(addr, 0))
+					}
+					if addrs := field(arg(0), "SentinelAddrs"); addrs != nil {
+						record("redis", "go-redis", n, addresses(addrs, 0))
+					}
+				case "NewClusterClient", "NewUniversalClient", "NewRing":
+					if addrs := field(arg(0), "Addrs"); addrs != nil {
+						record("redis", "go-redis", n, addresses(addrs, 0))
+					}
+				case "ParseURL":
+					if arg(0) != nil {
+						record("redis", "go-redis", n, []string{scope.Resolve(arg(0))})
+					}
+				}
+
+			case isPackage(sel.X, saramaName):
+				switch name {
+				case "NewClient", "NewSyncProducer", "NewAsyncProducer", "NewConsumer", "NewConsumerGroup", "NewClusterAdmin":
+					if arg(0) != nil {
+						record("kafka", "sarama", n, addresses(arg(0), 0))
+					}
+				}
+
+			case isPackage(sel.X, kafkaName):
+				switch name {
+				case "NewReader", "NewWriter":
+					if brokers := field(arg(0), "Brokers"); brokers != nil {
+						record("kafka", "kafka-go", n, addresses(brokers, 0))
+					}
+				case "Dial":
+					if arg(1) != nil {
+						record("kafka", "kafka-go", n, []string{scope.Resolve(arg(1))})
+					}
+				case "DialContext", "DialLeader":
+					if arg(2) != nil {
+						record("kafka", "kafka-go", n, []string{scope.Resolve(arg(2))})
+					}
+				}
+
+			case isPackage(sel.X, amqpName):
+				switch name {
+				case "Dial", "DialTLS", "DialConfig":
+					if arg(0) != nil {
+						record("amqp", "amqp", n, []string{scope.Resolve(arg(0))})
+					}
+				}
+
+			case mongoOptionsName != "" && (name == "ApplyURI" || name == "SetHosts") && arg(0) != nil:
+				// options.Client().ApplyURI(uri) or .SetHosts([]string{...})
+				if name == "ApplyURI" {
+					record("mongo", "mongo-driver", n, []string{scope.Resolve(arg(0))})
+				} else {
+					record("mongo", "mongo-driver", n, addresses(arg(0), 0))
+				}
+			}
+		}
+	})
+
+	return calls
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"go/ast"
+	"regexp"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+// grpcClientConstructor matches the constructors generated for gRPC clients, such as NewGreeterClient.
+var grpcClientConstructor = regexp.MustCompile(`^New(\w+)Client$`)
+
+func FindGRPCClientCalls(node ast.Node, wrappers []t.ServiceDiscoveryWrapper, serviceNames map[string]string, values map[string]string) []t.ClientCall {
+	// FindGRPCClientCalls traverses the AST to find gRPC client connections and the service methods called over them.
+	//
+	// node: The root node of the AST.
+	// wrappers: The service discovery wrappers of the service, used to tell whether a target derives from a discovered instance.
+	// serviceNames: The fully qualified gRPC service names found by FindGRPCServiceNames, keyed by short name.
+	// values: The configuration values the service reads, used to resolve placeholders. May be nil.
+	//
+	// Returns:
+	// A slice of ClientCall structs, one per method called on a generated client, with the full method name as Path.
+	// Connections on which no method call is found are returned once, without a Path.
+	// Targets using the nacos:// resolver scheme count as discovering the service they name.
+	// Connections and clients are followed within the function they are created in.
+
+	grpcName := util.ImportName(node, "google.golang.org/grpc", "grpc")
+	if grpcName == "" {
+		return nil
+	}
+
+	// connection is a dialled gRPC connection, and used tells whether any method was called over it
+	type connection struct {
+		call t.ClientCall
+		used bool
+	}
+	// client is a generated client created from a connection
+	type client struct {
+		conn    *connection
+		service string
+	}
+	// variable is a variable of a function, as functions may use the same names for different connections
+	type variable struct {
+		scope *functionScope
+		name  string
+	}
+
+	connections := make(map[variable]*connection)
+	dialled := make(map[*ast.CallExpr]bool)
+	clients := make(map[variable]*client)
+	var ordered []*connection
+	var calls []t.ClientCall
+
+	dial := func(call *ast.CallExpr, scope *functionScope) *connection {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || len(call.Args) == 0 {
+			return nil
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != grpcName {
+			return nil
+		}
+		target := call.Args[0]
+		switch sel.Sel.Name {
+		case "Dial", "NewClient":
+		case "DialContext":
+			if len(call.Args) < 2 {
+				return nil
+			}
+			target = call.Args[1]
+		default:
+			return nil
+		}
+
+		url := scope.Resolve(target)
+		scheme, host, port, path := util.ParseURLTemplate(url)
+		c := t.ClientCall{Kind: "grpc", Library: "grpc", URL: url, Host: host, Port: port, Location: Location(call.Pos())}
+		switch scheme {
+		case "nacos":
+			// nacos://[server[:port]]/service or nacos://service; either way the service is discovered through Nacos
+			c.Discovered = true
+			c.ServiceName = strings.Trim(path, "/")
+			if c.ServiceName == "" {
+				c.ServiceName = host
+			}
+			c.Host, c.Port = "", ""
+		case "dns", "passthrough":
+			_, c.Host, c.Port, _ = util.ParseURLTemplate(strings.TrimPrefix(path, "/"))
+		default:
+			if scheme == "" {
+				c.Host, c.Port = util.SplitHostPort(url)
+			}
+			c.ServiceName, c.Discovered = scope.DiscoveredService(target)
+		}
+		return &connection{call: c}
+	}
+
+	inspectFunctions(node, wrappers, values, func(n ast.Node, scope *functionScope) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Rhs) != 1 {
+				return
+			}
+			call, ok := n.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return
+			}
+			ident, ok := n.Lhs[0].(*ast.Ident)
+			if !ok {
+				return
+			}
+
+			if conn := dial(call, scope); conn != nil {
+				dialled[call] = true
+				connections[variable{scope, ident.Name}] = conn
+				ordered = append(ordered, conn)
+				return
+			}
+
+			// A generated constructor wraps a known connection into a client
+			name := ""
+			switch fun := call.Fun.(type) {
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			case *ast.Ident:
+				name = fun.Name
+			}
+			match := grpcClientConstructor.FindStringSubmatch(name)
+			if match == nil || len(call.Args) != 1 {
+				return
+			}
+			if arg, ok := call.Args[0].(*ast.Ident); ok && connections[variable{scope, arg.Name}] != nil {
+				service := match[1]
+				if full, ok := serviceNames[service]; ok {
+					service = full
+				}
+				clients[variable{scope, ident.Name}] = &client{conn: connections[variable{scope, arg.Name}], service: service}
+			}
+
+		case *ast.CallExpr:
+			// Connections that are dialled without being assigned still show the target is called
+			if !dialled[n] {
+				if conn := dial(n, scope); conn != nil {
+					dialled[n] = true
+					ordered = append(ordered, conn)
+					return
+				}
+			}
+
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			receiver, ok := sel.X.(*ast.Ident)
+			if !ok || clients[variable{scope, receiver.Name}] == nil {
+				return
+			}
+			c := clients[variable{scope, receiver.Name}]
+			c.conn.used = true
+			call := c.conn.call
+			call.Path = "/" + c.service + "/" + sel.Sel.Name
+			call.Location = Location(n.Pos())
+			calls = append(calls, call)
+		}
+	})
+
+	for _, conn := range ordered {
+		if !conn.used {
+			calls = append(calls, conn.call)
+		}
+	}
+	return calls
+}
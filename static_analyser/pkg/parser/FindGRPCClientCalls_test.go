@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"go/parser"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+// grpcSource dials gRPC targets in each form FindGRPCClientCalls follows, with the same variable names in every function
+const grpcSource = `package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"google.golang.org/grpc"
+)
+
+var Greeter_ServiceDesc = grpc.ServiceDesc{ServiceName: "helloworld.Greeter"}
+
+func resolver(ctx context.Context) {
+	conn, _ := grpc.Dial("nacos://greeter")
+	client := NewGreeterClient(conn)
+	client.SayHello(ctx, nil)
+}
+
+func resolverWithServer(ctx context.Context) {
+	conn, _ := grpc.NewClient("nacos://nacos.default.svc:8848/ledger")
+	client := pb.NewLedgerClient(conn)
+	client.Append(ctx, nil)
+	client.Read(ctx, nil)
+}
+
+func discovered(ctx context.Context, naming naming_client.INamingClient) {
+	instance, _ := naming.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "greeter"})
+	conn, _ := grpc.DialContext(ctx, fmt.Sprintf("%s:%d", instance.Ip, instance.Port))
+	client := NewGreeterClient(conn)
+	client.SayGoodbye(ctx, nil)
+}
+
+func dns() {
+	conn, _ := grpc.Dial("dns:///audit.default.svc:9000")
+	client := &http.Client{}
+	client.Get("http://ledger.default.svc:8080/health")
+	conn.Close()
+}
+
+func unassigned() {
+	defer grpc.Dial("passthrough:///metrics:9100")
+}
+`
+
+func TestFindGRPCClientCalls(test *testing.T) {
+	// TestFindGRPCClientCalls checks the connections and method calls found for nacos://, discovered, DNS and
+	// passthrough targets dialled with Dial, DialContext and NewClient, and that connections and clients are followed
+	// within their own function only.
+	//
+	// test: The test.
+
+	file, err := parser.ParseFile(FileSet, "grpc.go", grpcSource, 0)
+	if err != nil {
+		test.Fatal(err)
+	}
+	calls := FindGRPCClientCalls(file, nil, FindGRPCServiceNames(file), nil)
+
+	want := []t.ClientCall{
+		{URL: "nacos://greeter", Path: "/helloworld.Greeter/SayHello", Discovered: true, ServiceName: "greeter", Location: "grpc.go:17"},
+		{URL: "nacos://nacos.default.svc:8848/ledger", Path: "/Ledger/Append", Discovered: true, ServiceName: "ledger", Location: "grpc.go:23"},
+		{URL: "nacos://nacos.default.svc:8848/ledger", Path: "/Ledger/Read", Discovered: true, ServiceName: "ledger", Location: "grpc.go:24"},
+		{URL: "{instance.Ip}:{instance.Port}", Host: "{instance.Ip}", Port: "{instance.Port}", Path: "/helloworld.Greeter/SayGoodbye", Discovered: true, ServiceName: "greeter", Location: "grpc.go:31"},
+		{URL: "dns:///audit.default.svc:9000", Host: "audit.default.svc", Port: "9000", Location: "grpc.go:35"},
+		{URL: "passthrough:///metrics:9100", Host: "metrics", Port: "9100", Location: "grpc.go:42"},
+	}
+	if len(calls) != len(want) {
+		test.Fatalf("got %d calls %+v, want %d", len(calls), calls, len(want))
+	}
+	for i, call := range calls {
+		want[i].Kind, want[i].Library = "grpc", "grpc"
+		if call != want[i] {
+			test.Errorf("got call %+v, want %+v", call, want[i])
+		}
+	}
+}
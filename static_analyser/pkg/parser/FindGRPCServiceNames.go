@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+func FindGRPCServiceNames(node ast.Node) map[string]string {
+	// FindGRPCServiceNames finds the fully qualified names of the gRPC services described in generated code.
+	//
+	// node: The root node of the AST.
+	//
+	// Returns:
+	// A map where the keys are the short service names used in generated constructors, such as "Greeter",
+	// and the values are the fully qualified names from the grpc.ServiceDesc, such as "helloworld.Greeter".
+
+	names := make(map[string]string)
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		sel, ok := lit.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "ServiceDesc" {
+			return true
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok || key.Name != "ServiceName" {
+				continue
+			}
+			value, ok := kv.Value.(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			if full, err := strconv.Unquote(value.Value); err == nil {
+				names[full[strings.LastIndex(full, ".")+1:]] = full
+			}
+		}
+		return true
+	})
+	return names
+}
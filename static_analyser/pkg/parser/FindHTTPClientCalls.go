@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"go/ast"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func FindHTTPClientCalls(node ast.Node, wrappers []t.ServiceDiscoveryWrapper, values map[string]string) []t.ClientCall {
+	// FindHTTPClientCalls traverses the AST to find HTTP client calls made with net/http, resty or fasthttp.
+	//
+	// node: The root node of the AST.
+	// wrappers: The service discovery wrappers of the service, used to tell whether a URL derives from a discovered instance.
+	// values: The configuration values the service reads, used to resolve placeholders. May be nil.
+	//
+	// Returns:
+	// A slice of ClientCall structs, one per call, with the URL resolved as far as possible.
+
+	httpName := util.ImportName(node, "net/http", "http")
+	restyName := util.ImportName(node, "github.com/go-resty/resty/v2", "resty")
+	if restyName == "" {
+		restyName = util.ImportName(node, "gopkg.in/resty.v1", "resty")
+	}
+	fasthttpName := util.ImportName(node, "github.com/valyala/fasthttp", "fasthttp")
+
+	httpMethods := map[string]string{"Get": "GET", "Head": "HEAD", "Post": "POST", "PostForm": "POST", "Put": "PUT", "Delete": "DELETE", "Patch": "PATCH", "Options": "OPTIONS"}
+
+	isPackage := func(expr ast.Expr, name string) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && name != "" && ident.Name == name
+	}
+
+	// clientLibrary works out which library a client value created by an expression belongs to
+	clientLibrary := func(expr ast.Expr) string {
+		if unary, ok := expr.(*ast.UnaryExpr); ok {
+			expr = unary.X
+		}
+		switch e := expr.(type) {
+		case *ast.CompositeLit:
+			if sel, ok := e.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "Client" {
+				if isPackage(sel.X, httpName) {
+					return "net/http"
+				}
+				if isPackage(sel.X, fasthttpName) {
+					return "fasthttp"
+				}
+			}
+		case *ast.SelectorExpr:
+			if isPackage(e.X, httpName) && e.Sel.Name == "DefaultClient" {
+				return "net/http"
+			}
+		case *ast.CallExpr:
+			if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+				if isPackage(sel.X, restyName) && sel.Sel.Name == "New" {
+					return "resty"
+				}
+				if isPackage(sel.X, fasthttpName) && sel.Sel.Name == "AcquireRequest" {
+					return "fasthttp-request"
+				}
+			}
+		}
+		return ""
+	}
+
+	// restyRequest reports whether an expression is a resty request chain such as client.R().SetHeader(...)
+	restyRequest := func(expr ast.Expr) bool {
+		found := false
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "R" && len(call.Args) == 0 {
+					found = true
+				}
+			}
+			return !found
+		})
+		return found
+	}
+
+	var calls []t.ClientCall
+	clients := make(map[string]string)
+
+	inspectFunctions(node, wrappers, values, func(n ast.Node, scope *functionScope) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						if library := clientLibrary(n.Rhs[i]); library != "" {
+							clients[ident.Name] = library
+						}
+					}
+				}
+			}
+
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+
+			var library, method string
+			var urlArg ast.Expr
+			receiver, _ := sel.X.(*ast.Ident)
+			receiverLibrary := ""
+			if receiver != nil {
+				receiverLibrary = clients[receiver.Name]
+			}
+
+			switch {
+			case isPackage(sel.X, httpName) || receiverLibrary == "net/http":
+				library = "net/http"
+				switch sel.Sel.Name {
+				case "Get", "Head", "Post", "PostForm":
+					method = httpMethods[sel.Sel.Name]
+					if len(n.Args) > 0 {
+						urlArg = n.Args[0]
+					}
+				case "NewRequest":
+					if len(n.Args) > 1 && receiverLibrary == "" {
+						method, urlArg = scope.Resolve(n.Args[0]), n.Args[1]
+					}
+				case "NewRequestWithContext":
+					if len(n.Args) > 2 && receiverLibrary == "" {
+						method, urlArg = scope.Resolve(n.Args[1]), n.Args[2]
+					}
+				}
+
+			case isPackage(sel.X, fasthttpName) || receiverLibrary == "fasthttp":
+				library = "fasthttp"
+				switch sel.Sel.Name {
+				case "Get", "GetTimeout", "GetDeadline", "Post":
+					method = httpMethods[sel.Sel.Name]
+					if method == "" {
+						method = "GET"
+					}
+					if len(n.Args) > 1 {
+						urlArg = n.Args[1]
+					}
+				}
+
+			case receiverLibrary == "fasthttp-request":
+				library = "fasthttp"
+				if sel.Sel.Name == "SetRequestURI" && len(n.Args) > 0 {
+					urlArg = n.Args[0]
+				}
+
+			case restyName != "" && httpMethods[sel.Sel.Name] != "" && restyRequest(sel.X):
+				library = "resty"
+				method = httpMethods[sel.Sel.Name]
+				if len(n.Args) > 0 {
+					urlArg = n.Args[0]
+				}
+			}
+			if urlArg == nil {
+				return
+			}
+
+			// Method constants such as http.MethodGet resolve to placeholders, so map them back
+			if strings.HasPrefix(method, "{"+httpName+".Method") {
+				method = strings.ToUpper(strings.TrimSuffix(strings.TrimPrefix(method, "{"+httpName+".Method"), "}"))
+			}
+			if !util.IsResolved(method) {
+				method = ""
+			}
+
+			url := scope.Resolve(urlArg)
+			scheme, host, port, path := util.ParseURLTemplate(url)
+			if port == "" && util.IsResolved(host) {
+				switch scheme {
+				case "http":
+					port = "80"
+				case "https":
+					port = "443"
+				}
+			}
+			call := t.ClientCall{Kind: "http", Library: library, Method: method, URL: url, Host: host, Port: port, Path: path, Location: Location(n.Pos())}
+			call.ServiceName, call.Discovered = scope.DiscoveredService(urlArg)
+			calls = append(calls, call)
+		}
+	})
+
+	return calls
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"go/ast"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+)
+
+func FindListeners(node ast.Node, values map[string]string) []t.Listener {
+	// FindListeners traverses the AST to find the server sockets the service listens on.
+	// Listeners are taken from net.Listen, http.ListenAndServe and http.ListenAndServeTLS calls, http.Server literals,
+	// the Run and RunTLS methods of gin engines and the Start and StartTLS methods of echo instances. A net.Listen
+	// listener passed to the Serve method of a gRPC server is of kind "grpc".
+	//
+	// node: The root node of the AST.
+	// values: The configuration values the service reads, used to resolve placeholders. May be nil.
+	//
+	// Returns:
+	// A slice of Listener structs, in source order. A gin engine run without an address listens on ":8080".
+
+	netName := util.ImportName(node, "net", "net")
+	httpName := util.ImportName(node, "net/http", "http")
+	ginName := util.ImportName(node, "github.com/gin-gonic/gin", "gin")
+	echoName := util.ImportName(node, "github.com/labstack/echo/v4", "echo")
+	if echoName == "" {
+		echoName = util.ImportName(node, "github.com/labstack/echo", "echo")
+	}
+	grpcName := util.ImportName(node, "google.golang.org/grpc", "grpc")
+	if netName == "" && httpName == "" && ginName == "" && echoName == "" {
+		return nil
+	}
+
+	// packageCall returns the name of the function a call of <pkg>.<function> calls
+	packageCall := func(expr ast.Expr, pkg string) string {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || pkg == "" {
+			return ""
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != pkg {
+			return ""
+		}
+		return sel.Sel.Name
+	}
+
+	// createdBy tells whether a variable holds the result of one of the given functions of a package
+	createdBy := func(expr ast.Expr, scope *functionScope, pkg string, functions ...string) bool {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		value, ok := scope.env[ident.Name]
+		return ok && util.Contains(functions, packageCall(value, pkg))
+	}
+
+	listeners := []t.Listener{}
+	listen := func(pos ast.Node, kind string, address string) int {
+		_, port := util.SplitHostPort(address)
+		listeners = append(listeners, t.Listener{Kind: kind, Address: address, Port: port, Location: Location(pos.Pos())})
+		return len(listeners) - 1
+	}
+
+	// Variables holding a net.Listen listener, by the index of their listener
+	sockets := make(map[string]int)
+	seen := make(map[*ast.CallExpr]bool)
+	inspectFunctions(node, nil, values, func(n ast.Node, scope *functionScope) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// The listener returned with an error is not recorded in the scope, so it is tracked here
+			if len(n.Rhs) != 1 || len(n.Lhs) == 0 || packageCall(n.Rhs[0], netName) != "Listen" {
+				return
+			}
+			call := n.Rhs[0].(*ast.CallExpr)
+			if len(call.Args) < 2 {
+				return
+			}
+			seen[call] = true
+			index := listen(call, "net", scope.Resolve(call.Args[1]))
+			if ident, ok := n.Lhs[0].(*ast.Ident); ok && ident.Name != "_" {
+				sockets[ident.Name] = index
+			}
+
+		case *ast.CompositeLit:
+			sel, ok := n.Type.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Server" {
+				return
+			}
+			if x, ok := sel.X.(*ast.Ident); !ok || x.Name != httpName || httpName == "" {
+				return
+			}
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Addr" {
+						listen(n, "http", scope.Resolve(kv.Value))
+					}
+				}
+			}
+
+		case *ast.CallExpr:
+			if seen[n] {
+				return
+			}
+			switch name := packageCall(n, netName); {
+			case name == "Listen" && len(n.Args) >= 2:
+				listen(n, "net", scope.Resolve(n.Args[1]))
+				return
+			case name != "":
+				return
+			}
+			switch name := packageCall(n, httpName); {
+			case (name == "ListenAndServe" || name == "ListenAndServeTLS") && len(n.Args) > 0:
+				listen(n, "http", scope.Resolve(n.Args[0]))
+				return
+			case name != "":
+				return
+			}
+
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			switch sel.Sel.Name {
+			case "Run", "RunTLS":
+				if !createdBy(sel.X, scope, ginName, "Default", "New") {
+					return
+				}
+				address := ":8080"
+				if len(n.Args) > 0 {
+					address = scope.Resolve(n.Args[0])
+				}
+				listen(n, "gin", address)
+			case "Start", "StartTLS":
+				if len(n.Args) > 0 && createdBy(sel.X, scope, echoName, "New") {
+					listen(n, "echo", scope.Resolve(n.Args[0]))
+				}
+			case "Serve":
+				if len(n.Args) != 1 || grpcName == "" || !createdBy(sel.X, scope, grpcName, "NewServer") {
+					return
+				}
+				if ident, ok := n.Args[0].(*ast.Ident); ok {
+					if index, ok := sockets[ident.Name]; ok {
+						listeners[index].Kind = "grpc"
+					}
+				}
+			}
+		}
+	})
+	return listeners
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestFindListeners(test *testing.T) {
+	// TestFindListeners checks the listeners found in the tests/listeners fixture, which listens with
+	// http.ListenAndServe, net.Listen, a gRPC server and an http.Server, on constant and computed addresses.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name string
+		file string
+		want []t.Listener
+	}{
+		{
+			name: "constant and computed addresses",
+			file: "../../../tests/listeners/web/main.go",
+			want: []t.Listener{
+				{Kind: "grpc", Address: ":9090", Port: "9090", Location: "../../../tests/listeners/web/main.go:29"},
+				{Kind: "net", Address: "localhost:6060", Port: "6060", Location: "../../../tests/listeners/web/main.go:45"},
+				{Kind: "http", Address: ":8080", Port: "8080", Location: "../../../tests/listeners/web/main.go:51"},
+			},
+		},
+		{
+			name: "address read at runtime",
+			file: "../../../tests/listeners/metrics/main.go",
+			want: []t.Listener{
+				{Kind: "http", Address: "{os.Getenv(\"METRICS_ADDR\")}", Port: "", Location: "../../../tests/listeners/metrics/main.go:29"},
+			},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			file, err := ParseFile(c.file)
+			if err != nil {
+				test.Fatal(err)
+			}
+			if got := FindListeners(file, nil); !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got listeners %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"go/ast"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func FindNacosClientConfigs(node ast.Node, values map[string]string) []t.NacosClientConfig {
+	// FindNacosClientConfigs traverses the AST to find the settings of the Nacos clients of the service.
+	// Settings are taken from constant.ClientConfig and constant.ServerConfig literals, including the elements of
+	// []constant.ServerConfig literals, and from the options of constant.NewClientConfig and constant.NewServerConfig calls.
+	//
+	// node: The root node of the AST.
+	// values: The configuration values the service reads, used to resolve placeholders. May be nil.
+	//
+	// Returns:
+	// A slice of NacosClientConfig structs, one per configuration, in source order. Fields of nested struct literals,
+	// such as TLSCfg, are named with a dot, as in "TLSCfg.Enable"; options are named after the field they set, so that
+	// constant.WithUsername sets "Username".
+
+	constantName := util.ImportName(node, "github.com/nacos-group/nacos-sdk-go/v2/common/constant", "constant")
+	if constantName == "" {
+		constantName = util.ImportName(node, "github.com/nacos-group/nacos-sdk-go/common/constant", "constant")
+	}
+	if constantName == "" {
+		return nil
+	}
+
+	// isConstant tells whether a type expression is constant.<name> or *constant.<name>
+	isConstant := func(expr ast.Expr, name string) bool {
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != name {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		return ok && pkg.Name == constantName
+	}
+
+	// setting resolves the value of a field, telling literals written in the source from values read at runtime
+	setting := func(name string, expr ast.Expr, scope *functionScope) t.NacosConfigSetting {
+		if ident, ok := expr.(*ast.Ident); ok && (ident.Name == "true" || ident.Name == "false") {
+			return t.NacosConfigSetting{Name: name, Value: ident.Name, Literal: true, Location: Location(expr.Pos())}
+		}
+		literal := util.IsResolved(util.ResolveTemplate(expr, scope.env))
+		return t.NacosConfigSetting{Name: name, Value: scope.Resolve(expr), Literal: literal, Location: Location(expr.Pos())}
+	}
+
+	// literalSettings collects the fields of a struct literal, descending into nested struct literals
+	var literalSettings func(lit *ast.CompositeLit, prefix string, scope *functionScope) []t.NacosConfigSetting
+	literalSettings = func(lit *ast.CompositeLit, prefix string, scope *functionScope) []t.NacosConfigSetting {
+		settings := []t.NacosConfigSetting{}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			value := kv.Value
+			if unary, ok := value.(*ast.UnaryExpr); ok {
+				value = unary.X
+			}
+			if nested, ok := value.(*ast.CompositeLit); ok {
+				settings = append(settings, literalSettings(nested, prefix+key.Name+".", scope)...)
+				continue
+			}
+			settings = append(settings, setting(prefix+key.Name, kv.Value, scope))
+		}
+		return settings
+	}
+
+	// optionSettings collects the settings of constant.WithXxx options
+	optionSettings := func(options []ast.Expr, scope *functionScope) []t.NacosConfigSetting {
+		settings := []t.NacosConfigSetting{}
+		for _, opt := range options {
+			call, ok := opt.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				continue
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !strings.HasPrefix(sel.Sel.Name, "With") {
+				continue
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != constantName {
+				continue
+			}
+			name := strings.TrimPrefix(sel.Sel.Name, "With")
+			arg := call.Args[0]
+			if unary, ok := arg.(*ast.UnaryExpr); ok {
+				arg = unary.X
+			}
+			if lit, ok := arg.(*ast.CompositeLit); ok && name == "TLS" {
+				settings = append(settings, literalSettings(lit, "TLSCfg.", scope)...)
+				continue
+			}
+			settings = append(settings, setting(name, call.Args[0], scope))
+		}
+		return settings
+	}
+
+	configs := []t.NacosClientConfig{}
+	inspectFunctions(node, nil, values, func(n ast.Node, scope *functionScope) {
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			switch {
+			case isConstant(n.Type, "ClientConfig"):
+				configs = append(configs, t.NacosClientConfig{Kind: "client", Location: Location(n.Pos()), Settings: literalSettings(n, "", scope)})
+			case isConstant(n.Type, "ServerConfig"):
+				configs = append(configs, t.NacosClientConfig{Kind: "server", Location: Location(n.Pos()), Settings: literalSettings(n, "", scope)})
+			default:
+				// Elements of a []constant.ServerConfig literal may leave out their type
+				array, ok := n.Type.(*ast.ArrayType)
+				if !ok || !isConstant(array.Elt, "ServerConfig") {
+					return
+				}
+				for _, elt := range n.Elts {
+					if unary, ok := elt.(*ast.UnaryExpr); ok {
+						elt = unary.X
+					}
+					if lit, ok := elt.(*ast.CompositeLit); ok && lit.Type == nil {
+						configs = append(configs, t.NacosClientConfig{Kind: "server", Location: Location(lit.Pos()), Settings: literalSettings(lit, "", scope)})
+					}
+				}
+			}
+
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != constantName {
+				return
+			}
+			switch sel.Sel.Name {
+			case "NewClientConfig":
+				configs = append(configs, t.NacosClientConfig{Kind: "client", Location: Location(n.Pos()), Settings: optionSettings(n.Args, scope)})
+			case "NewServerConfig":
+				if len(n.Args) < 2 {
+					return
+				}
+				settings := []t.NacosConfigSetting{setting("IpAddr", n.Args[0], scope), setting("Port", n.Args[1], scope)}
+				settings = append(settings, optionSettings(n.Args[2:], scope)...)
+				configs = append(configs, t.NacosClientConfig{Kind: "server", Location: Location(n.Pos()), Settings: settings})
+			}
+		}
+	})
+	return configs
+}
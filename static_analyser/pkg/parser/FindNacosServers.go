@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"go/ast"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strconv"
+	"strings"
+)
+
+func FindNacosServers(node ast.Node, values map[string]string) []t.ClientCall {
+	// FindNacosServers traverses the AST to find the Nacos servers the service connects to.
+	// Servers are taken from constant.ServerConfig literals, constant.NewServerConfig calls and their options,
+	// and address servers from the Endpoint of constant.ClientConfig literals and constant.WithEndpoint options.
+	//
+	// node: The root node of the AST.
+	// values: The configuration values the service reads, used to resolve placeholders. May be nil.
+	//
+	// Returns:
+	// A slice of ClientCall structs. Each server yields a "nacos" call on its HTTP port, 8848 by default, with the
+	// context path as Path, and a "nacos-grpc" call on its gRPC port, which Nacos 2.x puts 1000 above the HTTP port.
+	// Each address server yields a "nacos-endpoint" call, on port 8080 unless the endpoint names one.
+
+	constantName := util.ImportName(node, "github.com/nacos-group/nacos-sdk-go/v2/common/constant", "constant")
+	if constantName == "" {
+		constantName = util.ImportName(node, "github.com/nacos-group/nacos-sdk-go/common/constant", "constant")
+	}
+	if constantName == "" {
+		return nil
+	}
+
+	// isConstant tells whether a type expression is constant.<name> or *constant.<name>
+	isConstant := func(expr ast.Expr, name string) bool {
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != name {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		return ok && pkg.Name == constantName
+	}
+
+	// isServerConfigs tells whether a type expression is a slice or array of constant.ServerConfig
+	isServerConfigs := func(expr ast.Expr) bool {
+		array, ok := expr.(*ast.ArrayType)
+		return ok && isConstant(array.Elt, "ServerConfig")
+	}
+
+	// optionCall returns the name and argument of a constant.WithXxx option call
+	optionCall := func(expr ast.Expr) (string, ast.Expr) {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return "", nil
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", nil
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != constantName {
+			return "", nil
+		}
+		return sel.Sel.Name, call.Args[0]
+	}
+
+	var calls []t.ClientCall
+	server := func(pos ast.Node, host string, port string, grpcPort string, contextPath string) {
+		if port == "" {
+			port = "8848"
+		}
+		if contextPath == "" {
+			contextPath = "/nacos"
+		}
+		if grpcPort == "" {
+			if p, err := strconv.Atoi(port); err == nil {
+				grpcPort = strconv.Itoa(p + 1000)
+			} else {
+				grpcPort = "{" + strings.TrimSuffix(strings.TrimPrefix(port, "{"), "}") + " + 1000}"
+			}
+		}
+		location := Location(pos.Pos())
+		calls = append(calls,
+			t.ClientCall{Kind: "nacos", Library: "nacos-sdk-go", URL: host + ":" + port, Host: host, Port: port, Path: contextPath, Location: location},
+			t.ClientCall{Kind: "nacos-grpc", Library: "nacos-sdk-go", URL: host + ":" + grpcPort, Host: host, Port: grpcPort, Location: location})
+	}
+	endpoint := func(pos ast.Node, address string) {
+		host, port := util.SplitHostPort(address)
+		if port == "" {
+			port = "8080"
+		}
+		calls = append(calls, t.ClientCall{Kind: "nacos-endpoint", Library: "nacos-sdk-go", URL: host + ":" + port, Host: host, Port: port, Location: Location(pos.Pos())})
+	}
+
+	serverLiteral := func(lit *ast.CompositeLit, scope *functionScope) {
+		var host, port, grpcPort, contextPath string
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			switch key.Name {
+			case "IpAddr":
+				host = scope.Resolve(kv.Value)
+			case "Port":
+				port = scope.Resolve(kv.Value)
+			case "GrpcPort":
+				grpcPort = scope.Resolve(kv.Value)
+			case "ContextPath":
+				contextPath = scope.Resolve(kv.Value)
+			}
+		}
+		server(lit, host, port, grpcPort, contextPath)
+	}
+
+	inspectFunctions(node, nil, values, func(n ast.Node, scope *functionScope) {
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			switch {
+			case isConstant(n.Type, "ServerConfig"):
+				serverLiteral(n, scope)
+			case isServerConfigs(n.Type):
+				// Elements of a []constant.ServerConfig literal may leave out their type
+				for _, elt := range n.Elts {
+					if unary, ok := elt.(*ast.UnaryExpr); ok {
+						elt = unary.X
+					}
+					if lit, ok := elt.(*ast.CompositeLit); ok && lit.Type == nil {
+						serverLiteral(lit, scope)
+					}
+				}
+			case isConstant(n.Type, "ClientConfig"):
+				for _, elt := range n.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Endpoint" {
+							endpoint(n, scope.Resolve(kv.Value))
+						}
+					}
+				}
+			}
+
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != constantName {
+				return
+			}
+			switch sel.Sel.Name {
+			case "NewServerConfig":
+				if len(n.Args) < 2 {
+					return
+				}
+				var grpcPort, contextPath string
+				for _, opt := range n.Args[2:] {
+					switch option, arg := optionCall(opt); option {
+					case "WithGrpcPort":
+						grpcPort = scope.Resolve(arg)
+					case "WithContextPath":
+						contextPath = scope.Resolve(arg)
+					}
+				}
+				server(n, scope.Resolve(n.Args[0]), scope.Resolve(n.Args[1]), grpcPort, contextPath)
+			case "WithEndpoint":
+				if len(n.Args) == 1 {
+					endpoint(n, scope.Resolve(n.Args[0]))
+				}
+			}
+		}
+	})
+	return calls
+}
@@ -3,19 +3,21 @@ package parser
 import (
 	"go/ast"
 	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
 	"strings"
 )
 
-// finds the invocation of the wrappers for register instance and resolves the arguments for serviceName, Ip, and Port
+// finds the invocation of the wrappers for register instance and resolves the arguments for serviceName, Ip, Port and GroupName
 func FindRegisterInstanceWrapperInvocations(node ast.Node, wrapper t.RegisterInstanceWrapper, service string) ([]string, []t.ServiceInfo) {
-	// FindRegisterInstanceWrapperInvocations finds the invocation of the wrappers for register instance and resolves the arguments for serviceName, Ip, and Port.
+	// FindRegisterInstanceWrapperInvocations finds the invocation of the wrappers for register instance and resolves the arguments for serviceName, Ip, Port and GroupName.
 	//
 	// node: The root node of the AST.
 	// wrapper: The RegisterInstanceWrapper struct that contains the wrapper function and the arguments to resolve.
 	// service: The name of the service.
 	//
 	// Returns:
-	// A slice of service names and a slice of ServiceInfo structs. Each ServiceInfo struct contains the application name, IP, and port.
+	// A slice of service names and a slice of ServiceInfo structs, in the same order. Each ServiceInfo struct contains the application name, IP, port and group.
+	// A service name drawn from a finite set, such as a loop over a slice literal, yields a name and a ServiceInfo per value.
 	//
 
 	handleBasicLit := func(arg ast.Expr) string {
@@ -24,13 +26,17 @@ func FindRegisterInstanceWrapperInvocations(node ast.Node, wrapper t.RegisterIns
 		// arg: The *ast.BasicLit node to process.
 		//
 		// Returns:
-		// The value of the *ast.BasicLit node as a string if the node is of type *ast.BasicLit. If the node is not of type *ast.BasicLit, it returns "nil".
+		// The value of the *ast.BasicLit node as a string if the node is of type *ast.BasicLit. If the node is a variable, it returns "nil".
+		// Other expressions, such as concatenations, are resolved into templates, see util.ResolveTemplate.
 
 		// Check if the argument is a basic literal
-		if lit, ok := arg.(*ast.BasicLit); ok {
-			return lit.Value
+		switch arg := arg.(type) {
+		case *ast.BasicLit:
+			return arg.Value
+		case *ast.Ident:
+			return "nil"
 		}
-		return "nil"
+		return util.ResolveTemplate(arg, nil)
 	}
 
 	resolveArgument := func(arg interface{}, args []string) string {
@@ -50,6 +56,9 @@ func FindRegisterInstanceWrapperInvocations(node ast.Node, wrapper t.RegisterIns
 			return t
 		case t.WrapperParams:
 			// If the argument is a t.WrapperParams, return the argument at the position specified in the t.WrapperParams struct from the args slice
+			if t.Position >= len(args) {
+				return "nil"
+			}
 			return strings.ReplaceAll(args[t.Position], "\"", "")
 		}
 		// If the argument is of neither type, return an empty string
@@ -59,6 +68,10 @@ func FindRegisterInstanceWrapperInvocations(node ast.Node, wrapper t.RegisterIns
 	wrapperName := wrapper.Wrapper
 	serviceNames := []string{}
 	serviceInfos := []t.ServiceInfo{}
+
+	// The function the calls are made in, whose variables name the services
+	var fn *ast.FuncDecl
+
 	// Inspect the AST to find the invocation of the wrapper function
 	ast.Inspect(node, func(n ast.Node) bool {
 		if n == nil {
@@ -66,23 +79,53 @@ func FindRegisterInstanceWrapperInvocations(node ast.Node, wrapper t.RegisterIns
 		}
 
 		switch n := n.(type) {
+		case *ast.FuncDecl:
+			fn = n
 		// Check if the node is a *ast.CallExpr
 		case *ast.CallExpr:
-			// Check if the function is the wrapper function
-			if fun, ok := n.Fun.(*ast.Ident); ok {
+			// Check if the function is the wrapper function, called directly or through its package or receiver
+			fun, ok := n.Fun.(*ast.Ident)
+			if sel, isSelector := n.Fun.(*ast.SelectorExpr); isSelector {
+				if _, isIdent := sel.X.(*ast.Ident); isIdent {
+					fun, ok = sel.Sel, true
+				}
+			}
+			if ok {
 				var args []string
 				if fun.Name == wrapperName {
-					// If the function is the wrapper function, resolve the arguments for serviceName, Ip, and Port
+					// If the function is the wrapper function, resolve the arguments for serviceName, Ip, Port and GroupName
 					for _, arg := range n.Args {
 						args = append(args, handleBasicLit(arg))
 					}
 
-					serviceName := resolveArgument(wrapper.ServiceName, args)
+					// A wrapper called with, or itself registering, each value of a finite set yields one name per value
+					names := []string{resolveArgument(wrapper.ServiceName, args)}
+					switch name := wrapper.ServiceName.(type) {
+					case []string:
+						names = name
+					case t.WrapperParams:
+						if name.Position < len(n.Args) {
+							if values := EnumerateValues(node, fn, n.Args[name.Position]); values != nil {
+								names = values
+							}
+						}
+					}
 					ip := resolveArgument(wrapper.IP, args)
 					port := resolveArgument(wrapper.Port, args)
+					// A computed port is resolved into the range of ports it can take rather than a template
+					if param, ok := wrapper.Port.(t.WrapperParams); ok && param.Position < len(n.Args) {
+						switch n.Args[param.Position].(type) {
+						case *ast.BasicLit, *ast.Ident:
+						default:
+							port = util.ResolvePortRange(n.Args[param.Position], nil, nil)
+						}
+					}
+					group := resolveArgument(wrapper.GroupName, args)
 
-					serviceInfos = append(serviceInfos, t.ServiceInfo{Application: service, IP: ip, Port: port})
-					serviceNames = append(serviceNames, serviceName)
+					for _, serviceName := range names {
+						serviceInfos = append(serviceInfos, t.ServiceInfo{Application: service, IP: ip, Port: port, Group: group})
+						serviceNames = append(serviceNames, serviceName)
+					}
 				}
 			}
 		}
@@ -3,21 +3,28 @@ package parser
 import (
 
 	"go/ast"
+	"go/token"
 	t "static_analyser/pkg/types"
 	"static_analyser/pkg/util"
 	"strings"
 )
 
-func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
-	// FindRegisterInstanceWrappers traverses the AST (Abstract Syntax Tree) to find all instances of RegisterInstance calls.
+func FindRegisterInstanceWrappers(node ast.Node, values map[string]string) []t.RegisterInstanceWrapper {
+	// FindRegisterInstanceWrappers traverses the AST (Abstract Syntax Tree) to find all instances of RegisterInstance calls,
+	// and of RegisterInstanceParam literals assigned to variables, which are registered through helpers such as naming.RegisterServiceInstance.
 	//
 	// node: The root node of the AST.
+	// values: The configuration values the service reads, used to resolve computed fields. May be nil.
 	//
 	// Returns:
 	// A slice of RegisterInstanceWrapper structs. Each struct represents a RegisterInstance call found in the AST.
 	// The RegisterInstanceWrapper struct contains the name of the wrapper function and the parameters passed to the RegisterInstance call.
 
+	// The function the registrations are made in, whose variables may name the services
+	var fn *ast.FuncDecl
+
 	handleFuncDecl := func(n *ast.FuncDecl) (string, []string) {
+		fn = n
 		wrapper := n.Name.Name
 		paramNames := []string{}
 
@@ -30,11 +37,54 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 		return wrapper, paramNames
 	}
 
+	handleExpr := func(v ast.Expr, keyName string, instance t.RegisterInstanceWrapper) t.RegisterInstanceWrapper {
+		// handleExpr processes a value that is computed, such as a concatenation or a sum, and updates the corresponding field in the given RegisterInstanceWrapper struct.
+		//
+		// v: The expression to process.
+		// keyName: The name of the field to update in the RegisterInstanceWrapper struct. It should be one of "Ip", "Port", "ServiceName" or "GroupName".
+		// instance: The RegisterInstanceWrapper struct to update.
+		//
+		// Returns:
+		// The updated RegisterInstanceWrapper struct. Ports are resolved into the range they can take, see util.ResolvePortRange,
+		// and the other fields into templates whose placeholders stand for the parts computed at runtime.
+
+		template := util.SubstituteConfigValues(util.ResolveTemplate(v, nil), values)
+		switch keyName {
+		case "Ip":
+			instance.IP = template
+		case "Port":
+			instance.Port = util.ResolvePortRange(v, nil, values)
+		case "ServiceName":
+			instance.ServiceName = template
+		case "GroupName":
+			instance.GroupName = template
+		}
+		return instance
+	}
+
+	enumerated := func(expr ast.Expr) interface{} {
+		// enumerated enumerates the values of an expression in the current function, see EnumerateValues.
+		//
+		// expr: The expression.
+		//
+		// Returns:
+		// The value if there is one, a slice of the values if there are several, and nil if they are not known statically.
+
+		values := EnumerateValues(node, fn, expr)
+		switch len(values) {
+		case 0:
+			return nil
+		case 1:
+			return values[0]
+		}
+		return values
+	}
+
 	handleIdent := func(v *ast.Ident, keyName string, paramNames []string, instance t.RegisterInstanceWrapper, node ast.Node, wrapper string) t.RegisterInstanceWrapper {
 		// handleIdent processes an *ast.Ident node and updates the corresponding field in the given RegisterInstanceWrapper struct.
 		//
 		// v: The *ast.Ident node to process.
-		// keyName: The name of the field to update in the RegisterInstanceWrapper struct. It should be one of "Ip", "Port", or "ServiceName".
+		// keyName: The name of the field to update in the RegisterInstanceWrapper struct. It should be one of "Ip", "Port", "ServiceName" or "GroupName".
 		// paramNames: A slice of parameter names from the wrapper function.
 		// instance: The RegisterInstanceWrapper struct to update.
 		// node: The root node of the AST.
@@ -56,6 +106,9 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 				case "ServiceName":
 					instance.ServiceName = t.WrapperParams{Position: i}
 
+				case "GroupName":
+					instance.GroupName = t.WrapperParams{Position: i}
+
 				}
 			}
 		}
@@ -68,6 +121,20 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 		if instance.ServiceName == nil && keyName == "ServiceName" {
 			instance.ServiceName = util.FindConstValue(node, strings.TrimSpace(v.Name), wrapper)
 		}
+		if instance.GroupName == nil && keyName == "GroupName" {
+			instance.GroupName = util.FindConstValue(node, strings.TrimSpace(v.Name), wrapper)
+		}
+		// A variable drawn from a finite set, such as a loop over a slice literal, names each of its values
+		if instance.ServiceName == "" && keyName == "ServiceName" {
+			if enumerated := enumerated(v); enumerated != nil {
+				instance.ServiceName = enumerated
+			}
+		}
+		// A variable that is neither a parameter nor a constant is computed at runtime
+		fields := map[string]interface{}{"Ip": instance.IP, "Port": instance.Port, "ServiceName": instance.ServiceName, "GroupName": instance.GroupName}
+		if fields[keyName] == "" {
+			instance = handleExpr(v, keyName, instance)
+		}
 
 		return instance
 	}
@@ -76,7 +143,7 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 		// handleBasicLit processes an *ast.BasicLit node and updates the corresponding field in the given RegisterInstanceWrapper struct.
 		//
 		// v: The *ast.BasicLit node to process.
-		// keyName: The name of the field to update in the RegisterInstanceWrapper struct. It should be one of "Ip", "Port", or "ServiceName".
+		// keyName: The name of the field to update in the RegisterInstanceWrapper struct. It should be one of "Ip", "Port", "ServiceName" or "GroupName".
 		// instance: The RegisterInstanceWrapper struct to update.
 		//
 		// Returns:
@@ -89,10 +156,62 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 			instance.Port = strings.ReplaceAll(strings.TrimSpace(v.Value), "\"", "")
 		case "ServiceName":
 			instance.ServiceName = strings.ReplaceAll(strings.TrimSpace(v.Value), "\"", "")
+		case "GroupName":
+			instance.GroupName = strings.ReplaceAll(strings.TrimSpace(v.Value), "\"", "")
 		}
 		return instance
 	}
 
+	handleCompositeLit := func(lit *ast.CompositeLit, node ast.Node, wrapper string, paramNames []string, instances []t.RegisterInstanceWrapper) []t.RegisterInstanceWrapper {
+		// handleCompositeLit processes an *ast.CompositeLit node holding the parameters of a registration.
+		//
+		// lit: The *ast.CompositeLit node to process.
+		// node: The root node of the AST.
+		// wrapper: The name of the wrapper function.
+		// paramNames: A slice of parameter names from the wrapper function.
+		// instances: A slice of RegisterInstanceWrapper structs found so far.
+		//
+		// Returns:
+		// A slice of RegisterInstanceWrapper structs. If the *ast.CompositeLit node is of type RegisterInstanceParam, a new RegisterInstanceWrapper struct is created and added to the slice.
+
+		// Check if the CompositeLit is of type RegisterInstanceParam
+		sel, ok := lit.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "RegisterInstanceParam" {
+			return instances
+		}
+		instance := t.RegisterInstanceWrapper{}
+		instance.Wrapper = wrapper
+		// Iterate over the elements of the CompositeLit
+		for _, elt := range lit.Elts {
+			// Check if the element is a KeyValueExpr
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				// Check if the key is an Identifier
+				if key, ok := kv.Key.(*ast.Ident); ok {
+					switch key.Name {
+					// Check if the key is Ip, Port, ServiceName or GroupName
+					case "Ip", "Port", "ServiceName", "GroupName":
+						switch v := kv.Value.(type) {
+						// Check if the value is an Ident or BasicLit
+						case *ast.Ident:
+							instance = handleIdent(v, key.Name, paramNames, instance, node, wrapper)
+						case *ast.BasicLit:
+							instance = handleBasicLit(v, key.Name, instance)
+						case *ast.IndexExpr:
+							if value := enumerated(v); value != nil && key.Name == "ServiceName" {
+								instance.ServiceName = value
+							} else {
+								instance = handleExpr(v, key.Name, instance)
+							}
+						default:
+							instance = handleExpr(v, key.Name, instance)
+						}
+					}
+				}
+			}
+		}
+		return append(instances, instance)
+	}
+
 	handleCallExpr := func(n *ast.CallExpr, node ast.Node, wrapper string, paramNames []string, instances []t.RegisterInstanceWrapper) []t.RegisterInstanceWrapper {
 		// handleCallExpr processes an *ast.CallExpr node to find instances of RegisterInstance calls.
 		//
@@ -109,41 +228,39 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 		// Check if the function is RegisterInstance
 		if selExpr, ok := n.Fun.(*ast.SelectorExpr); ok && selExpr.Sel.Name == "RegisterInstance" {
 			for _, arg := range n.Args {
-				switch arg := arg.(type) {
 				// Check if the argument is a CompositeLit
-				case *ast.CompositeLit:
-					// Check if the CompositeLit is of type RegisterInstanceParam
-					if sel, ok := arg.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "RegisterInstanceParam" {
-						instance := t.RegisterInstanceWrapper{}
-						instance.Wrapper = wrapper
-						// Iterate over the elements of the CompositeLit
-						for _, elt := range arg.Elts {
-							// Check if the element is a KeyValueExpr
-							if kv, ok := elt.(*ast.KeyValueExpr); ok {
-								// Check if the key is an Identifier
-								if key, ok := kv.Key.(*ast.Ident); ok {
-									switch key.Name {
-									// Check if the key is Ip, Port or ServiceName
-									case "Ip", "Port", "ServiceName":
-										switch v := kv.Value.(type) {
-										// Check if the value is an Ident or BasicLit
-										case *ast.Ident:
-											instance = handleIdent(v, key.Name, paramNames, instance, node, wrapper)
-										case *ast.BasicLit:
-											instance = handleBasicLit(v, key.Name, instance)
-										}
-									}
-								}
-							}
-						}
-						instances = append(instances, instance)
-					}
+				if lit, ok := arg.(*ast.CompositeLit); ok {
+					instances = handleCompositeLit(lit, node, wrapper, paramNames, instances)
 				}
 			}
 		}
 		return instances
 	}
 
+	handleAssignStmt := func(n *ast.AssignStmt, node ast.Node, wrapper string, paramNames []string, instances []t.RegisterInstanceWrapper) []t.RegisterInstanceWrapper {
+		// handleAssignStmt processes an *ast.AssignStmt node to find the parameters of a registration stored in a variable,
+		// which is then passed to RegisterInstance or to a helper outside the analysed source.
+		//
+		// n: The *ast.AssignStmt node to process.
+		// node: The root node of the AST.
+		// wrapper: The name of the wrapper function.
+		// paramNames: A slice of parameter names from the wrapper function.
+		// instances: A slice of RegisterInstanceWrapper structs found so far.
+		//
+		// Returns:
+		// A slice of RegisterInstanceWrapper structs, with one added for each RegisterInstanceParam literal or pointer to one assigned.
+
+		for _, rhs := range n.Rhs {
+			if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				rhs = unary.X
+			}
+			if lit, ok := rhs.(*ast.CompositeLit); ok {
+				instances = handleCompositeLit(lit, node, wrapper, paramNames, instances)
+			}
+		}
+		return instances
+	}
+
 	var instances []t.RegisterInstanceWrapper
 	var paramNames = []string{}
 	var wrapper string
@@ -160,6 +277,9 @@ func FindRegisterInstanceWrappers(node ast.Node) []t.RegisterInstanceWrapper {
 
 		case *ast.CallExpr:
 			instances = handleCallExpr(n, node, wrapper, paramNames, instances)
+
+		case *ast.AssignStmt:
+			instances = handleAssignStmt(n, node, wrapper, paramNames, instances)
 		}
 
 		return true
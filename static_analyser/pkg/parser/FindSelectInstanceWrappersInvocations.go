@@ -3,18 +3,20 @@ package parser
 import (
 	"go/ast"
 	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
 	"strings"
 )
 
-func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDiscoveryWrapper, service string) []string {
-	// FindSelectInstanceWrappersInvocations is a function that finds the invocation of the wrappers for service discovery and resolves the arguments for serviceName.
+func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDiscoveryWrapper, service string) ([]string, []string, []string) {
+	// FindSelectInstanceWrappersInvocations is a function that finds the invocation of the wrappers for service discovery and resolves the arguments for serviceName and GroupName.
 	//
 	// node: The root node of the AST.
 	// wrapper: The ServiceDiscoveryWrapper struct that contains the wrapper function and the arguments to resolve.
 	// service: The name of the service.
 	//
 	// Returns:
-	// A slice of service names.
+	// A slice of service names, a slice of the source locations of the invocations and a slice of the groups of the
+	// services, empty for the default group, in the same order.
 
 	handleBasicLit := func(arg ast.Expr) string {
 		// handleBasicLit is a closure that processes an *ast.BasicLit node and returns its value as a string.
@@ -22,13 +24,17 @@ func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDisco
 		// arg: The *ast.BasicLit node to process.
 		//
 		// Returns:
-		// The value of the *ast.BasicLit node as a string if the node is of type *ast.BasicLit. If the node is not of type *ast.BasicLit, it returns "nil".
+		// The value of the *ast.BasicLit node as a string if the node is of type *ast.BasicLit. If the node is a variable, it returns "nil".
+		// Other expressions, such as concatenations, are resolved into templates, see util.ResolveTemplate.
 
 		// Check if the argument is a basic literal
-		if lit, ok := arg.(*ast.BasicLit); ok {
-			return lit.Value
+		switch arg := arg.(type) {
+		case *ast.BasicLit:
+			return arg.Value
+		case *ast.Ident:
+			return "nil"
 		}
-		return "nil"
+		return util.ResolveTemplate(arg, nil)
 	}
 
 	resolveArgument := func(arg interface{}, args []string) string {
@@ -48,6 +54,9 @@ func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDisco
 			return t
 		case t.WrapperParams:
 			// If the argument is a t.WrapperParams, return the argument at the position specified in the t.WrapperParams struct from the args slice
+			if t.Position >= len(args) {
+				return "nil"
+			}
 			return strings.ReplaceAll(args[t.Position], "\"", "")
 		}
 		// If the argument is of neither type, return an empty string
@@ -56,6 +65,11 @@ func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDisco
 
 	wrapperName := wrapper.Wrapper
 	serviceNames := []string{}
+	locations := []string{}
+	groups := []string{}
+
+	// The function the calls are made in, whose variables name the services
+	var fn *ast.FuncDecl
 
 	// Inspect the AST for function calls
 	ast.Inspect(node, func(n ast.Node) bool {
@@ -64,6 +78,8 @@ func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDisco
 		}
 
 		switch n := n.(type) {
+		case *ast.FuncDecl:
+			fn = n
 		// Check if the node is a *ast.CallExpr
 		case *ast.CallExpr:
 			// Check if the function is the wrapper function
@@ -75,14 +91,30 @@ func FindSelectInstanceWrappersInvocations(node ast.Node, wrapper t.ServiceDisco
 						args = append(args, handleBasicLit(arg))
 					}
 
-					serviceName := resolveArgument(wrapper.ServiceName, args)
+					// A wrapper called with, or itself discovering, each value of a finite set yields one name per value
+					names := []string{resolveArgument(wrapper.ServiceName, args)}
+					switch name := wrapper.ServiceName.(type) {
+					case []string:
+						names = name
+					case t.WrapperParams:
+						if name.Position < len(n.Args) {
+							if values := EnumerateValues(node, fn, n.Args[name.Position]); values != nil {
+								names = values
+							}
+						}
+					}
 
-					serviceNames = append(serviceNames, serviceName)
+					group := resolveArgument(wrapper.GroupName, args)
+					for _, serviceName := range names {
+						serviceNames = append(serviceNames, serviceName)
+						locations = append(locations, Location(n.Pos()))
+						groups = append(groups, group)
+					}
 				}
 			}
 		}
 		return true
 	})
 
-	return serviceNames
+	return serviceNames, locations, groups
 }
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"go/parser"
+	"reflect"
+	"testing"
+)
+
+// wrapperSource calls a discovery wrapper with values drawn from finite sets
+const wrapperSource = `package main
+
+import "github.com/nacos-group/nacos-sdk-go/vo"
+
+func discover(client naming_client.INamingClient, name string) {
+	client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: name})
+}
+
+func fanOut(client naming_client.INamingClient, region string) {
+	for _, name := range []string{"orders", "payments"} {
+		discover(client, name)
+	}
+	ledgers := map[string]string{"eu": "ledger", "us": "ledger-us"}
+	discover(client, ledgers[region])
+	discover(client, "audit")
+	discover(client, region)
+}
+`
+
+func TestFindSelectInstanceWrappersInvocationsEnumerated(test *testing.T) {
+	// TestFindSelectInstanceWrappersInvocationsEnumerated checks that a wrapper called with each value of a finite set
+	// discovers each value, and that variables not known statically are not resolved.
+	//
+	// test: The test.
+
+	file, err := parser.ParseFile(FileSet, "wrapper.go", wrapperSource, 0)
+	if err != nil {
+		test.Fatal(err)
+	}
+	wrappers := FindServiceDiscoveryWrappers(file)
+	if len(wrappers) != 1 {
+		test.Fatalf("got wrappers %+v, want discover only", wrappers)
+	}
+	names, _, _ := FindSelectInstanceWrappersInvocations(file, wrappers[0], "aggregator")
+	want := []string{"orders", "payments", "ledger", "ledger-us", "audit", "nil"}
+	if !reflect.DeepEqual(names, want) {
+		test.Errorf("got %q, want %q", names, want)
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"go/ast"
+	"go/token"
 	t "static_analyser/pkg/types"
 	"static_analyser/pkg/util"
 	"strings"
@@ -17,10 +18,11 @@ func FindServiceDiscoveryWrappers(node ast.Node) []t.ServiceDiscoveryWrapper {
 	// The ServiceDiscoveryWrapper struct contains the name of the wrapper function and the parameters passed to the service discovery call.
 
 	select_sdk := []string{"GetService", "SelectAllInstances", "SelectOneHealthyInstance", "SelectInstances", "Subscribe"}
-	select_params := []string{"GetServiceParam", "SelectAllInstancesParam", "SelectOneHealthyInstanceParam", "SelectInstancesParam", "SubscribeParam"}
+	select_params := []string{"GetServiceParam", "SelectAllInstancesParam", "SelectOneHealthyInstanceParam", "SelectOneHealthInstanceParam", "SelectInstancesParam", "SubscribeParam"}
 
 	var paramNames = []string{}
 	var wrapper string
+	var fn *ast.FuncDecl
 	var instances []t.ServiceDiscoveryWrapper
 
 	handleFuncDecl := func(n *ast.FuncDecl) {
@@ -28,9 +30,12 @@ func FindServiceDiscoveryWrappers(node ast.Node) []t.ServiceDiscoveryWrapper {
 		//
 		// n: The function declaration node.
 		//
-		// This closure sets the wrapper variable to the name of the function and adds the names of the parameters to the paramNames slice.
+		// This closure sets the wrapper variable to the name of the function and sets the paramNames slice to the names of its parameters,
+		// so that every wrapper of a file maps its own parameters.
 
 		wrapper = n.Name.Name
+		fn = n
+		paramNames = []string{}
 		for _, param := range n.Type.Params.List {
 			for _, name := range param.Names {
 				paramNames = append(paramNames, name.Name)
@@ -38,6 +43,24 @@ func FindServiceDiscoveryWrappers(node ast.Node) []t.ServiceDiscoveryWrapper {
 		}
 	}
 
+	enumerated := func(expr ast.Expr) interface{} {
+		// enumerated enumerates the values of an expression in the current function, see EnumerateValues.
+		//
+		// expr: The expression.
+		//
+		// Returns:
+		// The value if there is one, a slice of the values if there are several, and nil if they are not known statically.
+
+		values := EnumerateValues(node, fn, expr)
+		switch len(values) {
+		case 0:
+			return nil
+		case 1:
+			return values[0]
+		}
+		return values
+	}
+
 	handleCallExpr := func(n *ast.CallExpr) {
 		// handleCallExpr is a closure that handles call expressions.
 		//
@@ -52,6 +75,10 @@ func FindServiceDiscoveryWrappers(node ast.Node) []t.ServiceDiscoveryWrapper {
 		}
 
 		for _, arg := range n.Args {
+			// Subscribe takes a pointer to its parameters
+			if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				arg = unary.X
+			}
 			arg, ok := arg.(*ast.CompositeLit)
 			if !ok {
 				continue
@@ -62,7 +89,8 @@ func FindServiceDiscoveryWrappers(node ast.Node) []t.ServiceDiscoveryWrapper {
 				continue
 			}
 
-			instance := t.ServiceDiscoveryWrapper{Wrapper: wrapper}
+			instance := t.ServiceDiscoveryWrapper{Wrapper: wrapper, Method: selExpr.Sel.Name}
+			named := false
 			for _, elt := range arg.Elts {
 				kv, ok := elt.(*ast.KeyValueExpr)
 				if !ok {
@@ -70,23 +98,41 @@ func FindServiceDiscoveryWrappers(node ast.Node) []t.ServiceDiscoveryWrapper {
 				}
 
 				key, ok := kv.Key.(*ast.Ident)
-				if !ok || key.Name != "ServiceName" {
+				if !ok || (key.Name != "ServiceName" && key.Name != "GroupName") {
 					continue
 				}
 
+				var value interface{}
 				switch v := kv.Value.(type) {
 				case *ast.Ident:
 					for i, paramName := range paramNames {
 						if paramName == strings.TrimSpace(v.Name) {
-							instance.ServiceName = t.WrapperParams{Position: i}
-						}
-						if instance.ServiceName == nil {
-							instance.ServiceName = util.FindConstValue(node, strings.TrimSpace(v.Name), wrapper)
+							value = t.WrapperParams{Position: i}
 						}
 					}
+					// A variable drawn from a finite set, such as a loop over a slice literal, names each of its values
+					if value == nil && key.Name == "ServiceName" {
+						value = enumerated(v)
+					}
+					if value == nil && len(paramNames) > 0 {
+						value = util.FindConstValue(node, strings.TrimSpace(v.Name), wrapper)
+					}
+				case *ast.IndexExpr:
+					if key.Name == "ServiceName" {
+						value = enumerated(v)
+					}
 				case *ast.BasicLit:
-					instance.ServiceName = strings.ReplaceAll(strings.TrimSpace(v.Value), "\"", "")
+					value = strings.ReplaceAll(strings.TrimSpace(v.Value), "\"", "")
+				}
+				if key.Name == "GroupName" {
+					instance.GroupName = value
+				} else {
+					instance.ServiceName = value
+					named = true
 				}
+			}
+			// Literals without a service name, such as those filled in later, are not wrappers
+			if named {
 				instances = append(instances, instance)
 			}
 		}
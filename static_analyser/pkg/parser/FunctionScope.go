@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"go/ast"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+// discoveryFunctions are the Nacos SDK functions that return instances of a discovered service.
+var discoveryFunctions = []string{"GetService", "SelectAllInstances", "SelectOneHealthyInstance", "SelectInstances", "Subscribe"}
+
+// functionScope tracks, within one function, the expressions last assigned to local variables
+// and which variables hold values derived from a Nacos discovery call.
+type functionScope struct {
+	env        map[string]ast.Expr
+	discovered map[string]string
+	wrappers   []t.ServiceDiscoveryWrapper
+	values     map[string]string
+}
+
+func inspectFunctions(node ast.Node, wrappers []t.ServiceDiscoveryWrapper, values map[string]string, visit func(n ast.Node, scope *functionScope)) {
+	// inspectFunctions walks every function of the AST in source order, giving each function a fresh functionScope.
+	// Assignments are recorded in the scope before visit sees them, so visit can resolve variables assigned earlier.
+	//
+	// node: The root node of the AST.
+	// wrappers: The service discovery wrappers whose results count as discovered values.
+	// values: The configuration values the service reads from the Nacos config center, see util.ParseConfigValues. May be nil.
+	// visit: The function called for every node inside a function.
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return true
+		}
+		scope := &functionScope{env: make(map[string]ast.Expr), discovered: make(map[string]string), wrappers: wrappers, values: values}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if n == nil {
+				return false
+			}
+			scope.record(n)
+			visit(n, scope)
+			return true
+		})
+		return false
+	})
+}
+
+func (s *functionScope) record(n ast.Node) {
+	// record updates the scope with the variables assigned by a node.
+	//
+	// n: The node to record. Assignments, variable declarations and range statements are recorded; other nodes are ignored.
+
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			ident, ok := l.(*ast.Ident)
+			if !ok || ident.Name == "_" {
+				continue
+			}
+			var value ast.Expr
+			if len(lhs) == len(rhs) {
+				value = rhs[i]
+				s.env[ident.Name] = value
+			} else if len(rhs) == 1 {
+				// Every result of a multi-value call derives from the call
+				value = rhs[0]
+				delete(s.env, ident.Name)
+			}
+			if value == nil {
+				continue
+			}
+			if service, ok := s.DiscoveredService(value); ok {
+				s.discovered[ident.Name] = service
+			} else {
+				delete(s.discovered, ident.Name)
+			}
+		}
+	}
+
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		assign(n.Lhs, n.Rhs)
+	case *ast.ValueSpec:
+		lhs := []ast.Expr{}
+		for _, name := range n.Names {
+			lhs = append(lhs, name)
+		}
+		assign(lhs, n.Values)
+	case *ast.RangeStmt:
+		if service, ok := s.DiscoveredService(n.X); ok {
+			for _, v := range []ast.Expr{n.Key, n.Value} {
+				if ident, ok := v.(*ast.Ident); ok {
+					s.discovered[ident.Name] = service
+				}
+			}
+		}
+	}
+}
+
+func (s *functionScope) Resolve(expr ast.Expr) string {
+	// Resolve resolves an expression into a template using the variables of the scope, see util.ResolveTemplate.
+	// Placeholders that name a configuration value read by the service are replaced with that value.
+
+	return util.SubstituteConfigValues(util.ResolveTemplate(expr, s.env), s.values)
+}
+
+func (s *functionScope) DiscoveredService(expr ast.Expr) (string, bool) {
+	// DiscoveredService finds out whether an expression derives from a Nacos discovery call.
+	//
+	// expr: The expression to check.
+	//
+	// Returns:
+	// The name of the discovered service, which is a template if it could not be resolved, and true,
+	// if the expression uses a discovered variable, calls a discovery function or calls a discovery wrapper.
+	// An empty string and false otherwise.
+
+	service, found := "", false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.Ident:
+			service, found = s.discovered[n.Name]
+		case *ast.CallExpr:
+			service, found = s.discoveryCall(n)
+		}
+		return !found
+	})
+	return service, found
+}
+
+func (s *functionScope) discoveryCall(call *ast.CallExpr) (string, bool) {
+	// discoveryCall finds out whether a call is a Nacos discovery call or a call to a discovery wrapper.
+	//
+	// call: The call to check.
+	//
+	// Returns:
+	// The name of the discovered service and true if it is; an empty string and false otherwise.
+
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if !util.Contains(discoveryFunctions, fun.Sel.Name) {
+			return "", false
+		}
+		for _, arg := range call.Args {
+			if unary, ok := arg.(*ast.UnaryExpr); ok {
+				arg = unary.X
+			}
+			lit, ok := arg.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			for _, elt := range lit.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "ServiceName" {
+						return s.Resolve(kv.Value), true
+					}
+				}
+			}
+		}
+		return "", true
+
+	case *ast.Ident:
+		for _, wrapper := range s.wrappers {
+			if wrapper.Wrapper != fun.Name {
+				continue
+			}
+			switch name := wrapper.ServiceName.(type) {
+			case string:
+				return name, true
+			case t.WrapperParams:
+				if name.Position < len(call.Args) {
+					return strings.TrimSpace(s.Resolve(call.Args[name.Position])), true
+				}
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
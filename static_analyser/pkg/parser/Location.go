@@ -0,0 +1,21 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+)
+
+func Location(pos token.Pos) string {
+	// Location converts a position of a node parsed by ParseFile into a "file:line" string.
+	//
+	// pos: The position of the node.
+	//
+	// Returns:
+	// The file and line of the position, or an empty string if the position is unknown.
+
+	if !pos.IsValid() {
+		return ""
+	}
+	position := FileSet.Position(pos)
+	return fmt.Sprintf("%s:%d", position.Filename, position.Line)
+}
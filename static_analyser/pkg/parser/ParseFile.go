@@ -7,21 +7,25 @@ import (
 	"go/token"
 )
 
+// FileSet records the positions of every file parsed by ParseFile so that AST nodes can be mapped back to source locations.
+var FileSet = token.NewFileSet()
+
 func ParseFile(filePath string) (*ast.File, error) {
 	// ParseFile reads a Go source file and parses it into an abstract syntax tree (AST).
 	//
 	// filePath: The path to the Go source file.
 	//
 	// Returns:
-	// A pointer to an ast.File struct representing the parsed Go source file.
-	// An error if there was a problem reading the file or parsing the source code.
+	// A pointer to an ast.File struct representing the parsed Go source file. If the source has syntax errors, it holds
+	// the declarations that could be parsed, and is nil only if the file could not be read.
+	// An error if there was a problem reading the file or parsing the source code. Syntax errors wrap a scanner.ErrorList
+	// with the position of each error.
 
 	// Convert file to an AST
-	fset := token.NewFileSet()
-	fileAst, err := parser.ParseFile(fset, filePath, nil, parser.AllErrors)
+	fileAst, err := parser.ParseFile(FileSet, filePath, nil, parser.AllErrors)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse file %s: %v", filePath, err)
+		return fileAst, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 	}
 
 	return fileAst, nil
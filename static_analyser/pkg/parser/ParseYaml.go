@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	t "static_analyser/pkg/types"
@@ -8,6 +9,9 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// ErrMissingFields is returned by ParseYaml for YAML files without apiVersion and kind, which are not Kubernetes resources.
+var ErrMissingFields = errors.New("missing required fields")
+
 func ParseYaml(filePath string) (*t.Yaml2Go, string, error) {
 	// ParseYaml reads a YAML file and unmarshals it into a Yaml2Go struct.
 	//
@@ -34,7 +38,7 @@ func ParseYaml(filePath string) (*t.Yaml2Go, string, error) {
 
 	// Check if the required fields are present
 	if conf.ApiVersion == "" && conf.Kind == ""  {
-		return nil, "", fmt.Errorf("missing required fields")
+		return nil, "", ErrMissingFields
 	}
 
 	return conf, conf.Metadata.Name, nil
@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"sort"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func AuditPolicies(parsedYamls map[string]*t.Yaml2Go, namespaces []t.KubernetesNamespace, policies []t.NetworkPolicy, g t.ServiceGraph) t.AuditReport {
+	// AuditPolicies audits existing NetworkPolicies, such as those written by hand, against what the code needs.
+	//
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	// namespaces: The Namespaces declared in the sources, whose labels namespace selectors match. May be nil.
+	// policies: The existing NetworkPolicies.
+	// g: The service graph.
+	//
+	// Returns:
+	// The edges the policies block or cannot be evaluated for, the over-permissive rules, the workloads some direction of
+	// which no policy selects, and a score per namespace. The score of a namespace is the percentage of its checks that
+	// pass: one per direction of each of its workloads, passing if a policy selects it; one per edge from or to one of
+	// its workloads, passing unless blocked; and one per rule of its policies, passing unless over-permissive.
+
+	simulation := EvaluatePolicies(parsedYamls, namespaces, policies, g)
+	report := t.AuditReport{
+		Missing:    []t.PolicyVerdict{},
+		Unknown:    []t.PolicyVerdict{},
+		Permissive: simulation.Permissive,
+		Uncovered:  []t.UncoveredWorkload{},
+		Namespaces: []t.NamespaceScore{},
+	}
+
+	scores := make(map[string]*t.NamespaceScore)
+	check := func(namespace string, passed bool) {
+		score, ok := scores[namespace]
+		if !ok {
+			score = &t.NamespaceScore{Namespace: namespace}
+			scores[namespace] = score
+		}
+		score.Checks++
+		if passed {
+			score.Passed++
+		}
+	}
+
+	workloadNamespace := make(map[string]string)
+	for _, node := range g.Nodes {
+		if node.External {
+			continue
+		}
+		namespace, _ := WorkloadSelector(node.ID, parsedYamls)
+		labels := WorkloadLabels(node.ID, parsedYamls)
+		workloadNamespace[node.ID] = namespace
+
+		uncovered := t.UncoveredWorkload{Workload: node.ID, Namespace: namespace, Directions: []string{}}
+		for _, direction := range []string{"Ingress", "Egress"} {
+			covered := false
+			for _, policy := range policies {
+				if policyNamespace(policy) == namespace && restricts(policy, direction) && MatchSelector(policy.Spec.PodSelector, labels) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				uncovered.Directions = append(uncovered.Directions, strings.ToLower(direction))
+			}
+			check(namespace, covered)
+		}
+		if len(uncovered.Directions) > 0 {
+			report.Uncovered = append(report.Uncovered, uncovered)
+		}
+	}
+
+	for _, verdict := range simulation.Verdicts {
+		switch verdict.Verdict {
+		case "blocked":
+			report.Missing = append(report.Missing, verdict)
+		case "unknown":
+			report.Unknown = append(report.Unknown, verdict)
+		}
+		// An edge counts for the namespaces of both ends, once if they are the same
+		namespaces := map[string]bool{}
+		for _, id := range []string{verdict.Edge.From, verdict.Edge.To} {
+			if namespace, ok := workloadNamespace[id]; ok {
+				namespaces[namespace] = true
+			}
+		}
+		for namespace := range namespaces {
+			check(namespace, verdict.Verdict != "blocked")
+		}
+	}
+
+	type rule struct {
+		policy    string
+		direction string
+		index     int
+	}
+	permissive := make(map[rule]bool)
+	for _, finding := range simulation.Permissive {
+		permissive[rule{finding.Policy, finding.Direction, finding.Rule}] = true
+	}
+	for _, policy := range policies {
+		for _, direction := range []string{"Ingress", "Egress"} {
+			if !restricts(policy, direction) {
+				continue
+			}
+			rules := len(policy.Spec.Ingress)
+			if direction == "Egress" {
+				rules = len(policy.Spec.Egress)
+			}
+			for i := 0; i < rules; i++ {
+				check(policyNamespace(policy), !permissive[rule{policyName(policy), strings.ToLower(direction), i}])
+			}
+		}
+	}
+
+	for _, score := range scores {
+		score.Score = 100
+		if score.Checks > 0 {
+			score.Score = score.Passed * 100 / score.Checks
+		}
+		report.Namespaces = append(report.Namespaces, *score)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	sort.Slice(report.Uncovered, func(i, j int) bool {
+		return report.Uncovered[i].Workload < report.Uncovered[j].Workload
+	})
+	return report
+}
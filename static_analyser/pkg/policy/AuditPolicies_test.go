@@ -0,0 +1,142 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	t "static_analyser/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestAuditPolicies(test *testing.T) {
+	// TestAuditPolicies checks the missing allowances, over-permissive rules, uncovered workloads and scores of policies
+	// that match what the code needs, miss an edge of it or allow more than it.
+	//
+	// test: The test.
+
+	workload := func(app string) *t.Yaml2Go {
+		conf := &t.Yaml2Go{Kind: "Deployment"}
+		conf.Metadata.Namespace = "shop"
+		conf.Spec.Template.Metadata.Labels = map[string]string{"app": app}
+		return conf
+	}
+	parsedYamls := map[string]*t.Yaml2Go{"orders": workload("orders"), "payments": workload("payments")}
+	g := t.ServiceGraph{
+		Nodes: []t.GraphNode{{ID: "orders", Namespace: "shop"}, {ID: "payments", Namespace: "shop"}},
+		Edges: []t.GraphEdge{{From: "orders", To: "payments", Protocol: "TCP", Port: "8080", Kind: "SelectOneHealthyInstance", Resolved: true}},
+	}
+
+	pods := func(app string) []t.NetworkPolicyPeer {
+		return []t.NetworkPolicyPeer{{PodSelector: &t.LabelSelector{MatchLabels: map[string]string{"app": app}}}}
+	}
+	port := func(port int) []t.NetworkPolicyPort {
+		return []t.NetworkPolicyPort{{Protocol: "TCP", Port: port}}
+	}
+	policy := func(name string, app string, direction string, egress []t.NetworkPolicyEgressRule, ingress []t.NetworkPolicyIngressRule) t.NetworkPolicy {
+		return t.NetworkPolicy{
+			ApiVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+			Metadata:   t.NetworkPolicyMeta{Name: name, Namespace: "shop"},
+			Spec: t.NetworkPolicySpec{
+				PodSelector: t.LabelSelector{MatchLabels: map[string]string{"app": app}},
+				Egress:      egress,
+				Ingress:     ingress,
+				PolicyTypes: []string{direction},
+			},
+		}
+	}
+	paymentsIngress := policy("payments-ingress", "payments", "Ingress", nil, []t.NetworkPolicyIngressRule{{From: pods("orders"), Ports: port(8080)}})
+
+	cases := []struct {
+		name       string
+		policies   []t.NetworkPolicy
+		missing    int
+		permissive []string
+		uncovered  []string
+		score      string
+	}{
+		{
+			name: "matching",
+			policies: []t.NetworkPolicy{
+				policy("orders-egress", "orders", "Egress", []t.NetworkPolicyEgressRule{{To: pods("payments"), Ports: port(8080)}}, nil),
+				paymentsIngress,
+			},
+			uncovered: []string{"shop/orders: ingress", "shop/payments: egress"},
+			score:     "shop 5/7 71%",
+		},
+		{
+			name: "missing",
+			policies: []t.NetworkPolicy{
+				policy("orders-egress", "orders", "Egress", []t.NetworkPolicyEgressRule{{To: pods("payments"), Ports: port(9090)}}, nil),
+				paymentsIngress,
+			},
+			missing:    1,
+			permissive: []string{"shop/orders-egress egress 0: allows traffic no edge of the service graph needs"},
+			uncovered:  []string{"shop/orders: ingress", "shop/payments: egress"},
+			score:      "shop 3/7 42%",
+		},
+		{
+			name: "allowing all traffic",
+			policies: []t.NetworkPolicy{
+				policy("orders-egress", "orders", "Egress", []t.NetworkPolicyEgressRule{{}}, nil),
+				paymentsIngress,
+			},
+			permissive: []string{"shop/orders-egress egress 0: allows all traffic"},
+			uncovered:  []string{"shop/orders: ingress", "shop/payments: egress"},
+			score:      "shop 4/7 57%",
+		},
+		{
+			name: "allowing every pod and every address",
+			policies: []t.NetworkPolicy{
+				policy("orders-egress", "orders", "Egress", []t.NetworkPolicyEgressRule{
+					{To: []t.NetworkPolicyPeer{{NamespaceSelector: &t.LabelSelector{}}}, Ports: port(8080)},
+					{To: []t.NetworkPolicyPeer{{IPBlock: &t.IPBlock{CIDR: "0.0.0.0/0"}}}, Ports: port(443)},
+				}, nil),
+				paymentsIngress,
+			},
+			permissive: []string{
+				"shop/orders-egress egress 0: allows every pod of every namespace",
+				"shop/orders-egress egress 1: allows traffic no edge of the service graph needs",
+			},
+			uncovered: []string{"shop/orders: ingress", "shop/payments: egress"},
+			score:     "shop 4/8 50%",
+		},
+		{
+			name:      "no policies",
+			uncovered: []string{"shop/orders: ingress, egress", "shop/payments: ingress, egress"},
+			score:     "shop 1/5 20%",
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			report := AuditPolicies(parsedYamls, nil, c.policies, g)
+			if len(report.Missing) != c.missing {
+				test.Errorf("got missing allowances %+v, want %d", report.Missing, c.missing)
+			}
+			permissive := []string{}
+			for _, finding := range report.Permissive {
+				permissive = append(permissive, fmt.Sprintf("%s %s %d: %s", finding.Policy, finding.Direction, finding.Rule, finding.Reason))
+			}
+			if c.permissive == nil {
+				c.permissive = []string{}
+			}
+			if !reflect.DeepEqual(permissive, c.permissive) {
+				test.Errorf("got over-permissive rules %q, want %q", permissive, c.permissive)
+			}
+			uncovered := []string{}
+			for _, workload := range report.Uncovered {
+				uncovered = append(uncovered, workload.Namespace+"/"+workload.Workload+": "+strings.Join(workload.Directions, ", "))
+			}
+			if !reflect.DeepEqual(uncovered, c.uncovered) {
+				test.Errorf("got uncovered workloads %q, want %q", uncovered, c.uncovered)
+			}
+			if len(report.Namespaces) != 1 {
+				test.Fatalf("got scores %+v, want one namespace", report.Namespaces)
+			}
+			score := report.Namespaces[0]
+			if got := fmt.Sprintf("%s %d/%d %d%%", score.Namespace, score.Passed, score.Checks, score.Score); got != c.score {
+				test.Errorf("got score %s, want %s", got, c.score)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func DescribeSelector(selector *t.LabelSelector) string {
+	// DescribeSelector describes a label selector as a comma separated list of key=value pairs and expressions.
+	//
+	// selector: The selector to describe.
+	//
+	// Returns:
+	// The sorted pairs and expressions, such as "app=web,tier In (api,web)", or "*" if the selector matches everything.
+
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return "*"
+	}
+	pairs := []string{}
+	for key, value := range selector.MatchLabels {
+		pairs = append(pairs, key+"="+value)
+	}
+	for _, expr := range selector.MatchExpressions {
+		pair := expr.Key + " " + expr.Operator
+		if len(expr.Values) > 0 {
+			pair += " (" + strings.Join(expr.Values, ",") + ")"
+		}
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func DescribeEgressRule(rule t.NetworkPolicyEgressRule) []string {
+	// DescribeEgressRule describes an egress rule as one line per destination and port.
+	//
+	// rule: The rule to describe.
+	//
+	// Returns:
+	// Lines such as "pods app=helloservice port TCP/80", so that rules can be compared line by line.
+
+	destinations := []string{}
+	for _, peer := range rule.To {
+		parts := []string{}
+		if peer.NamespaceSelector != nil {
+			parts = append(parts, "namespaces "+DescribeSelector(peer.NamespaceSelector))
+		}
+		if peer.PodSelector != nil {
+			parts = append(parts, "pods "+DescribeSelector(peer.PodSelector))
+		}
+		if peer.IPBlock != nil {
+			parts = append(parts, "ipBlock "+peer.IPBlock.CIDR)
+		}
+		destinations = append(destinations, strings.Join(parts, " "))
+	}
+	if len(destinations) == 0 {
+		destinations = append(destinations, "anywhere")
+	}
+
+	ports := []string{}
+	for _, port := range rule.Ports {
+		if port.Port == nil {
+			ports = append(ports, port.Protocol+"/any")
+			continue
+		}
+		if port.EndPort != 0 {
+			ports = append(ports, fmt.Sprintf("%s/%v-%d", port.Protocol, port.Port, port.EndPort))
+			continue
+		}
+		ports = append(ports, fmt.Sprintf("%s/%v", port.Protocol, port.Port))
+	}
+	if len(ports) == 0 {
+		ports = append(ports, "any")
+	}
+
+	lines := []string{}
+	for _, destination := range destinations {
+		for _, port := range ports {
+			lines = append(lines, destination+" port "+port)
+		}
+	}
+	return lines
+}
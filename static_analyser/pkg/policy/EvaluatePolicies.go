@@ -0,0 +1,363 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+// Verdicts of a rule, a direction or an edge. Unknown means the policies cannot be evaluated statically, for example
+// because the destination is a host name or the port could not be resolved.
+const (
+	blocked = iota
+	unknown
+	allowed
+)
+
+func EvaluatePolicies(parsedYamls map[string]*t.Yaml2Go, namespaces []t.KubernetesNamespace, policies []t.NetworkPolicy, g t.ServiceGraph) t.SimulationReport {
+	// EvaluatePolicies evaluates a set of NetworkPolicies against the service graph without a cluster, following Kubernetes semantics:
+	// a pod selected by no policy of a direction is unrestricted in that direction, and a selected pod only allows the
+	// connections some rule of a selecting policy allows. An edge is allowed if the egress of its source and the ingress of
+	// its destination allow it.
+	//
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	// Together with WorkloadSelector and WorkloadLabels they give the namespace, labels and named ports of each workload.
+	// namespaces: The Namespaces declared in the sources, whose labels namespace selectors match, see NamespaceLabels. May be nil.
+	// policies: The NetworkPolicies to evaluate.
+	// g: The service graph.
+	//
+	// Returns:
+	// The verdict of every edge, and the rules that allow more than the graph needs: rules allowing all traffic, the whole
+	// cluster or the whole internet, and rules no edge uses.
+
+	// endpoint is a node of the graph as NetworkPolicies see it
+	type endpoint struct {
+		workload        string
+		namespace       string
+		namespaceLabels map[string]string
+		labels          map[string]string
+		ip              net.IP
+		block           *net.IPNet // block holds the addresses a host computed at runtime can take, see util.TemplateCIDR
+		host            string
+	}
+	toEndpoint := func(id string) endpoint {
+		switch {
+		case strings.HasPrefix(id, graph.ExternalPrefix):
+			host := strings.TrimPrefix(id, graph.ExternalPrefix)
+			e := endpoint{ip: net.ParseIP(host), host: host}
+			if cidr, ok := util.TemplateCIDR(host); ok {
+				_, e.block, _ = net.ParseCIDR(cidr)
+			}
+			return e
+		case strings.HasPrefix(id, graph.UnresolvedPrefix):
+			return endpoint{host: id}
+		}
+		namespace, _ := WorkloadSelector(id, parsedYamls)
+		return endpoint{workload: id, namespace: namespace, namespaceLabels: NamespaceLabels(namespace, namespaces), labels: WorkloadLabels(id, parsedYamls)}
+	}
+
+	peerMatches := func(peer t.NetworkPolicyPeer, namespace string, e endpoint) int {
+		if peer.IPBlock != nil {
+			if e.ip == nil && e.block != nil {
+				_, cidr, err := net.ParseCIDR(peer.IPBlock.CIDR)
+				if err != nil {
+					return blocked
+				}
+				blockOnes, _ := e.block.Mask.Size()
+				cidrOnes, _ := cidr.Mask.Size()
+				switch {
+				case cidr.Contains(e.block.IP) && blockOnes >= cidrOnes:
+					// Every address the host can take is in the block, unless an exception takes some
+					for _, except := range peer.IPBlock.Except {
+						if _, exceptCIDR, err := net.ParseCIDR(except); err == nil && (exceptCIDR.Contains(e.block.IP) || e.block.Contains(exceptCIDR.IP)) {
+							return unknown
+						}
+					}
+					return allowed
+				case e.block.Contains(cidr.IP):
+					// Only some of the addresses the host can take are in the block
+					return unknown
+				}
+				return blocked
+			}
+			if e.ip == nil {
+				if e.workload == "" {
+					// A host name may resolve into the block or not
+					return unknown
+				}
+				return blocked
+			}
+			_, cidr, err := net.ParseCIDR(peer.IPBlock.CIDR)
+			if err != nil || !cidr.Contains(e.ip) {
+				return blocked
+			}
+			for _, except := range peer.IPBlock.Except {
+				if _, exceptCIDR, err := net.ParseCIDR(except); err == nil && exceptCIDR.Contains(e.ip) {
+					return blocked
+				}
+			}
+			return allowed
+		}
+		if e.workload == "" {
+			if strings.HasPrefix(e.host, graph.UnresolvedPrefix) {
+				return unknown
+			}
+			return blocked
+		}
+		if peer.NamespaceSelector != nil {
+			if !MatchSelector(*peer.NamespaceSelector, e.namespaceLabels) {
+				return blocked
+			}
+		} else if e.namespace != namespace {
+			return blocked
+		}
+		if peer.PodSelector != nil && !MatchSelector(*peer.PodSelector, e.labels) {
+			return blocked
+		}
+		return allowed
+	}
+
+	portMatches := func(ports []t.NetworkPolicyPort, edge t.GraphEdge, destination endpoint) int {
+		if len(ports) == 0 {
+			return allowed
+		}
+		// A port computed at runtime may be a range, which a rule allows only if it allows every port of the range
+		number, last, ok := util.ParsePortRange(edge.Port)
+		result := blocked
+		for _, port := range ports {
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = "TCP"
+			}
+			if !strings.EqualFold(protocol, edge.Protocol) {
+				continue
+			}
+			if port.Port == nil {
+				return allowed
+			}
+			if !ok {
+				result = unknown
+				continue
+			}
+			switch p := port.Port.(type) {
+			case int:
+				end := p
+				if port.EndPort != 0 {
+					end = port.EndPort
+				}
+				if number >= p && last <= end {
+					return allowed
+				}
+				if number <= end && last >= p {
+					// The rule allows only part of the range
+					result = unknown
+				}
+			case string:
+				if p == edge.Port {
+					return allowed
+				}
+				if number != last {
+					result = unknown
+					continue
+				}
+				// A named port is resolved against the containers of the destination
+				named := false
+				if conf, ok := parsedYamls[destination.workload]; ok {
+					for _, container := range conf.Spec.Template.Spec.Containers {
+						for _, containerPort := range container.Ports {
+							if containerPort.Name == p {
+								named = true
+								if containerPort.ContainerPort == number {
+									return allowed
+								}
+							}
+						}
+					}
+				}
+				if !named {
+					result = unknown
+				}
+			}
+		}
+		return result
+	}
+
+	// used records which rules matched an edge, keyed by policy, direction and rule index
+	used := make(map[string]bool)
+	ruleKey := func(policy t.NetworkPolicy, direction string, rule int) string {
+		return fmt.Sprintf("%s\x00%s\x00%d", policyName(policy), direction, rule)
+	}
+
+	evaluateDirection := func(direction string, subject endpoint, other endpoint, edge t.GraphEdge, destination endpoint) (int, []string) {
+		if subject.workload == "" {
+			// Only the analysed workloads are subject to the policies
+			return allowed, nil
+		}
+		selecting := []string{}
+		result := blocked
+		for _, policy := range policies {
+			if policyNamespace(policy) != subject.namespace || !restricts(policy, direction) || !MatchSelector(policy.Spec.PodSelector, subject.labels) {
+				continue
+			}
+			selecting = append(selecting, policyName(policy))
+
+			type rule struct {
+				peers []t.NetworkPolicyPeer
+				ports []t.NetworkPolicyPort
+			}
+			rules := []rule{}
+			if direction == "Egress" {
+				for _, r := range policy.Spec.Egress {
+					rules = append(rules, rule{r.To, r.Ports})
+				}
+			} else {
+				for _, r := range policy.Spec.Ingress {
+					rules = append(rules, rule{r.From, r.Ports})
+				}
+			}
+			for i, r := range rules {
+				peer := allowed
+				if len(r.peers) > 0 {
+					peer = blocked
+					for _, p := range r.peers {
+						peer = max(peer, peerMatches(p, subject.namespace, other))
+					}
+				}
+				verdict := min(peer, portMatches(r.ports, edge, destination))
+				if verdict != blocked {
+					// A rule that may allow an edge is needed by the graph
+					used[ruleKey(policy, direction, i)] = true
+				}
+				result = max(result, verdict)
+			}
+		}
+		if len(selecting) == 0 {
+			return allowed, nil
+		}
+		return result, selecting
+	}
+
+	report := t.SimulationReport{Verdicts: []t.PolicyVerdict{}, Permissive: []t.PolicyFinding{}}
+	for _, edge := range g.Edges {
+		source, destination := toEndpoint(edge.From), toEndpoint(edge.To)
+		egress, egressPolicies := evaluateDirection("Egress", source, destination, edge, destination)
+		ingress, ingressPolicies := evaluateDirection("Ingress", destination, source, edge, destination)
+
+		verdict := t.PolicyVerdict{Edge: edge, Verdict: "allowed"}
+		reasons := []string{}
+		if egress != allowed {
+			reasons = append(reasons, "egress of "+edge.From+" restricted by "+strings.Join(egressPolicies, ", "))
+		}
+		if ingress != allowed {
+			reasons = append(reasons, "ingress of "+edge.To+" restricted by "+strings.Join(ingressPolicies, ", "))
+		}
+		switch min(egress, ingress) {
+		case blocked:
+			verdict.Verdict = "blocked"
+		case unknown:
+			verdict.Verdict = "unknown"
+		}
+		verdict.Reason = strings.Join(reasons, "; ")
+		report.Verdicts = append(report.Verdicts, verdict)
+	}
+
+	// Rules of policies that select no analysed workload cannot be checked against the graph
+	selectsWorkload := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if node.External {
+			continue
+		}
+		e := toEndpoint(node.ID)
+		for _, policy := range policies {
+			if policyNamespace(policy) == e.namespace && MatchSelector(policy.Spec.PodSelector, e.labels) {
+				selectsWorkload[policyName(policy)] = true
+			}
+		}
+	}
+	permissive := func(policy t.NetworkPolicy, direction string, index int, peers []t.NetworkPolicyPeer, ports []t.NetworkPolicyPort) {
+		finding := t.PolicyFinding{Policy: policyName(policy), Direction: strings.ToLower(direction), Rule: index}
+		switch {
+		case len(peers) == 0 && len(ports) == 0:
+			finding.Reason = "allows all traffic"
+		case !used[ruleKey(policy, direction, index)] && selectsWorkload[policyName(policy)]:
+			finding.Reason = "allows traffic no edge of the service graph needs"
+		default:
+			for _, peer := range peers {
+				if peer.IPBlock != nil && strings.HasSuffix(peer.IPBlock.CIDR, "/0") {
+					finding.Reason = "allows every IP address (" + peer.IPBlock.CIDR + ")"
+				} else if peer.NamespaceSelector != nil && DescribeSelector(peer.NamespaceSelector) == "*" && DescribeSelector(peer.PodSelector) == "*" {
+					finding.Reason = "allows every pod of every namespace"
+				}
+			}
+		}
+		if finding.Reason != "" {
+			report.Permissive = append(report.Permissive, finding)
+		}
+	}
+	for _, policy := range policies {
+		if restricts(policy, "Egress") {
+			for i, rule := range policy.Spec.Egress {
+				permissive(policy, "Egress", i, rule.To, rule.Ports)
+			}
+		}
+		if restricts(policy, "Ingress") {
+			for i, rule := range policy.Spec.Ingress {
+				permissive(policy, "Ingress", i, rule.From, rule.Ports)
+			}
+		}
+	}
+	sort.SliceStable(report.Permissive, func(i, j int) bool {
+		return report.Permissive[i].Policy < report.Permissive[j].Policy
+	})
+	return report
+}
+
+func policyNamespace(policy t.NetworkPolicy) string {
+	// policyNamespace returns the namespace of a NetworkPolicy.
+	//
+	// policy: The NetworkPolicy.
+	//
+	// Returns:
+	// The namespace of its metadata, "default" if unset.
+
+	if policy.Metadata.Namespace == "" {
+		return "default"
+	}
+	return policy.Metadata.Namespace
+}
+
+func policyName(policy t.NetworkPolicy) string {
+	// policyName returns the namespace and name of a NetworkPolicy.
+	//
+	// policy: The NetworkPolicy.
+	//
+	// Returns:
+	// The namespace and name, separated by a slash.
+
+	return policyNamespace(policy) + "/" + policy.Metadata.Name
+}
+
+func restricts(policy t.NetworkPolicy, direction string) bool {
+	// restricts tells whether a NetworkPolicy restricts a direction of the pods it selects.
+	//
+	// policy: The NetworkPolicy.
+	// direction: "Ingress" or "Egress".
+	//
+	// Returns:
+	// True if the direction is among the policyTypes of the policy. Without policyTypes, a policy restricts
+	// ingress, and egress if it has egress rules.
+
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return direction == "Ingress" || len(policy.Spec.Egress) > 0
+	}
+	for _, policyType := range policy.Spec.PolicyTypes {
+		if policyType == direction {
+			return true
+		}
+	}
+	return false
+}
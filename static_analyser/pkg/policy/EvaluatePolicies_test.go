@@ -0,0 +1,90 @@
+package policy
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestEvaluatePoliciesLabels(test *testing.T) {
+	// TestEvaluatePoliciesLabels checks that pod and namespace selectors are matched against every label of the pods and
+	// namespaces, not only the app label and the namespace name.
+	//
+	// test: The test.
+
+	workload := func(namespace string, labels map[string]string) *t.Yaml2Go {
+		conf := &t.Yaml2Go{Kind: "Deployment"}
+		conf.Metadata.Namespace = namespace
+		conf.Spec.Template.Metadata.Labels = labels
+		return conf
+	}
+	parsedYamls := map[string]*t.Yaml2Go{
+		"orders":   workload("shop", map[string]string{"app": "orders", "tier": "backend"}),
+		"payments": workload("billing", map[string]string{"app": "payments", "tier": "frontend", "version": "v2"}),
+	}
+	namespaces := []t.KubernetesNamespace{{Name: "billing", Labels: map[string]string{"team": "payments"}}}
+	g := t.ServiceGraph{
+		Nodes: []t.GraphNode{{ID: "orders", Namespace: "shop"}, {ID: "payments", Namespace: "billing"}},
+		Edges: []t.GraphEdge{{From: "orders", To: "payments", Protocol: "TCP", Port: "8080", Kind: "SelectOneHealthyInstance", Resolved: true}},
+	}
+	egress := func(peer t.NetworkPolicyPeer) []t.NetworkPolicy {
+		return []t.NetworkPolicy{{
+			ApiVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+			Metadata:   t.NetworkPolicyMeta{Name: "orders-egress", Namespace: "shop"},
+			Spec: t.NetworkPolicySpec{
+				PodSelector: t.LabelSelector{MatchLabels: map[string]string{"app": "orders", "tier": "backend"}},
+				Egress:      []t.NetworkPolicyEgressRule{{To: []t.NetworkPolicyPeer{peer}}},
+				PolicyTypes: []string{"Egress"},
+			},
+		}}
+	}
+	selector := func(labels map[string]string) *t.LabelSelector {
+		return &t.LabelSelector{MatchLabels: labels}
+	}
+
+	cases := []struct {
+		name    string
+		peer    t.NetworkPolicyPeer
+		verdict string
+	}{
+		{
+			name:    "matching pod and namespace labels",
+			peer:    t.NetworkPolicyPeer{NamespaceSelector: selector(map[string]string{"team": "payments"}), PodSelector: selector(map[string]string{"app": "payments", "version": "v2"})},
+			verdict: "allowed",
+		},
+		{
+			name:    "pod label with another value",
+			peer:    t.NetworkPolicyPeer{NamespaceSelector: selector(map[string]string{"team": "payments"}), PodSelector: selector(map[string]string{"app": "payments", "tier": "backend"})},
+			verdict: "blocked",
+		},
+		{
+			name: "pod label the pods lack",
+			peer: t.NetworkPolicyPeer{NamespaceSelector: selector(nil), PodSelector: &t.LabelSelector{
+				MatchLabels:      map[string]string{"app": "payments"},
+				MatchExpressions: []t.LabelSelectorRequirement{{Key: "canary", Operator: "Exists"}},
+			}},
+			verdict: "blocked",
+		},
+		{
+			name:    "namespace label with another value",
+			peer:    t.NetworkPolicyPeer{NamespaceSelector: selector(map[string]string{"team": "orders"}), PodSelector: selector(map[string]string{"app": "payments"})},
+			verdict: "blocked",
+		},
+		{
+			name:    "namespace name and label",
+			peer:    t.NetworkPolicyPeer{NamespaceSelector: selector(map[string]string{"kubernetes.io/metadata.name": "billing", "team": "payments"})},
+			verdict: "allowed",
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			report := EvaluatePolicies(parsedYamls, namespaces, egress(c.peer), g)
+			if len(report.Verdicts) != 1 {
+				test.Fatalf("got %d verdicts, want 1", len(report.Verdicts))
+			}
+			if verdict := report.Verdicts[0]; verdict.Verdict != c.verdict {
+				test.Errorf("got %s (%s), want %s", verdict.Verdict, verdict.Reason, c.verdict)
+			}
+		})
+	}
+}
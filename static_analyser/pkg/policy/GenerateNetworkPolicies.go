@@ -0,0 +1,266 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strconv"
+	"strings"
+)
+
+// ApproximationsAnnotation is the annotation of a generated NetworkPolicy that lists the rules approximating values
+// computed at runtime.
+const ApproximationsAnnotation = "static-analyser/approximations"
+
+// nacosDefaultPorts maps the kinds of Nacos server requests to the ports a Nacos server listens on by default: the HTTP
+// port, the gRPC port 1000 above it, and the port of an address server.
+var nacosDefaultPorts = map[string]int{"nacos": 8848, "nacos-grpc": 9848, "nacos-endpoint": 8080}
+
+func WorkloadSelector(application string, parsedYamls map[string]*t.Yaml2Go) (string, map[string]string) {
+	// WorkloadSelector works out the namespace and pod labels that select an application's pods.
+	//
+	// application: The name of the application.
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	//
+	// Returns:
+	// The namespace of the application, "default" if none is declared.
+	// The labels of the application's pod template, falling back to an app label with the application name.
+
+	namespace := "default"
+	labels := map[string]string{"app": application}
+	if conf, ok := parsedYamls[application]; ok {
+		if conf.Metadata.Namespace != "" {
+			namespace = conf.Metadata.Namespace
+		}
+		if app := conf.Spec.Template.Metadata.Labels["app"]; app != "" {
+			labels["app"] = app
+		}
+	}
+	return namespace, labels
+}
+
+func GenerateNetworkPolicies(parsedYamls map[string]*t.Yaml2Go, manifests map[string]t.TCPManifest) []t.NetworkPolicy {
+	// GenerateNetworkPolicies generates one egress NetworkPolicy per application from its TCPManifest.
+	//
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	// manifests: A map where the keys are the names of the applications and the values are the corresponding TCPManifests.
+	//
+	// Returns:
+	// The NetworkPolicies, sorted by application. Each allows egress to the workloads the application calls, on the called ports,
+	// and to the IP addresses it calls outside the analysed workloads. Nacos servers, datastores and brokers that are neither,
+	// such as those under a host name or one computed at runtime, are allowed on their ports to any destination, so the
+	// application keeps reaching them; a port of theirs computed at runtime is taken to be their default port. Other requests
+	// whose target is neither are left out.
+	// Addresses and ports computed at runtime are approximated: an address with a known prefix, such as "10.10.10.{n}", by the
+	// ipBlock of its known octets, and a range of ports by a port and endPort. Policies holding such rules, or rules for
+	// services discovered by a name computed at runtime, list them in their ApproximationsAnnotation.
+
+	applications := []string{}
+	for application := range manifests {
+		applications = append(applications, application)
+	}
+	sort.Strings(applications)
+
+	policies := []t.NetworkPolicy{}
+	for _, application := range applications {
+		namespace, labels := WorkloadSelector(application, parsedYamls)
+
+		// Collect the ports per destination so every destination gets a single rule
+		order := []string{}
+		peers := make(map[string]t.NetworkPolicyPeer)
+		ports := make(map[string][]t.NetworkPolicyPort)
+		seen := make(map[string]bool)
+		// The destinations whose rule allows every port
+		allPorts := make(map[string]bool)
+		// The rules approximating values computed at runtime, noted once each
+		approximations := []string{}
+		approximate := func(note string) {
+			if !util.Contains(approximations, note) {
+				approximations = append(approximations, note)
+			}
+		}
+		for _, req := range manifests[application].Requests {
+			target := req.URL
+			block, approximated := util.TemplateCIDR(req.URL)
+			var key string
+			var peer t.NetworkPolicyPeer
+			switch {
+			case req.Name != "":
+				peerNamespace, peerLabels := WorkloadSelector(req.Name, parsedYamls)
+				key = "workload:" + req.Name
+				peer = t.NetworkPolicyPeer{PodSelector: &t.LabelSelector{MatchLabels: peerLabels}}
+				if peerNamespace != namespace {
+					peer.NamespaceSelector = &t.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": peerNamespace}}
+				}
+				target = req.Name
+				if req.ServiceName != "" && !util.IsResolved(req.ServiceName) {
+					approximate(fmt.Sprintf("%s is taken for the service name %s computed at runtime", req.Name, req.ServiceName))
+				}
+			case net.ParseIP(req.URL) != nil:
+				cidr := req.URL + "/32"
+				if strings.Contains(req.URL, ":") {
+					cidr = req.URL + "/128"
+				}
+				key = "ip:" + cidr
+				peer = t.NetworkPolicyPeer{IPBlock: &t.IPBlock{CIDR: cidr}}
+			case approximated:
+				key = "ip:" + block
+				peer = t.NetworkPolicyPeer{IPBlock: &t.IPBlock{CIDR: block}}
+				approximate(fmt.Sprintf("ipBlock %s covers the addresses %s computed at runtime", block, req.URL))
+			case strings.HasPrefix(req.Kind, "nacos"):
+				// The Nacos server usually runs outside the analysed workloads under a host name,
+				// which a NetworkPolicy cannot select, so only its ports are restricted
+				key = "registry"
+			case util.DefaultPorts[req.Kind] != "":
+				// So are datastores and brokers under a host name or one computed at runtime
+				key = "datastore:" + req.Kind
+				approximate(fmt.Sprintf("%s at %s is allowed on its ports to any destination", req.Kind, req.URL))
+			default:
+				continue
+			}
+
+			if _, ok := peers[key]; !ok {
+				order = append(order, key)
+				peers[key] = peer
+			}
+			// Rules without destinations cannot allow every port, which would allow all egress,
+			// so the port the server listens on by default is taken
+			anywhere := key == "registry" || strings.HasPrefix(key, "datastore:")
+			port := policyPort(req.Port)
+			if port.Port == nil && anywhere {
+				if defaultPort, ok := defaultServerPort(req.Kind); ok {
+					port.Port = defaultPort
+					approximate(fmt.Sprintf("port %d is taken for the %s port %s computed at runtime", defaultPort, serverName(req.Kind), req.Port))
+					req.Port = strconv.Itoa(defaultPort)
+				}
+			}
+			switch {
+			case port.Port == nil && anywhere:
+			case port.Port == nil:
+				// A port computed at runtime can be any port of the destination
+				allPorts[key] = true
+				if req.Port != "" && req.Port != "nil" {
+					approximate(fmt.Sprintf("all ports of %s are allowed for the port %s computed at runtime", target, req.Port))
+				}
+			case !seen[key+"\x00"+req.Port]:
+				seen[key+"\x00"+req.Port] = true
+				ports[key] = append(ports[key], port)
+			}
+			if port.EndPort != 0 {
+				approximate(fmt.Sprintf("ports %s cover the port of %s computed at runtime", req.Port, target))
+			}
+		}
+		sort.Strings(order)
+
+		egress := []t.NetworkPolicyEgressRule{}
+		for _, key := range order {
+			rule := t.NetworkPolicyEgressRule{To: []t.NetworkPolicyPeer{peers[key]}, Ports: ports[key]}
+			if allPorts[key] {
+				rule.Ports = nil
+			}
+			if key == "registry" || strings.HasPrefix(key, "datastore:") {
+				if len(rule.Ports) == 0 {
+					// A rule without destinations and ports would allow all egress
+					continue
+				}
+				rule.To = nil
+			}
+			egress = append(egress, rule)
+		}
+
+		metadata := t.NetworkPolicyMeta{Name: application + "-egress", Namespace: namespace}
+		if len(approximations) > 0 {
+			sort.Strings(approximations)
+			metadata.Annotations = map[string]string{ApproximationsAnnotation: strings.Join(approximations, "; ")}
+		}
+		policies = append(policies, t.NetworkPolicy{
+			ApiVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+			Metadata:   metadata,
+			Spec: t.NetworkPolicySpec{
+				PodSelector: t.LabelSelector{MatchLabels: labels},
+				Egress:      egress,
+				PolicyTypes: []string{"Egress"},
+			},
+		})
+	}
+	return policies
+}
+
+func defaultServerPort(kind string) (int, bool) {
+	// defaultServerPort returns the port a Nacos server, datastore or broker listens on by default.
+	//
+	// kind: The kind of the request, such as "nacos-grpc" or "mysql".
+	//
+	// Returns:
+	// The port and true if the kind has a default port, 0 and false otherwise.
+
+	if port, ok := nacosDefaultPorts[kind]; ok {
+		return port, true
+	}
+	port, err := strconv.Atoi(util.DefaultPorts[kind])
+	return port, err == nil
+}
+
+func serverName(kind string) string {
+	// serverName names the server a request of a kind is sent to in the approximation notes.
+	//
+	// kind: The kind of the request, such as "nacos" or "redis".
+	//
+	// Returns:
+	// "Nacos server" for the Nacos kinds, the kind otherwise.
+
+	if strings.HasPrefix(kind, "nacos") {
+		return "Nacos server"
+	}
+	return kind
+}
+
+func policyPort(port string) t.NetworkPolicyPort {
+	// policyPort converts a port from a TCPRequest into a NetworkPolicy port.
+	//
+	// port: The port as found in the source, either a number, a range of numbers such as "8080-8089", a named port or a
+	// template computed at runtime.
+	//
+	// Returns:
+	// A TCP NetworkPolicyPort. Its Port is nil if the port is unknown or is not a valid port name, such as a template,
+	// and its EndPort is set for a range.
+
+	policyPort := t.NetworkPolicyPort{Protocol: "TCP"}
+	if lo, hi, ok := util.ParsePortRange(port); ok {
+		policyPort.Port = lo
+		if hi > lo {
+			policyPort.EndPort = hi
+		}
+	} else if isPortName(port) {
+		policyPort.Port = port
+	}
+	return policyPort
+}
+
+func isPortName(name string) bool {
+	// isPortName checks if a name is a valid named port, an IANA_SVC_NAME: at most 15 lowercase letters, digits and
+	// hyphens, with at least one letter, neither starting nor ending with a hyphen nor holding two adjacent ones.
+	//
+	// name: The name.
+	//
+	// Returns:
+	// True if Kubernetes accepts the name as a port name, false otherwise.
+
+	if name == "" || len(name) > 15 || name == "nil" || strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") || strings.Contains(name, "--") {
+		return false
+	}
+	letter := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z':
+			letter = true
+		case r >= '0' && r <= '9' || r == '-':
+		default:
+			return false
+		}
+	}
+	return letter
+}
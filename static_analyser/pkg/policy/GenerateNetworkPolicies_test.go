@@ -0,0 +1,62 @@
+package policy
+
+import (
+	t "static_analyser/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestGenerateNetworkPoliciesDatastores(test *testing.T) {
+	// TestGenerateNetworkPoliciesDatastores checks that datastores the policies cannot select, such as those under a host
+	// name or one computed at runtime, are allowed on their ports to any destination rather than left out.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name string
+		req  t.TCPRequest
+		port interface{}
+		note string
+	}{
+		{
+			name: "host name",
+			req:  t.TCPRequest{Type: "tcp", URL: "redis.cache.svc:6379", Port: "6379", Kind: "redis"},
+			port: 6379,
+			note: "redis at redis.cache.svc:6379 is allowed on its ports to any destination",
+		},
+		{
+			name: "host computed at runtime",
+			req:  t.TCPRequest{Type: "tcp", URL: "{dbHost}:5433", Port: "5433", Kind: "postgres"},
+			port: 5433,
+			note: "postgres at {dbHost}:5433 is allowed on its ports to any destination",
+		},
+		{
+			name: "host and port computed at runtime",
+			req:  t.TCPRequest{Type: "tcp", URL: "{dbHost}:{dbPort}", Port: "{dbPort}", Kind: "mysql"},
+			port: 3306,
+			note: "port 3306 is taken for the mysql port {dbPort} computed at runtime",
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			manifests := map[string]t.TCPManifest{"orders": {Service: "orders", Requests: []t.TCPRequest{c.req}}}
+			policies := GenerateNetworkPolicies(nil, manifests)
+			if len(policies) != 1 {
+				test.Fatalf("got %d policies, want 1", len(policies))
+			}
+			egress := policies[0].Spec.Egress
+			if len(egress) != 1 {
+				test.Fatalf("got %d egress rules, want 1: %+v", len(egress), egress)
+			}
+			if egress[0].To != nil {
+				test.Errorf("got destinations %+v, want any destination", egress[0].To)
+			}
+			if len(egress[0].Ports) != 1 || egress[0].Ports[0].Port != c.port {
+				test.Errorf("got ports %+v, want %v", egress[0].Ports, c.port)
+			}
+			if note := policies[0].Metadata.Annotations[ApproximationsAnnotation]; !strings.Contains(note, c.note) {
+				test.Errorf("got approximations %q, want them to hold %q", note, c.note)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package policy
+
+import (
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+)
+
+func MatchSelector(selector t.LabelSelector, labels map[string]string) bool {
+	// MatchSelector checks whether a label selector selects a resource, following Kubernetes label selector semantics.
+	//
+	// selector: The selector. An empty selector selects everything.
+	// labels: The labels of the resource.
+	//
+	// Returns:
+	// True if the labels carry every label of MatchLabels and satisfy every expression of MatchExpressions; false otherwise,
+	// including for expressions with an unknown operator.
+
+	for key, value := range selector.MatchLabels {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	for _, expr := range selector.MatchExpressions {
+		value, ok := labels[expr.Key]
+		switch expr.Operator {
+		case "In":
+			if !ok || !util.Contains(expr.Values, value) {
+				return false
+			}
+		case "NotIn":
+			if ok && util.Contains(expr.Values, value) {
+				return false
+			}
+		case "Exists":
+			if !ok {
+				return false
+			}
+		case "DoesNotExist":
+			if ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
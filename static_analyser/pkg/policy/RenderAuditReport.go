@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"fmt"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderAuditReport(report t.AuditReport) string {
+	// RenderAuditReport renders an AuditReport as text.
+	//
+	// report: The report to render.
+	//
+	// Returns:
+	// The missing allowances, the edges that cannot be evaluated, the over-permissive rules and the uncovered workloads,
+	// one per line, followed by the score of each namespace.
+
+	var b strings.Builder
+	for _, section := range []struct {
+		title    string
+		verdicts []t.PolicyVerdict
+	}{{"Missing allowances", report.Missing}, {"Edges that cannot be evaluated statically", report.Unknown}} {
+		if len(section.verdicts) == 0 {
+			continue
+		}
+		b.WriteString(section.title + ":\n")
+		for _, verdict := range section.verdicts {
+			fmt.Fprintf(&b, "  %s -> %s %s: %s\n", verdict.Edge.From, verdict.Edge.To, graph.EdgeLabel(verdict.Edge), verdict.Reason)
+		}
+		b.WriteString("\n")
+	}
+	if len(report.Permissive) > 0 {
+		b.WriteString("Over-permissive rules:\n")
+		for _, finding := range report.Permissive {
+			fmt.Fprintf(&b, "  %s %s rule %d: %s\n", finding.Policy, finding.Direction, finding.Rule, finding.Reason)
+		}
+		b.WriteString("\n")
+	}
+	if len(report.Uncovered) > 0 {
+		b.WriteString("Workloads without policies:\n")
+		for _, workload := range report.Uncovered {
+			fmt.Fprintf(&b, "  %s/%s: no %s policy\n", workload.Namespace, workload.Workload, strings.Join(workload.Directions, " or "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Scores:\n")
+	for _, score := range report.Namespaces {
+		fmt.Fprintf(&b, "  %s: %d%% (%d of %d checks passed)\n", score.Namespace, score.Score, score.Passed, score.Checks)
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package policy
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestRenderAuditReport(test *testing.T) {
+	// TestRenderAuditReport checks that the sections of an audit report are rendered only when they have entries, and
+	// that the scores are always rendered.
+	//
+	// test: The test.
+
+	edge := t.GraphEdge{From: "orders", To: "payments", Protocol: "TCP", Port: "8080", Kind: "SelectOneHealthyInstance", Method: "POST", Path: "/pay"}
+	cases := []struct {
+		name   string
+		report t.AuditReport
+		want   string
+	}{
+		{
+			name:   "passing",
+			report: t.AuditReport{Namespaces: []t.NamespaceScore{{Namespace: "shop", Passed: 7, Checks: 7, Score: 100}}},
+			want:   "Scores:\n  shop: 100% (7 of 7 checks passed)\n",
+		},
+		{
+			name: "every section",
+			report: t.AuditReport{
+				Missing:    []t.PolicyVerdict{{Edge: edge, Verdict: "blocked", Reason: "egress of orders restricted by shop/orders-egress"}},
+				Unknown:    []t.PolicyVerdict{{Edge: t.GraphEdge{From: "orders", To: "ledger", Protocol: "TCP"}, Verdict: "unknown", Reason: "ingress of ledger restricted by shop/ledger-ingress"}},
+				Permissive: []t.PolicyFinding{{Policy: "shop/orders-egress", Direction: "egress", Rule: 1, Reason: "allows all traffic"}},
+				Uncovered:  []t.UncoveredWorkload{{Workload: "payments", Namespace: "shop", Directions: []string{"ingress", "egress"}}},
+				Namespaces: []t.NamespaceScore{{Namespace: "finance", Passed: 2, Checks: 2, Score: 100}, {Namespace: "shop", Passed: 3, Checks: 8, Score: 37}},
+			},
+			want: "Missing allowances:\n" +
+				"  orders -> payments TCP/8080 SelectOneHealthyInstance POST /pay: egress of orders restricted by shop/orders-egress\n" +
+				"\n" +
+				"Edges that cannot be evaluated statically:\n" +
+				"  orders -> ledger TCP/?: ingress of ledger restricted by shop/ledger-ingress\n" +
+				"\n" +
+				"Over-permissive rules:\n" +
+				"  shop/orders-egress egress rule 1: allows all traffic\n" +
+				"\n" +
+				"Workloads without policies:\n" +
+				"  shop/payments: no ingress or egress policy\n" +
+				"\n" +
+				"Scores:\n" +
+				"  finance: 100% (2 of 2 checks passed)\n" +
+				"  shop: 37% (3 of 8 checks passed)\n",
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := RenderAuditReport(c.report); got != c.want {
+				test.Errorf("got report\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"fmt"
+	"static_analyser/pkg/graph"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderSimulationReport(report t.SimulationReport) string {
+	// RenderSimulationReport renders a SimulationReport as text.
+	//
+	// report: The report to render.
+	//
+	// Returns:
+	// The blocked edges, the edges whose verdict is unknown and the over-permissive rules, one per line, followed by a summary line.
+
+	counts := make(map[string]int)
+	sections := map[string][]string{}
+	for _, verdict := range report.Verdicts {
+		counts[verdict.Verdict]++
+		if verdict.Verdict != "allowed" {
+			line := fmt.Sprintf("  %s -> %s %s: %s", verdict.Edge.From, verdict.Edge.To, graph.EdgeLabel(verdict.Edge), verdict.Reason)
+			sections[verdict.Verdict] = append(sections[verdict.Verdict], line)
+		}
+	}
+
+	var b strings.Builder
+	for _, section := range []struct{ verdict, title string }{{"blocked", "Blocked edges"}, {"unknown", "Edges that cannot be evaluated statically"}} {
+		if len(sections[section.verdict]) > 0 {
+			b.WriteString(section.title + ":\n" + strings.Join(sections[section.verdict], "\n") + "\n\n")
+		}
+	}
+	if len(report.Permissive) > 0 {
+		b.WriteString("Over-permissive rules:\n")
+		for _, finding := range report.Permissive {
+			fmt.Fprintf(&b, "  %s %s rule %d: %s\n", finding.Policy, finding.Direction, finding.Rule, finding.Reason)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%d allowed, %d blocked, %d unknown, %d over-permissive rules\n", counts["allowed"], counts["blocked"], counts["unknown"], len(report.Permissive))
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package policy
+
+import (
+	t "static_analyser/pkg/types"
+)
+
+func WorkloadLabels(application string, parsedYamls map[string]*t.Yaml2Go) map[string]string {
+	// WorkloadLabels works out every label an application's pods carry, which selectors of existing policies and
+	// Services may match on beyond the app label WorkloadSelector selects.
+	//
+	// application: The name of the application.
+	// parsedYamls: A map where the keys are the names of the applications and the values are pointers to the corresponding parsed YAML files.
+	//
+	// Returns:
+	// The labels of the application's pod template, with the app label of WorkloadSelector if the template has none.
+
+	_, selector := WorkloadSelector(application, parsedYamls)
+	labels := make(map[string]string)
+	if conf, ok := parsedYamls[application]; ok {
+		for key, value := range conf.Spec.Template.Metadata.Labels {
+			labels[key] = value
+		}
+	}
+	for key, value := range selector {
+		labels[key] = value
+	}
+	return labels
+}
+
+func NamespaceLabels(namespace string, namespaces []t.KubernetesNamespace) map[string]string {
+	// NamespaceLabels works out the labels of a namespace, which namespace selectors of policies match.
+	//
+	// namespace: The name of the namespace.
+	// namespaces: The Namespaces declared in the sources, see file_utils.ReadKubernetesNamespaces. May be nil.
+	//
+	// Returns:
+	// The labels of the namespace's Namespace, if declared, with the kubernetes.io/metadata.name label Kubernetes sets on
+	// every namespace.
+
+	labels := map[string]string{"kubernetes.io/metadata.name": namespace}
+	for _, ns := range namespaces {
+		if ns.Name != namespace {
+			continue
+		}
+		for key, value := range ns.Labels {
+			if key != "kubernetes.io/metadata.name" {
+				labels[key] = value
+			}
+		}
+	}
+	return labels
+}
@@ -0,0 +1,130 @@
+package security
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strings"
+)
+
+func CheckClientConfigs(configs []t.NacosClientConfig) []t.SecurityFinding {
+	// CheckClientConfigs checks the Nacos client and server configurations of a service for weaknesses, see Rules.go.
+	// Values computed at runtime, such as those read with os.Getenv, are assumed to be safe, except for settings whose
+	// default is unsafe when they are left out.
+	//
+	// configs: The configurations of the service, see parser.FindNacosClientConfigs.
+	//
+	// Returns:
+	// The findings, sorted by location and rule.
+
+	lookup := func(config t.NacosClientConfig, name string) (t.NacosConfigSetting, bool) {
+		// lookup finds a setting of a configuration.
+		//
+		// config: The configuration.
+		// name: The name of the setting.
+		//
+		// Returns:
+		// The last setting of that name and true, or an empty setting and false if the source does not set it.
+
+		found, ok := t.NacosConfigSetting{}, false
+		for _, setting := range config.Settings {
+			if setting.Name == name {
+				found, ok = setting, true
+			}
+		}
+		return found, ok
+	}
+
+	findings := []t.SecurityFinding{}
+	tls := false
+	clients := []t.NacosClientConfig{}
+	for _, config := range configs {
+		if config.Kind != "client" {
+			continue
+		}
+		clients = append(clients, config)
+		if enable, ok := lookup(config, "TLSCfg.Enable"); ok && (enable.Value == "true" || !enable.Literal) {
+			tls = true
+		}
+	}
+
+	for _, config := range clients {
+		for _, name := range credentials {
+			if setting, ok := lookup(config, name); ok && setting.Literal && setting.Value != "" {
+				findings = append(findings, t.SecurityFinding{Rule: HardcodedCredential, Severity: "error", Location: setting.Location,
+					Message:     fmt.Sprintf("%s is hard-coded in the source", name),
+					Remediation: fmt.Sprintf("Read %s from an environment variable backed by a Kubernetes Secret, and rotate it, as it stays in the history of the repository.", name)})
+			}
+		}
+
+		namespace, ok := lookup(config, "NamespaceId")
+		if !ok || (namespace.Literal && (namespace.Value == "" || strings.EqualFold(namespace.Value, "public"))) {
+			location := config.Location
+			if ok {
+				location = namespace.Location
+			}
+			findings = append(findings, t.SecurityFinding{Rule: PublicNamespace, Severity: "warning", Location: location,
+				Message:     "the client uses the public namespace, which every environment and tenant of the server shares",
+				Remediation: "Create a namespace per environment and set NamespaceId from the deployment, so that production services cannot discover or be shadowed by test registrations."})
+		}
+
+		_, username := lookup(config, "Username")
+		_, accessKey := lookup(config, "AccessKey")
+		if !username && !accessKey {
+			findings = append(findings, t.SecurityFinding{Rule: MissingAuth, Severity: "warning", Location: config.Location,
+				Message:     "the client does not authenticate, so the server must accept anonymous clients",
+				Remediation: "Enable nacos.core.auth.enabled on the server and give the client a Username and Password, or an AccessKey and SecretKey, from a Kubernetes Secret."})
+		}
+
+		cacheDir, ok := lookup(config, "CacheDir")
+		switch {
+		case !ok:
+			findings = append(findings, t.SecurityFinding{Rule: DiskCache, Severity: "info", Location: config.Location,
+				Message:     "registry and config data are cached in the default /tmp/nacos/cache, which other processes of the host or pod can read",
+				Remediation: "Set CacheDir to a private directory, such as an emptyDir volume mounted only in this container."})
+		case cacheDir.Literal && (!path.IsAbs(cacheDir.Value) || path.Clean(cacheDir.Value) == "/tmp" || strings.HasPrefix(path.Clean(cacheDir.Value), "/tmp/")):
+			findings = append(findings, t.SecurityFinding{Rule: DiskCache, Severity: "info", Location: cacheDir.Location,
+				Message:     fmt.Sprintf("registry and config data are cached in %q, which is shared or relative to the working directory", cacheDir.Value),
+				Remediation: "Set CacheDir to an absolute private directory, such as an emptyDir volume mounted only in this container, outside the image and /tmp."})
+		}
+
+		if notLoad, ok := lookup(config, "NotLoadCacheAtStart"); !ok || (notLoad.Literal && notLoad.Value == "false") {
+			location := config.Location
+			if ok {
+				location = notLoad.Location
+			}
+			findings = append(findings, t.SecurityFinding{Rule: CacheLoadedAtStart, Severity: "info", Location: location,
+				Message:     "cached registry data is loaded at start, so a stale or tampered cache directs discovery until the server answers",
+				Remediation: "Set NotLoadCacheAtStart to true."})
+		}
+	}
+
+	for _, config := range configs {
+		if config.Kind != "server" || tls {
+			continue
+		}
+		scheme, ok := lookup(config, "Scheme")
+		if ok && (!scheme.Literal || strings.EqualFold(scheme.Value, "https")) {
+			continue
+		}
+		host, _ := lookup(config, "IpAddr")
+		findings = append(findings, t.SecurityFinding{Rule: PlaintextServer, Severity: "warning", Location: config.Location,
+			Message:     fmt.Sprintf("the Nacos server %s is reached over plaintext, exposing credentials and registrations on the network", host.Value),
+			Remediation: "Serve Nacos over TLS, set Scheme to \"https\" and enable TLSCfg on the client."})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		fileA, lineA := util.SplitLocation(findings[i].Location)
+		fileB, lineB := util.SplitLocation(findings[j].Location)
+		if fileA != fileB {
+			return fileA < fileB
+		}
+		if lineA != lineB {
+			return lineA < lineB
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
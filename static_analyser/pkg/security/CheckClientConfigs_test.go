@@ -0,0 +1,112 @@
+package security
+
+import (
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestCheckClientConfigs(test *testing.T) {
+	// TestCheckClientConfigs checks the rules each client and server configuration breaks, with settings written in the
+	// source, read at runtime or left out.
+	//
+	// test: The test.
+
+	// literal and runtime set a field at a line of main.go to a value written in the source or read at runtime
+	literal := func(name string, value string, line string) t.NacosConfigSetting {
+		return t.NacosConfigSetting{Name: name, Value: value, Literal: true, Location: "main.go:" + line}
+	}
+	runtime := func(name string, line string) t.NacosConfigSetting {
+		return t.NacosConfigSetting{Name: name, Value: "{os.Getenv(\"" + name + "\")}", Location: "main.go:" + line}
+	}
+	client := func(settings ...t.NacosConfigSetting) t.NacosClientConfig {
+		return t.NacosClientConfig{Kind: "client", Location: "main.go:10", Settings: settings}
+	}
+	server := func(settings ...t.NacosConfigSetting) t.NacosClientConfig {
+		return t.NacosClientConfig{Kind: "server", Location: "main.go:30", Settings: settings}
+	}
+	// hardened sets every client setting safely, but for the ones given
+	hardened := func(settings ...t.NacosConfigSetting) t.NacosClientConfig {
+		return client(append([]t.NacosConfigSetting{
+			runtime("Username", "11"), runtime("Password", "12"), literal("NamespaceId", "production", "13"),
+			literal("CacheDir", "/var/cache/nacos", "14"), literal("NotLoadCacheAtStart", "true", "15"), literal("TLSCfg.Enable", "true", "16"),
+		}, settings...)...)
+	}
+
+	cases := []struct {
+		name    string
+		configs []t.NacosClientConfig
+		want    []string
+	}{
+		{
+			name:    "hardened",
+			configs: []t.NacosClientConfig{hardened(), server(literal("IpAddr", "nacos.default.svc", "31"))},
+			want:    []string{},
+		},
+		{
+			name:    "defaults",
+			configs: []t.NacosClientConfig{client(), server(literal("IpAddr", "nacos.default.svc", "31"))},
+			want:    []string{"SEC002 main.go:10", "SEC004 main.go:10", "SEC005 main.go:10", "SEC006 main.go:10", "SEC003 main.go:30"},
+		},
+		{
+			name:    "hard-coded credentials",
+			configs: []t.NacosClientConfig{hardened(literal("Password", "secret", "20"), literal("AccessKey", "AK", "21"), literal("SecretKey", "", "22"))},
+			want:    []string{"SEC001 main.go:20", "SEC001 main.go:21"},
+		},
+		{
+			name:    "public namespace",
+			configs: []t.NacosClientConfig{hardened(literal("NamespaceId", "Public", "20"))},
+			want:    []string{"SEC002 main.go:20"},
+		},
+		{
+			name:    "namespace read at runtime",
+			configs: []t.NacosClientConfig{hardened(runtime("NamespaceId", "20"))},
+			want:    []string{},
+		},
+		{
+			name: "access key instead of user",
+			configs: []t.NacosClientConfig{client(runtime("AccessKey", "11"), runtime("SecretKey", "12"), runtime("NamespaceId", "13"),
+				runtime("CacheDir", "14"), literal("NotLoadCacheAtStart", "true", "15"))},
+			want: []string{},
+		},
+		{
+			name:    "shared cache directories",
+			configs: []t.NacosClientConfig{hardened(literal("CacheDir", "/tmp/nacos", "20")), hardened(literal("CacheDir", "cache", "40"))},
+			want:    []string{"SEC005 main.go:20", "SEC005 main.go:40"},
+		},
+		{
+			name:    "cache loaded at start",
+			configs: []t.NacosClientConfig{hardened(literal("NotLoadCacheAtStart", "false", "20"))},
+			want:    []string{"SEC006 main.go:20"},
+		},
+		{
+			name: "plaintext servers",
+			configs: []t.NacosClientConfig{
+				client(runtime("Username", "11"), runtime("NamespaceId", "13"), runtime("CacheDir", "14"), literal("NotLoadCacheAtStart", "true", "15")),
+				server(literal("IpAddr", "nacos-0.nacos", "31"), literal("Scheme", "http", "32")),
+				server(literal("IpAddr", "nacos-1.nacos", "41"), literal("Scheme", "https", "42")),
+				server(literal("IpAddr", "nacos-2.nacos", "51"), runtime("Scheme", "52")),
+			},
+			want: []string{"SEC003 main.go:30"},
+		},
+		{
+			name: "TLS enabled at runtime",
+			configs: []t.NacosClientConfig{
+				client(runtime("Username", "11"), runtime("NamespaceId", "13"), runtime("CacheDir", "14"), literal("NotLoadCacheAtStart", "true", "15"), runtime("TLSCfg.Enable", "16")),
+				server(literal("IpAddr", "nacos.default.svc", "31")),
+			},
+			want: []string{},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			got := []string{}
+			for _, finding := range CheckClientConfigs(c.configs) {
+				got = append(got, finding.Rule+" "+finding.Location)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got findings %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package security
+
+// Rules of the security checks of the Nacos client configuration
+const (
+	HardcodedCredential = "SEC001" // A username, password, access key or secret key is written in the source.
+	PublicNamespace     = "SEC002" // The client uses the public namespace, shared by every environment.
+	PlaintextServer     = "SEC003" // The client connects to a Nacos server without TLS.
+	MissingAuth         = "SEC004" // The client does not authenticate to the Nacos server.
+	DiskCache           = "SEC005" // Registry and config data are cached in a shared or unprotected directory.
+	CacheLoadedAtStart  = "SEC006" // Cached registry data is trusted at start, before the server is reached.
+)
+
+// credentials are the client settings that hold secrets
+var credentials = []string{"Username", "Password", "AccessKey", "SecretKey"}
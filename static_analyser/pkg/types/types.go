@@ -1,5 +1,51 @@
 package types
 
+// ApplicationAnalysis represents the results of analysing the source of one application, which depend on nothing but
+// its Go files and the config snapshot, so that they can be computed concurrently and cached.
+type ApplicationAnalysis struct {
+	Application   string                   `json:"application"`   // Application is the name of the application.
+	Hash          string                   `json:"hash"`          // Hash is the hash of the inputs of the analysis, the key of its cache entry.
+	Registrations map[string][]ServiceInfo `json:"registrations"` // Registrations are the instances the application registers, keyed by service name.
+	Discoveries   []DiscoveryCall          `json:"discoveries"`   // Discoveries are the service discovery calls the application makes.
+	Configs       []ConfigAccess           `json:"configs"`       // Configs are the Nacos configurations the application reads and writes.
+	ClientCalls   []ClientCall             `json:"clientCalls"`   // ClientCalls are the client calls the application makes, in source order.
+	ClientConfigs []NacosClientConfig      `json:"clientConfigs"` // ClientConfigs are the Nacos client and server configurations of the application.
+	Listeners     []Listener               `json:"listeners"`     // Listeners are the server sockets the application listens on.
+	Diagnostics   []Diagnostic             `json:"diagnostics"`   // Diagnostics are the problems found while analysing the application.
+}
+
+// AuditReport represents the result of auditing existing NetworkPolicies against the service graph.
+type AuditReport struct {
+	Missing    []PolicyVerdict     `json:"missing"`    // Missing are the edges the policies block.
+	Unknown    []PolicyVerdict     `json:"unknown"`    // Unknown are the edges the policies cannot be evaluated for statically.
+	Permissive []PolicyFinding     `json:"permissive"` // Permissive are the rules that allow traffic no edge needs or more than the graph needs.
+	Uncovered  []UncoveredWorkload `json:"uncovered"`  // Uncovered are the workloads some direction of which no policy selects.
+	Namespaces []NamespaceScore    `json:"namespaces"` // Namespaces are the scores of the namespaces of the analysed workloads.
+}
+
+// ClientCall represents an outgoing client call found in the source, such as an HTTP request or a gRPC method call.
+type ClientCall struct {
+	Kind        string // Kind is the kind of call, "http" or "grpc".
+	Library     string // Library is the client library, such as "net/http", "resty", "fasthttp" or "grpc".
+	Method      string // Method is the HTTP method, empty if unknown or not HTTP.
+	URL         string // URL is the resolved URL or dial target template of the call.
+	Host        string // Host is the host part of the URL.
+	Port        string // Port is the port part of the URL, empty if not given.
+	Path        string // Path is the HTTP path, or the full gRPC method name such as "/helloworld.Greeter/SayHello".
+	Discovered  bool   // Discovered reports whether the target derives from a Nacos discovery call.
+	ServiceName string // ServiceName is the discovered service the target derives from.
+	Location    string // Location is the source location of the call.
+}
+
+// ConfigAccess represents a read or write of a Nacos configuration by a service.
+type ConfigAccess struct {
+	DataId   string `json:"dataId"`             // DataId is the data ID of the configuration.
+	Group    string `json:"group"`              // Group is the group of the configuration.
+	Access   string `json:"access"`             // Access is "read" for GetConfig and ListenConfig, "write" for PublishConfig and DeleteConfig.
+	Function string `json:"function"`           // Function is the Nacos SDK function that was called.
+	Location string `json:"location,omitempty"` // Location is the source location of the call.
+}
+
 // Containers represents a collection of containers in a configuration.
 type Containers struct {
 	Env            []Env          `yaml:"env"`            // Environment variables for the containers.
@@ -11,6 +57,31 @@ type Containers struct {
 	LivenessProbe  LivenessProbe  `yaml:"livenessProbe"`  // Configuration for the liveness probe.
 }
 
+// Diagnostic represents a problem found while analysing the sources, such as a Go file with a syntax error.
+type Diagnostic struct {
+	Code     string `json:"code"`              // Code identifies the kind of problem, such as "SA001".
+	Severity string `json:"severity"`          // Severity is "error", "warning" or "info".
+	Location string `json:"location"`          // Location is the file, and the line if known, as "file:line".
+	Message  string `json:"message"`           // Message describes the problem.
+	Service  string `json:"service,omitempty"` // Service is the application the problem was found in, if any.
+}
+
+// DiscoveryCall represents a call of a service discovery wrapper.
+type DiscoveryCall struct {
+	ServiceName string `json:"serviceName"` // ServiceName is the name of the discovered service.
+	Method      string `json:"method"`      // Method is the Nacos SDK function the wrapper calls.
+	Location    string `json:"location"`    // Location is the source location of the call.
+	Group       string `json:"group"`       // Group is the Nacos group of the discovered service, empty for the default group.
+}
+
+// EdgeChange represents a call between two services that was added or removed between two revisions.
+type EdgeChange struct {
+	From  string   `json:"from"`  // From is the calling service.
+	To    string   `json:"to"`    // To is the called service or external target.
+	Ports []string `json:"ports"` // Ports are the ports of the call.
+	Kinds []string `json:"kinds"` // Kinds are the kinds of call between the two services.
+}
+
 // Env represents an environment variable.
 type Env struct {
 	Name  string `yaml:"name"`  // Name is the name of the environment variable.
@@ -22,12 +93,118 @@ type Exec struct {
 	Command []string `yaml:"command"`
 }
 
+// FlowEndpoint represents one end of a network flow observed at runtime.
+type FlowEndpoint struct {
+	IP        string `json:"ip,omitempty"`        // IP is the IP address of the endpoint.
+	Workload  string `json:"workload,omitempty"`  // Workload is the app label of the pod, empty if the endpoint is not a known workload.
+	Namespace string `json:"namespace,omitempty"` // Namespace is the namespace of the pod.
+	Pod       string `json:"pod,omitempty"`       // Pod is the name of the pod.
+}
+
+// FlowReport represents how the flows observed at runtime compare with the service graph.
+type FlowReport struct {
+	Confirmed            []ObservedFlow `json:"confirmed"`            // Confirmed are the observed flows that match a predicted edge.
+	ObservedNotPredicted []ObservedFlow `json:"observedNotPredicted"` // ObservedNotPredicted are the observed flows that match no predicted edge.
+	PredictedNotObserved []GraphEdge    `json:"predictedNotObserved"` // PredictedNotObserved are the predicted edges that no flow matches.
+}
+
+// GraphEdge represents a directed call between two nodes of the service graph.
+type GraphEdge struct {
+	From        string   `json:"from"`                // From is the ID of the calling node.
+	To          string   `json:"to"`                  // To is the ID of the called node.
+	Protocol    string   `json:"protocol"`            // Protocol is the transport protocol of the call.
+	Port        string   `json:"port"`                // Port is the destination port of the call.
+	Kind        string   `json:"kind"`                // Kind is the kind of call that produced the edge.
+	Method      string   `json:"method,omitempty"`    // Method is the HTTP method of the call, if known.
+	Path        string   `json:"path,omitempty"`      // Path is the HTTP path of the call, if known.
+	ServiceName string   `json:"serviceName"`         // ServiceName is the Nacos service name that was discovered.
+	Resolved    bool     `json:"resolved"`            // Resolved reports whether the target could be matched to a workload.
+	Locations   []string `json:"locations,omitempty"` // Locations are the source locations of the calls.
+}
+
+// GraphHop represents one step of a path through the service graph, with every edge between its two nodes.
+type GraphHop struct {
+	From  string      `json:"from"`  // From is the ID of the node the hop starts at.
+	To    string      `json:"to"`    // To is the ID of the node the hop ends at.
+	Edges []GraphEdge `json:"edges"` // Edges are the parallel edges that make up the hop.
+}
+
+// GraphNode represents a workload or external target in the service graph.
+type GraphNode struct {
+	ID        string `json:"id"`        // ID is the unique identifier of the node.
+	Label     string `json:"label"`     // Label is the display name of the node.
+	Namespace string `json:"namespace"` // Namespace is the Kubernetes namespace of the node.
+	Version   string `json:"version"`   // Version is the version of the workload.
+	External  bool   `json:"external"`  // External reports whether the node is outside the analysed workloads.
+}
+
+// GraphPath represents a path through the service graph.
+type GraphPath struct {
+	From string     `json:"from"` // From is the ID of the node the path starts at.
+	To   string     `json:"to"`   // To is the ID of the node the path ends at.
+	Hops []GraphHop `json:"hops"` // Hops are the steps of the path in calling order.
+}
+
+// IPBlock represents a CIDR range selected by a NetworkPolicy peer.
+type IPBlock struct {
+	CIDR   string   `yaml:"cidr" json:"cidr"`                         // CIDR is the selected range.
+	Except []string `yaml:"except,omitempty" json:"except,omitempty"` // Except are ranges excluded from CIDR.
+}
+
+// LabelSelector represents a Kubernetes label selector.
+type LabelSelector struct {
+	MatchLabels      map[string]string          `yaml:"matchLabels,omitempty" json:"matchLabels,omitempty"`           // MatchLabels are the labels a resource must carry.
+	MatchExpressions []LabelSelectorRequirement `yaml:"matchExpressions,omitempty" json:"matchExpressions,omitempty"` // MatchExpressions are further requirements on the labels.
+}
+
+// LabelSelectorRequirement represents a requirement of a label selector, such as "tier In (web, api)".
+type LabelSelectorRequirement struct {
+	Key      string   `yaml:"key" json:"key"`                           // Key is the label the requirement applies to.
+	Operator string   `yaml:"operator" json:"operator"`                 // Operator is In, NotIn, Exists or DoesNotExist.
+	Values   []string `yaml:"values,omitempty" json:"values,omitempty"` // Values are the values of In and NotIn.
+}
+
+// KubernetesNamespace represents a Kubernetes Namespace.
+type KubernetesNamespace struct {
+	Name   string            // Name is the name of the Namespace.
+	Labels map[string]string // Labels are the labels of the Namespace.
+	File   string            // File is the file the Namespace was read from.
+}
+
+// KubernetesService represents a Kubernetes Service.
+type KubernetesService struct {
+	Metadata Metadata              `yaml:"metadata"` // Metadata is the name and namespace of the Service.
+	Spec     KubernetesServiceSpec `yaml:"spec"`     // Spec is the specification of the Service.
+	File     string                `yaml:"-"`        // File is the file the Service was read from.
+}
+
+// KubernetesServiceSpec represents the specification of a Kubernetes Service.
+type KubernetesServiceSpec struct {
+	Selector map[string]string `yaml:"selector"` // Selector are the labels of the pods the Service sends traffic to.
+	Ports    []ServicePort     `yaml:"ports"`    // Ports are the ports of the Service.
+}
+
 // Labels represents the labels associated with a resource.
 type Labels struct {
 	App     string `yaml:"app"`     // App represents the application name.
 	Version string `yaml:"version"` // Version represents the version of the resource.
 }
 
+// Listener represents a server socket an application listens on.
+type Listener struct {
+	Kind     string `json:"kind"`     // Kind is the library that listens: "net", "http", "grpc", "gin" or "echo".
+	Address  string `json:"address"`  // Address is the listen address, a template if it is computed at runtime.
+	Port     string `json:"port"`     // Port is the port of the address, a template if it is computed at runtime.
+	Location string `json:"location"` // Location is the source location of the call.
+}
+
+// LintSettings represents the settings of the golangci-lint plugin.
+type LintSettings struct {
+	Root    string   `json:"root"`    // Root is the directory holding the sources of every workload, see the -root flag of nacoslint.
+	Enable  []string `json:"enable"`  // Enable are the rules to check, such as "NACOS001"; all rules if empty.
+	Disable []string `json:"disable"` // Disable are the rules not to check.
+}
+
 // Limits represents the resource limits for a particular task.
 type Limits struct {
 	Cpu    string `yaml:"cpu"`    // Cpu represents the CPU limit for the task.
@@ -44,15 +221,193 @@ type LivenessProbeExec struct {
 	Command []string `yaml:"command"`
 }
 
+// ManifestDiff represents the changes in allowed communication between two revisions.
+type ManifestDiff struct {
+	AddedEdges       []EdgeChange   `json:"addedEdges"`       // AddedEdges are the calls that only exist in the new revision.
+	RemovedEdges     []EdgeChange   `json:"removedEdges"`     // RemovedEdges are the calls that only exist in the old revision.
+	ChangedPorts     []PortChange   `json:"changedPorts"`     // ChangedPorts are the calls whose ports changed.
+	NewExternalHosts []string       `json:"newExternalHosts"` // NewExternalHosts are the external hosts that only the new revision calls.
+	PolicyChanges    []PolicyChange `json:"policyChanges"`    // PolicyChanges are the changes to the generated NetworkPolicies.
+}
+
 // Metadata represents the metadata associated with a resource.
 type Metadata struct {
-	Name   string `yaml:"name"`   // Name is the name of the resource.
-	Labels Labels `yaml:"labels"` // Labels are the labels associated with the resource.
+	Name      string `yaml:"name"`      // Name is the name of the resource.
+	Namespace string `yaml:"namespace"` // Namespace is the namespace of the resource.
+	Labels    Labels `yaml:"labels"`    // Labels are the labels associated with the resource.
+}
+
+// NacosAuthConfig represents the users, roles and permissions of the Nacos auth plugin, as created through its Open API.
+type NacosAuthConfig struct {
+	Users       []NacosUser       `json:"users"`       // Users are the users to create, one per application.
+	Roles       []NacosRole       `json:"roles"`       // Roles are the roles to bind to the users.
+	Permissions []NacosPermission `json:"permissions"` // Permissions are the permissions to grant to the roles.
+}
+
+// NacosClientConfig represents a Nacos client or server configuration found in the source.
+type NacosClientConfig struct {
+	Kind     string               `json:"kind"`     // Kind is "client" for a constant.ClientConfig, "server" for a constant.ServerConfig.
+	Location string               `json:"location"` // Location is the source location of the configuration.
+	Settings []NacosConfigSetting `json:"settings"` // Settings are the fields the source sets, in source order.
+}
+
+// NacosConfig represents a configuration held by a Nacos server.
+type NacosConfig struct {
+	DataId  string `json:"dataId"`           // DataId is the data ID of the configuration.
+	Group   string `json:"group"`            // Group is the group of the configuration.
+	Tenant  string `json:"tenant,omitempty"` // Tenant is the namespace of the configuration, empty for public.
+	Content string `json:"content"`          // Content is the content of the configuration.
+}
+
+// NacosConfigSetting represents a field of a Nacos client or server configuration set in the source.
+type NacosConfigSetting struct {
+	Name     string `json:"name"`     // Name is the name of the field, such as "Username"; fields of nested structs are joined with a dot, as in "TLSCfg.Enable".
+	Value    string `json:"value"`    // Value is the resolved value of the field, a template if it is computed at runtime.
+	Literal  bool   `json:"literal"`  // Literal reports whether the value is written in the source, rather than read at runtime.
+	Location string `json:"location"` // Location is the source location of the field.
+}
+
+// NacosInstance represents a service instance registered with a Nacos server.
+type NacosInstance struct {
+	ServiceName string            `json:"serviceName"`        // ServiceName is the name of the service, without its group.
+	GroupName   string            `json:"groupName"`          // GroupName is the group of the service.
+	NamespaceId string            `json:"namespaceId"`        // NamespaceId is the namespace of the service.
+	ClusterName string            `json:"clusterName"`        // ClusterName is the cluster of the instance.
+	Ip          string            `json:"ip"`                 // Ip is the IP address of the instance.
+	Port        uint64            `json:"port"`               // Port is the port of the instance.
+	Weight      float64           `json:"weight"`             // Weight is the load balancing weight of the instance.
+	Healthy     bool              `json:"healthy"`            // Healthy tells whether the instance is healthy.
+	Enabled     bool              `json:"enabled"`            // Enabled tells whether the instance accepts traffic.
+	Ephemeral   bool              `json:"ephemeral"`          // Ephemeral tells whether the instance is kept alive by heartbeats.
+	Metadata    map[string]string `json:"metadata,omitempty"` // Metadata is the metadata of the instance.
+}
+
+// NacosPermission represents a permission of a role on a Nacos resource.
+type NacosPermission struct {
+	Role      string   `json:"role"`                // Role is the role the permission is granted to.
+	Resource  string   `json:"resource"`            // Resource is the resource, as "namespace:group:naming/service" or "namespace:group:config/dataId".
+	Action    string   `json:"action"`              // Action is "r" to read the resource, "w" to write it, or "rw" for both.
+	Locations []string `json:"locations,omitempty"` // Locations are the source locations of the calls that need the permission, sorted; registrations have none.
+}
+
+// NacosRole represents the binding of a role to a Nacos user.
+type NacosRole struct {
+	Role     string `json:"role"`     // Role is the name of the role.
+	Username string `json:"username"` // Username is the user holding the role.
+}
+
+// NacosSnapshot represents the state of a Nacos server.
+type NacosSnapshot struct {
+	Instances []NacosInstance `json:"instances"` // Instances are the registered service instances.
+	Configs   []NacosConfig   `json:"configs"`   // Configs are the published configurations.
+}
+
+// NacosUser represents a user of the Nacos auth plugin.
+type NacosUser struct {
+	Username string `json:"username"` // Username is the name of the user, the name of its application.
+	Password string `json:"password"` // Password is the environment variable holding the password of the user, which is not generated.
+}
+
+// NamespaceScore represents how well the NetworkPolicies of a namespace match the service graph.
+type NamespaceScore struct {
+	Namespace string `json:"namespace"` // Namespace is the name of the namespace.
+	Passed    int    `json:"passed"`    // Passed is the number of checks that passed.
+	Checks    int    `json:"checks"`    // Checks is the number of checks made.
+	Score     int    `json:"score"`     // Score is the percentage of checks that passed.
+}
+
+// NetworkPolicy represents a Kubernetes NetworkPolicy.
+type NetworkPolicy struct {
+	ApiVersion string            `yaml:"apiVersion" json:"apiVersion"` // ApiVersion is the API version of the policy.
+	Kind       string            `yaml:"kind" json:"kind"`             // Kind is always NetworkPolicy.
+	Metadata   NetworkPolicyMeta `yaml:"metadata" json:"metadata"`     // Metadata is the name and namespace of the policy.
+	Spec       NetworkPolicySpec `yaml:"spec" json:"spec"`             // Spec is the specification of the policy.
+}
+
+// NetworkPolicyEgressRule represents an egress rule of a NetworkPolicy.
+type NetworkPolicyEgressRule struct {
+	To    []NetworkPolicyPeer `yaml:"to,omitempty" json:"to,omitempty"`       // To are the destinations the rule allows.
+	Ports []NetworkPolicyPort `yaml:"ports,omitempty" json:"ports,omitempty"` // Ports are the destination ports the rule allows.
+}
+
+// NetworkPolicyIngressRule represents an ingress rule of a NetworkPolicy.
+type NetworkPolicyIngressRule struct {
+	From  []NetworkPolicyPeer `yaml:"from,omitempty" json:"from,omitempty"`   // From are the sources the rule allows.
+	Ports []NetworkPolicyPort `yaml:"ports,omitempty" json:"ports,omitempty"` // Ports are the destination ports the rule allows.
+}
+
+// NetworkPolicyMeta represents the metadata of a NetworkPolicy.
+type NetworkPolicyMeta struct {
+	Name        string            `yaml:"name" json:"name"`                                   // Name is the name of the policy.
+	Namespace   string            `yaml:"namespace,omitempty" json:"namespace,omitempty"`     // Namespace is the namespace of the policy.
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"` // Annotations are notes on the policy, such as the rules that approximate values computed at runtime.
+}
+
+// NetworkPolicyPeer represents a source or destination selected by a NetworkPolicy rule.
+type NetworkPolicyPeer struct {
+	PodSelector       *LabelSelector `yaml:"podSelector,omitempty" json:"podSelector,omitempty"`             // PodSelector selects pods.
+	NamespaceSelector *LabelSelector `yaml:"namespaceSelector,omitempty" json:"namespaceSelector,omitempty"` // NamespaceSelector selects namespaces.
+	IPBlock           *IPBlock       `yaml:"ipBlock,omitempty" json:"ipBlock,omitempty"`                     // IPBlock selects an IP range.
+}
+
+// NetworkPolicyPort represents a port allowed by a NetworkPolicy rule.
+type NetworkPolicyPort struct {
+	Protocol string      `yaml:"protocol,omitempty" json:"protocol,omitempty"` // Protocol is the protocol of the port.
+	Port     interface{} `yaml:"port,omitempty" json:"port,omitempty"`         // Port is a port number or a named port.
+	EndPort  int         `yaml:"endPort,omitempty" json:"endPort,omitempty"`   // EndPort ends the range of ports starting at Port, if set.
+}
+
+// NetworkPolicySpec represents the specification of a NetworkPolicy.
+type NetworkPolicySpec struct {
+	PodSelector LabelSelector              `yaml:"podSelector" json:"podSelector"`                     // PodSelector selects the pods the policy applies to.
+	Ingress     []NetworkPolicyIngressRule `yaml:"ingress,omitempty" json:"ingress,omitempty"`         // Ingress are the allowed incoming connections.
+	Egress      []NetworkPolicyEgressRule  `yaml:"egress,omitempty" json:"egress,omitempty"`           // Egress are the allowed outgoing connections.
+	PolicyTypes []string                   `yaml:"policyTypes,omitempty" json:"policyTypes,omitempty"` // PolicyTypes are the directions the policy restricts.
+}
+
+// ObservedFlow represents the flows observed at runtime from one endpoint to another on one port.
+type ObservedFlow struct {
+	Source      FlowEndpoint `json:"source"`      // Source is the endpoint that opened the flows.
+	Destination FlowEndpoint `json:"destination"` // Destination is the endpoint the flows were opened to.
+	Port        string       `json:"port"`        // Port is the destination port.
+	Protocol    string       `json:"protocol"`    // Protocol is "tcp", "udp" or "sctp".
+	Count       int          `json:"count"`       // Count is the number of flows observed.
+}
+
+// PolicyChange represents the egress rules added to or removed from a generated NetworkPolicy.
+type PolicyChange struct {
+	Policy  string   `json:"policy"`  // Policy is the namespace and name of the policy.
+	Added   []string `json:"added"`   // Added are the egress rules only the new revision needs.
+	Removed []string `json:"removed"` // Removed are the egress rules only the old revision needed.
+}
+
+// PolicyFinding represents a NetworkPolicy rule that allows more than the service graph needs.
+type PolicyFinding struct {
+	Policy    string `json:"policy"`    // Policy is the namespace and name of the policy.
+	Direction string `json:"direction"` // Direction is "ingress" or "egress".
+	Rule      int    `json:"rule"`      // Rule is the index of the rule within its direction.
+	Reason    string `json:"reason"`    // Reason describes what the rule allows.
+}
+
+// PolicyVerdict represents whether a set of NetworkPolicies allows an edge of the service graph.
+type PolicyVerdict struct {
+	Edge    GraphEdge `json:"edge"`             // Edge is the evaluated edge.
+	Verdict string    `json:"verdict"`          // Verdict is "allowed", "blocked" or "unknown" if the policies cannot be evaluated statically for the edge.
+	Reason  string    `json:"reason,omitempty"` // Reason explains a blocked or unknown verdict.
+}
+
+// PortChange represents a call between two services whose ports changed between two revisions.
+type PortChange struct {
+	From     string   `json:"from"`     // From is the calling service.
+	To       string   `json:"to"`       // To is the called service or external target.
+	OldPorts []string `json:"oldPorts"` // OldPorts are the ports in the old revision.
+	NewPorts []string `json:"newPorts"` // NewPorts are the ports in the new revision.
 }
 
 // Ports represents the ports configuration for a container.
 type Ports struct {
-	ContainerPort int `yaml:"containerPort"`
+	ContainerPort int    `yaml:"containerPort"`
+	Name          string `yaml:"name"` // Name is the name of the port, which NetworkPolicies may refer to.
 }
 
 // ReadinessProbe represents the configuration for a readiness probe.
@@ -60,12 +415,23 @@ type ReadinessProbe struct {
 	Exec Exec `yaml:"exec"`
 }
 
+// RegistryFinding represents a difference between the registrations predicted by static analysis and those found at runtime.
+type RegistryFinding struct {
+	Kind          string   `json:"kind"`                    // Kind is "unpredicted", "missing", "ip-mismatch" or "port-mismatch".
+	ServiceName   string   `json:"serviceName"`             // ServiceName is the Nacos service name.
+	Application   string   `json:"application,omitempty"`   // Application is the workload predicted to register the service.
+	PredictedIP   string   `json:"predictedIp,omitempty"`   // PredictedIP is the IP address static analysis predicted.
+	PredictedPort string   `json:"predictedPort,omitempty"` // PredictedPort is the port static analysis predicted.
+	Runtime       []string `json:"runtime,omitempty"`       // Runtime are the "ip:port" addresses of the instances found at runtime.
+}
+
 // RegisterInstanceWrapper represents the registration information for a service.
 type RegisterInstanceWrapper struct {
 	Wrapper     string      // Wrapper is the name of the wrapper function.
 	ServiceName interface{} // ServiceName is the name of the service.
 	IP          interface{} // IP is the IP address of the service.
 	Port        interface{} // Port is the port number of the service.
+	GroupName   interface{} // GroupName is the group of the service, nil if the SDK default is used.
 }
 
 // Requests represents the resource requests for a container.
@@ -80,10 +446,27 @@ type Resources struct {
 	Limits   Limits   `yaml:"limits"`   // Limits specifies the resource limits for the component.
 }
 
+// SecurityFinding represents a weakness of the Nacos client configuration of a service.
+type SecurityFinding struct {
+	Rule        string `json:"rule"`        // Rule identifies the kind of weakness, such as "SEC001".
+	Severity    string `json:"severity"`    // Severity is "error", "warning" or "info".
+	Location    string `json:"location"`    // Location is the source location of the configuration or setting.
+	Message     string `json:"message"`     // Message describes the weakness.
+	Remediation string `json:"remediation"` // Remediation describes how to fix the weakness.
+}
+
 // ServiceDiscoveryWrapper represents information about a selection.
 type ServiceDiscoveryWrapper struct {
 	Wrapper     string      // Wrapper is the name of the wrapper.
-	ServiceName interface{} // ServiceName is the name of the service.
+	Method      string      // Method is the name of the Nacos SDK function called by the wrapper.
+	ServiceName interface{} // ServiceName is the name of the service, the names it is drawn from if the wrapper discovers several, or the parameter it is taken from.
+	GroupName   interface{} // GroupName is the group of the service, nil if the SDK default is used.
+}
+
+// ServiceGraph represents the dependency graph of the analysed services.
+type ServiceGraph struct {
+	Nodes []GraphNode `json:"nodes"` // Nodes are the workloads and external targets of the graph.
+	Edges []GraphEdge `json:"edges"` // Edges are the calls between the nodes.
 }
 
 // ServiceInfo represents information about a service.
@@ -91,6 +474,21 @@ type ServiceInfo struct {
 	Application string // Application represents the name of the application.
 	IP          string // IP represents the IP address of the service.
 	Port        string // Port represents the port number of the service.
+	Group       string // Group represents the Nacos group the service is registered in, empty for the default group.
+}
+
+// ServicePort represents a port of a Kubernetes Service.
+type ServicePort struct {
+	Name       string `yaml:"name"`       // Name is the name of the port.
+	Port       int    `yaml:"port"`       // Port is the port the Service exposes.
+	TargetPort string `yaml:"targetPort"` // TargetPort is the container port traffic is sent to, a number or the name of a container port; the Service port if empty.
+	Protocol   string `yaml:"protocol"`   // Protocol is TCP, UDP or SCTP; TCP if empty.
+}
+
+// SimulationReport represents the result of evaluating a set of NetworkPolicies against the service graph.
+type SimulationReport struct {
+	Verdicts   []PolicyVerdict `json:"verdicts"`   // Verdicts are the verdicts of every edge.
+	Permissive []PolicyFinding `json:"permissive"` // Permissive are the rules that allow more than the graph needs.
 }
 
 // Spec represents the specification of a resource.
@@ -100,17 +498,25 @@ type Spec struct {
 
 // TCPManifest represents the manifest for a TCP service.
 type TCPManifest struct {
-	Service  string       `json:"service"`  // Name of the service.
-	Version  string       `json:"version"`  // Version of the service.
-	Requests []TCPRequest `json:"requests"` // List of TCP requests.
+	Service  string            `json:"service"`            // Name of the service.
+	Version  string            `json:"version"`            // Version of the service.
+	Requests []TCPRequest      `json:"requests"`           // List of TCP requests.
+	Configs  []ConfigAccess    `json:"configs,omitempty"`  // List of Nacos configurations read or written.
+	Security []SecurityFinding `json:"security,omitempty"` // List of weaknesses of the Nacos client configuration.
 }
 
 // TCPRequest represents a TCP request.
 type TCPRequest struct {
-	Type string `json:"type"` // Type represents the type of the TCP request.
-	URL  string `json:"url"`  // URL represents the URL of the TCP request.
-	Name string `json:"name"` // Name represents the name of the TCP request.
-	Port string `json:"port"` // Port represents the port number of the TCP request.
+	Type        string `json:"type"`                  // Type represents the type of the TCP request.
+	URL         string `json:"url"`                   // URL represents the URL of the TCP request.
+	Name        string `json:"name"`                  // Name represents the name of the TCP request.
+	Port        string `json:"port"`                  // Port represents the port number of the TCP request.
+	ServiceName string `json:"serviceName,omitempty"` // ServiceName represents the Nacos service name that was discovered.
+	Kind        string `json:"kind,omitempty"`        // Kind represents the kind of call that produced the TCP request.
+	Method      string `json:"method,omitempty"`      // Method represents the HTTP method of the request, if known.
+	Path        string `json:"path,omitempty"`        // Path represents the HTTP path of the request, if known.
+	Location    string `json:"location,omitempty"`    // Location represents the source location of the call.
+	Source      string `json:"source,omitempty"`      // Source is "observed" for requests taken from runtime flow logs rather than the source code.
 }
 
 // Template represents a template object.
@@ -123,14 +529,8 @@ type Template struct {
 
 // TemplateMetadata represents the metadata of a template.
 type TemplateMetadata struct {
-	// Labels contains the labels associated with the template.
-	Labels TemplateMetadataLabels `yaml:"labels"`
-}
-
-// TemplateMetadataLabels represents the metadata labels for a template.
-type TemplateMetadataLabels struct {
-	// App represents the application name.
-	App string `yaml:"app"`
+	// Labels contains the labels associated with the template, such as "app", which the pods of the template carry.
+	Labels map[string]string `yaml:"labels"`
 }
 
 // TemplateSpec represents a template specification.
@@ -138,6 +538,13 @@ type TemplateSpec struct {
 	Containers []Containers `yaml:"containers"`
 }
 
+// UncoveredWorkload represents an analysed workload that no NetworkPolicy selects in some direction.
+type UncoveredWorkload struct {
+	Workload   string   `json:"workload"`   // Workload is the name of the workload.
+	Namespace  string   `json:"namespace"`  // Namespace is the namespace of the workload.
+	Directions []string `json:"directions"` // Directions are "ingress" and/or "egress", the directions no policy restricts.
+}
+
 // WrapperParams represents the parameters for a wrapper.
 type WrapperParams struct {
 	// Position represents the position at which the argument is passed into the wrapper.
@@ -0,0 +1,20 @@
+package util
+
+import t "static_analyser/pkg/types"
+
+func ContainsServiceInfo(infos []t.ServiceInfo, info t.ServiceInfo) bool {
+	// ContainsServiceInfo checks if a slice of ServiceInfo contains a specific instance.
+	//
+	// infos: The slice to search.
+	// info: The instance to search for in the slice.
+	//
+	// Returns:
+	// True if an instance with the same application, IP, port and group is found in the slice, false otherwise.
+
+	for _, existing := range infos {
+		if existing == info {
+			return true
+		}
+	}
+	return false
+}
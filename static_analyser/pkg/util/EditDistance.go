@@ -0,0 +1,30 @@
+package util
+
+func EditDistance(a string, b string) int {
+	// EditDistance computes the Levenshtein distance between two strings.
+	//
+	// a: The first string.
+	// b: The second string.
+	//
+	// Returns:
+	// The least number of single character insertions, deletions and substitutions turning a into b.
+
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"go/ast"
+	"path"
+	"strconv"
+)
+
+func ImportName(node ast.Node, importPath string, defaultName string) string {
+	// ImportName finds the name under which a file imports a package.
+	//
+	// node: The root node of the AST, normally an *ast.File.
+	// importPath: The import path of the package.
+	// defaultName: The name to assume if node is not a file.
+	//
+	// Returns:
+	// The local name of the package, or an empty string if the file does not import it.
+
+	file, ok := node.(*ast.File)
+	if !ok {
+		return defaultName
+	}
+	for _, spec := range file.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || p != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		if defaultName != "" {
+			return defaultName
+		}
+		return path.Base(p)
+	}
+	return ""
+}
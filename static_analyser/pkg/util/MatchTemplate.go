@@ -0,0 +1,81 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// placeholder matches a placeholder of a template produced by ResolveTemplate.
+var placeholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+func MatchTemplate(template string, value string) bool {
+	// MatchTemplate checks if a value is one a template can take, each placeholder standing for any string.
+	// A template that is nothing but placeholders and punctuation, such as "{name}" or "{prefix}-{id}", matches nothing,
+	// as it says too little about the value.
+	//
+	// template: The template, such as the service name "orders-{shard}" computed at runtime.
+	// value: The value, such as the name of a registered service.
+	//
+	// Returns:
+	// True if the value matches the pattern of the template, false otherwise.
+
+	literals := placeholder.ReplaceAllString(template, "")
+	if strings.IndexFunc(literals, func(r rune) bool { return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' }) < 0 {
+		return false
+	}
+	parts := placeholder.Split(template, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	matched, err := regexp.MatchString("^"+strings.Join(parts, ".*")+"$", value)
+	return err == nil && matched
+}
+
+func TemplateCIDR(template string) (string, bool) {
+	// TemplateCIDR approximates the IPv4 addresses a template can take with the CIDR block of the octets its literal
+	// prefix fixes, so "10.10.10.{n}" and "10.10.10.1{n}" give "10.10.10.0/24" and "10.{n}.0.1" gives "10.0.0.0/8".
+	// Every fixed part of the template must be numeric, so a host name such as "10.1.{x}.foo.example.com" gives none.
+	//
+	// template: The template of an IP address, such as config.InstanceIp + strconv.Itoa(n).
+	//
+	// Returns:
+	// The CIDR block and true if the template has placeholders, at most four octets of which every fixed part is
+	// numeric, and a prefix fixing at least one octet.
+	// An empty string and false otherwise.
+
+	// Placeholders are masked first, as they may hold dots of their own
+	parts := strings.Split(placeholder.ReplaceAllString(template, "{}"), ".")
+	if len(parts) > 4 || !strings.Contains(template, "{") {
+		return "", false
+	}
+	// octet tells whether a fixed part is a number from 0 to 255, written without leading zeros
+	octet := func(part string) bool {
+		n, err := strconv.Atoi(part)
+		return err == nil && n >= 0 && n <= 255 && strconv.Itoa(n) == part
+	}
+	octets := []string{}
+	prefix := true
+	for _, part := range parts {
+		prefix = prefix && !strings.Contains(part, "{}")
+		switch {
+		case prefix && !octet(part):
+			return "", false
+		case prefix:
+			octets = append(octets, part)
+		case !strings.Contains(part, "{}") && !octet(part):
+			return "", false
+		case strings.Trim(strings.ReplaceAll(part, "{}", ""), "0123456789") != "":
+			// Past the prefix, an octet is at most partly known
+			return "", false
+		}
+	}
+	if len(octets) == 0 {
+		return "", false
+	}
+	bits := 8 * len(octets)
+	for len(octets) < 4 {
+		octets = append(octets, "0")
+	}
+	return strings.Join(octets, ".") + "/" + strconv.Itoa(bits), true
+}
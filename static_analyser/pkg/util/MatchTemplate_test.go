@@ -0,0 +1,66 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestMatchTemplate(test *testing.T) {
+	// TestMatchTemplate checks which values templates computed at runtime match.
+	//
+	// test: The test.
+
+	cases := []struct {
+		template string
+		value    string
+		want     bool
+	}{
+		{"orders-shard-{i}", "orders-shard-1", true},
+		{"orders-shard-{i}", "orders-shard-", true},
+		{"orders-shard-{i}", "payments-shard-1", false},
+		{"{region}-orders", "eu-orders", true},
+		{"orders", "orders", true},
+		{"orders.{v}", "ordersXv1", false},
+		// Templates saying nothing about the value match nothing
+		{"{name}", "orders", false},
+		{"{prefix}-{id}", "orders-1", false},
+	}
+	for _, c := range cases {
+		if got := MatchTemplate(c.template, c.value); got != c.want {
+			test.Errorf("MatchTemplate(%q, %q) = %v, want %v", c.template, c.value, got, c.want)
+		}
+	}
+}
+
+func TestTemplateCIDR(test *testing.T) {
+	// TestTemplateCIDR checks the CIDR blocks approximating the addresses of templates.
+	//
+	// test: The test.
+
+	cases := []struct {
+		template string
+		cidr     string
+		ok       bool
+	}{
+		{"10.20.30.{n}", "10.20.30.0/24", true},
+		{"10.20.30.1{n}", "10.20.30.0/24", true},
+		{"10.20.{n}.5", "10.20.0.0/16", true},
+		{"10.{n}.0.1", "10.0.0.0/8", true},
+		{"1{n}.0.0.1", "", false},
+		{"{n}.0.0.1", "", false},
+		{"10.20.30.40", "", false},
+		{"10.256.{n}.1", "", false},
+		{"10.020.{n}.1", "", false},
+		{"10.20.30.40.{n}", "", false},
+		{"orders.{ns}.svc", "", false},
+		{"10.20.x{n}.1", "", false},
+		{"10.1.{x}.foo.example.com", "", false},
+		{"10.1.{x}.foo", "", false},
+		{"10.{n}.300", "", false},
+		{"10.{config.Subnet}.1", "10.0.0.0/8", true},
+	}
+	for _, c := range cases {
+		if cidr, ok := TemplateCIDR(c.template); cidr != c.cidr || ok != c.ok {
+			test.Errorf("TemplateCIDR(%q) = %q, %v, want %q, %v", c.template, cidr, ok, c.cidr, c.ok)
+		}
+	}
+}
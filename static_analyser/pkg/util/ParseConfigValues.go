@@ -0,0 +1,87 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+func ParseConfigValues(content string) map[string]string {
+	// ParseConfigValues parses the content of a Nacos configuration into flat key/value pairs.
+	// JSON and YAML documents are flattened, so {"db": {"host": "mysql"}} yields "db.host" and "host";
+	// anything else is read as properties, one "key=value" or "key: value" per line.
+	//
+	// content: The content of the configuration.
+	//
+	// Returns:
+	// A map where the keys are the configuration keys normalised by NormalizeConfigKey and the values are the
+	// configuration values. A nested key is also stored under its last segment unless that is already taken.
+
+	values := make(map[string]string)
+	leaves := make(map[string]string)
+
+	var flatten func(prefix string, value interface{})
+	flatten = func(prefix string, value interface{}) {
+		switch v := value.(type) {
+		case map[interface{}]interface{}:
+			for key, child := range v {
+				name := fmt.Sprint(key)
+				if prefix != "" {
+					name = prefix + "." + name
+				}
+				flatten(name, child)
+			}
+		case []interface{}:
+			for i, child := range v {
+				flatten(fmt.Sprintf("%s.%d", prefix, i), child)
+			}
+		case nil:
+		default:
+			values[NormalizeConfigKey(prefix)] = fmt.Sprint(v)
+			if i := strings.LastIndex(prefix, "."); i >= 0 {
+				leaves[NormalizeConfigKey(prefix[i+1:])] = fmt.Sprint(v)
+			}
+		}
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(content), &document); err == nil {
+		if _, ok := document.(map[interface{}]interface{}); ok {
+			flatten("", document)
+		}
+	}
+
+	if len(values) == 0 {
+		for _, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+				continue
+			}
+			i := strings.IndexAny(line, "=:")
+			if i < 0 {
+				continue
+			}
+			values[NormalizeConfigKey(strings.TrimSpace(line[:i]))] = strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	for key, value := range leaves {
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func NormalizeConfigKey(key string) string {
+	// NormalizeConfigKey normalises a configuration key so that "DB_HOST", "db-host", "db.host" and the
+	// struct field "DBHost" all compare equal.
+	//
+	// key: The key to normalise.
+	//
+	// Returns:
+	// The key in lower case without underscores, dashes and dots.
+
+	return strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+}
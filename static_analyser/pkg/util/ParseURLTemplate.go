@@ -0,0 +1,77 @@
+package util
+
+import (
+	"strings"
+)
+
+func ParseURLTemplate(template string) (string, string, string, string) {
+	// ParseURLTemplate splits a URL template produced by ResolveTemplate into its parts.
+	// Placeholders are kept, so "http://{instance.Ip}:{instance.Port}/user?id={id}" has host "{instance.Ip}",
+	// port "{instance.Port}" and path "/user".
+	//
+	// template: The URL template.
+	//
+	// Returns:
+	// The scheme, host, port and path of the URL. Parts that are not present are empty.
+
+	scheme := ""
+	rest := template
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme = rest[:i]
+		rest = rest[i+3:]
+	}
+
+	// The authority ends at the first slash, query or fragment outside of a placeholder
+	end := len(rest)
+	depth := 0
+	for i, c := range rest {
+		if c == '{' {
+			depth++
+		} else if c == '}' {
+			depth--
+		} else if depth == 0 && (c == '/' || c == '?' || c == '#') {
+			end = i
+			break
+		}
+	}
+	authority, path := rest[:end], rest[end:]
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		authority = authority[i+1:]
+	}
+
+	host, port := SplitHostPort(authority)
+	return scheme, host, port, path
+}
+
+func SplitHostPort(address string) (string, string) {
+	// SplitHostPort splits an address template into its host and port, leaving placeholders intact.
+	//
+	// address: An address such as "localhost:8083", "[::1]:80" or "{host}:{port}".
+	//
+	// Returns:
+	// The host and the port, which is empty if the address has none.
+
+	if strings.HasPrefix(address, "[") {
+		if i := strings.Index(address, "]"); i >= 0 {
+			host, rest := address[1:i], address[i+1:]
+			return host, strings.TrimPrefix(rest, ":")
+		}
+	}
+	depth := 0
+	for i := len(address) - 1; i >= 0; i-- {
+		switch address[i] {
+		case '}':
+			depth++
+		case '{':
+			depth--
+		case ':':
+			if depth == 0 {
+				return address[:i], address[i+1:]
+			}
+		}
+	}
+	return address, ""
+}
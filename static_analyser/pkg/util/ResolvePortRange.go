@@ -0,0 +1,137 @@
+package util
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func ResolvePortRange(expr ast.Expr, env map[string]ast.Expr, values map[string]string) string {
+	// ResolvePortRange resolves an integer-valued expression, such as the port of a registered instance, into the range
+	// of ports it can take. Integer literals, variables found in env, configuration values, sums, differences, products
+	// and remainders are evaluated; math/rand calls such as rand.Intn(n) range from 0 to n-1, and conversions to
+	// unsigned types are at least 0. Any other operand can take any value.
+	//
+	// expr: The expression to resolve.
+	// env: A map where the keys are local variable names and the values are the expressions last assigned to them. May be nil.
+	// values: The configuration values the service reads, see ParseConfigValues. May be nil.
+	//
+	// Returns:
+	// The port if the expression has a single value, such as "8080", or the range of ports it can take, such as
+	// "8080-8089", see ParsePortRange. If the range is not bounded within the valid ports, the expression is returned as
+	// a single "{expression}" placeholder.
+
+	visiting := make(map[string]bool)
+
+	// bounds returns the lowest and highest value of an expression, which are infinite if it is not bounded
+	var bounds func(expr ast.Expr) (float64, float64)
+	bounds = func(expr ast.Expr) (float64, float64) {
+		unknown := math.Inf(1)
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if e.Kind == token.INT {
+				if n, err := strconv.ParseInt(e.Value, 0, 64); err == nil {
+					return float64(n), float64(n)
+				}
+			}
+
+		case *ast.ParenExpr:
+			return bounds(e.X)
+
+		case *ast.Ident:
+			if value, ok := env[e.Name]; ok && !visiting[e.Name] {
+				visiting[e.Name] = true
+				defer delete(visiting, e.Name)
+				return bounds(value)
+			}
+
+		case *ast.BinaryExpr:
+			xlo, xhi := bounds(e.X)
+			ylo, yhi := bounds(e.Y)
+			switch e.Op {
+			case token.ADD:
+				return xlo + ylo, xhi + yhi
+			case token.SUB:
+				return xlo - yhi, xhi - ylo
+			case token.MUL:
+				// Only products of non-negative bounded operands are evaluated
+				if xlo >= 0 && ylo >= 0 && !math.IsInf(xhi, 0) && !math.IsInf(yhi, 0) {
+					return xlo * ylo, xhi * yhi
+				}
+			case token.REM:
+				if xlo >= 0 && ylo == yhi && ylo > 0 {
+					return 0, math.Min(xhi, ylo-1)
+				}
+			}
+
+		case *ast.CallExpr:
+			if ident, ok := e.Fun.(*ast.Ident); ok && len(e.Args) == 1 && isConversion(ident.Name) {
+				lo, hi := bounds(e.Args[0])
+				if strings.HasPrefix(ident.Name, "uint") {
+					lo = math.Max(lo, 0)
+				}
+				return lo, hi
+			}
+			if sel, ok := e.Fun.(*ast.SelectorExpr); ok && len(e.Args) == 1 {
+				if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "rand" {
+					switch sel.Sel.Name {
+					case "Intn", "Int31n", "Int63n", "IntN", "Int32N", "Int64N", "UintN", "Uint32N", "Uint64N", "N":
+						if _, hi := bounds(e.Args[0]); !math.IsInf(hi, 0) && hi > 0 {
+							return 0, hi - 1
+						}
+						return 0, unknown
+					}
+				}
+			}
+		}
+
+		// Configuration values and constants the template resolves to count as well
+		if n, err := strconv.ParseInt(SubstituteConfigValues(ResolveTemplate(expr, env), values), 10, 64); err == nil {
+			return float64(n), float64(n)
+		}
+		return -unknown, unknown
+	}
+
+	lo, hi := bounds(expr)
+	lo, hi = math.Max(lo, 1), math.Min(hi, 65535)
+	switch {
+	case math.IsNaN(lo) || math.IsNaN(hi) || lo > hi || (lo == 1 && hi == 65535):
+		// The parts of a template are concatenated, which would misrepresent a sum, and a number here is not a valid port
+		if template := SubstituteConfigValues(ResolveTemplate(expr, env), values); IsResolved(template) {
+			if _, err := strconv.Atoi(template); err != nil {
+				return template
+			}
+		}
+		return "{" + types.ExprString(expr) + "}"
+	case lo == hi:
+		return strconv.Itoa(int(lo))
+	}
+	return strconv.Itoa(int(lo)) + "-" + strconv.Itoa(int(hi))
+}
+
+func ParsePortRange(port string) (int, int, bool) {
+	// ParsePortRange parses a port as returned by ResolvePortRange.
+	//
+	// port: The port, such as "8080" or "8080-8089".
+	//
+	// Returns:
+	// The first and last port of the range, the same for a single port, and true if the port is a number or a range
+	// of valid ports. Zeros and false otherwise, such as for named ports, templates and inverted ranges.
+
+	first, last, isRange := strings.Cut(port, "-")
+	lo, err := strconv.Atoi(first)
+	if err != nil || lo < 1 || lo > 65535 {
+		return 0, 0, false
+	}
+	if !isRange {
+		return lo, lo, true
+	}
+	hi, err := strconv.Atoi(last)
+	if err != nil || hi < lo || hi > 65535 {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
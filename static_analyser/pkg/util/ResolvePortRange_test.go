@@ -0,0 +1,101 @@
+package util
+
+import (
+	"go/ast"
+	"go/parser"
+	"testing"
+)
+
+func TestResolvePortRange(test *testing.T) {
+	// TestResolvePortRange checks the ranges of ports integer expressions are resolved into, including ranges open at one
+	// end, ranges reaching past the valid ports and ranges outside of them.
+	//
+	// test: The test.
+
+	env := map[string]ast.Expr{
+		"base":   mustParseExpr(test, "8000"),
+		"offset": mustParseExpr(test, "rand.Intn(10)"),
+		"loop":   mustParseExpr(test, "loop + 1"),
+	}
+	values := map[string]string{NormalizeConfigKey("server.port"): "9090"}
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		// Single values
+		{`8080`, "8080"},
+		{`0x1F90`, "8080"},
+		{`base + 80`, "8080"},
+		{`uint64(8080)`, "8080"},
+		{`cfg.Server.Port`, "9090"},
+		// Bounded ranges
+		{`8080 + rand.Intn(10)`, "8080-8089"},
+		{`uint64(8080 + rand.Intn(10))`, "8080-8089"},
+		{`base + offset`, "8000-8009"},
+		{`cfg.Server.Port + rand.Intn(2)`, "9090-9091"},
+		{`8090 - rand.Intn(10)`, "8081-8090"},
+		{`8000 + rand.Intn(5)*10`, "8000-8040"},
+		{`8000 + uint16(n)%100`, "8000-8099"},
+		// Ranges open at one end are bounded by the valid ports
+		{`8080 + uint(n)`, "8080-65535"},
+		{`8080 - uint(n)`, "1-8080"},
+		{`8080 + rand.Intn(n)`, "8080-65535"},
+		// Ranges open at both ends, and values without bounds, are kept as placeholders
+		{`n`, "{n}"},
+		{`8080 + n`, "{8080 + n}"},
+		{`port()`, "{port()}"},
+		{`loop`, "{loop}"},
+		// Ranges reaching past the valid ports are cut at 65535
+		{`65530 + rand.Intn(20)`, "65530-65535"},
+		// Ranges entirely outside the valid ports hold no port
+		{`70000 + rand.Intn(10)`, "{70000 + rand.Intn(10)}"},
+		{`0`, "{0}"},
+		{`-10 + rand.Intn(5)`, "{-10 + rand.Intn(5)}"},
+		{`70000`, "{70000}"},
+	}
+	for _, c := range cases {
+		if got := ResolvePortRange(mustParseExpr(test, c.expr), env, values); got != c.want {
+			test.Errorf("ResolvePortRange(%s) = %q, want %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParsePortRange(test *testing.T) {
+	// TestParsePortRange checks that ports and ranges are parsed, and that inverted ranges, named ports and templates are not.
+	//
+	// test: The test.
+
+	cases := []struct {
+		port   string
+		lo, hi int
+		ok     bool
+	}{
+		{"8080", 8080, 8080, true},
+		{"8080-8089", 8080, 8089, true},
+		{"8080-8080", 8080, 8080, true},
+		{"8089-8080", 0, 0, false},
+		{"0", 0, 0, false},
+		{"65530-70000", 0, 0, false},
+		{"-8080", 0, 0, false},
+		{"http", 0, 0, false},
+		{"{port}", 0, 0, false},
+		{"8080-{n}", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, c := range cases {
+		if lo, hi, ok := ParsePortRange(c.port); lo != c.lo || hi != c.hi || ok != c.ok {
+			test.Errorf("ParsePortRange(%q) = %d, %d, %v, want %d, %d, %v", c.port, lo, hi, ok, c.lo, c.hi, c.ok)
+		}
+	}
+}
+
+func mustParseExpr(test *testing.T, expr string) ast.Expr {
+	// mustParseExpr parses a Go expression, failing the test if it is invalid.
+
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		test.Fatalf("invalid expression %q: %v", expr, err)
+	}
+	return parsed
+}
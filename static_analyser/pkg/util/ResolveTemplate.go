@@ -0,0 +1,141 @@
+package util
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+func ResolveTemplate(expr ast.Expr, env map[string]ast.Expr) string {
+	// ResolveTemplate resolves a string-valued expression into a template, as far as it can be resolved statically.
+	//
+	// expr: The expression to resolve.
+	// env: A map where the keys are local variable names and the values are the expressions last assigned to them.
+	//
+	// Returns:
+	// The value of the expression. String and integer literals, concatenations, fmt.Sprintf calls, strconv conversions
+	// and variables found in env are resolved; anything else is kept as a "{expression}" placeholder.
+
+	visiting := make(map[string]bool)
+
+	var resolve func(expr ast.Expr) string
+	resolve = func(expr ast.Expr) string {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if e.Kind == token.STRING {
+				if value, err := strconv.Unquote(e.Value); err == nil {
+					return value
+				}
+			}
+			return e.Value
+
+		case *ast.ParenExpr:
+			return resolve(e.X)
+
+		case *ast.Ident:
+			// Follow the variable to the expression last assigned to it, unless it refers to itself
+			if value, ok := env[e.Name]; ok && !visiting[e.Name] {
+				visiting[e.Name] = true
+				defer delete(visiting, e.Name)
+				return resolve(value)
+			}
+
+		case *ast.BinaryExpr:
+			if e.Op == token.ADD {
+				return resolve(e.X) + resolve(e.Y)
+			}
+
+		case *ast.CallExpr:
+			sel, ok := e.Fun.(*ast.SelectorExpr)
+			if !ok {
+				// Type conversions such as string(x) or uint64(x) keep the value
+				if ident, ok := e.Fun.(*ast.Ident); ok && len(e.Args) == 1 && isConversion(ident.Name) {
+					return resolve(e.Args[0])
+				}
+				break
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok {
+				break
+			}
+			switch pkg.Name + "." + sel.Sel.Name {
+			case "fmt.Sprintf":
+				if len(e.Args) > 0 {
+					return sprintf(resolve(e.Args[0]), e.Args[1:], resolve)
+				}
+			case "strconv.Itoa", "strconv.Quote":
+				if len(e.Args) == 1 {
+					return resolve(e.Args[0])
+				}
+			case "strconv.FormatInt", "strconv.FormatUint":
+				if len(e.Args) == 2 {
+					return resolve(e.Args[0])
+				}
+			case "net.JoinHostPort":
+				if len(e.Args) == 2 {
+					return resolve(e.Args[0]) + ":" + resolve(e.Args[1])
+				}
+			}
+		}
+		return "{" + types.ExprString(expr) + "}"
+	}
+
+	return resolve(expr)
+}
+
+func isConversion(name string) bool {
+	// isConversion reports whether a function name is a builtin conversion that keeps the textual value of its argument.
+
+	switch name {
+	case "string", "int", "int32", "int64", "uint", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+func sprintf(format string, args []ast.Expr, resolve func(ast.Expr) string) string {
+	// sprintf substitutes the resolved arguments of a fmt.Sprintf call into its format string.
+	//
+	// format: The resolved format string.
+	// args: The remaining arguments of the call.
+	// resolve: The function used to resolve each argument.
+	//
+	// Returns:
+	// The formatted template. Verbs without a matching argument are kept as they are.
+
+	var sb strings.Builder
+	next := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sb.WriteByte(format[i])
+			continue
+		}
+		// Skip flags, width and precision to find the verb
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.*", format[j]) >= 0 {
+			j++
+		}
+		if j >= len(format) {
+			sb.WriteString(format[i:])
+			break
+		}
+		if format[j] == '%' {
+			sb.WriteByte('%')
+		} else if next < len(args) {
+			sb.WriteString(resolve(args[next]))
+			next++
+		} else {
+			sb.WriteString(format[i : j+1])
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+func IsResolved(template string) bool {
+	// IsResolved reports whether a template produced by ResolveTemplate contains no placeholders.
+
+	return template != "" && !strings.ContainsAny(template, "{}")
+}
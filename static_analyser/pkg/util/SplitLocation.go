@@ -0,0 +1,22 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+func SplitLocation(location string) (string, int) {
+	// SplitLocation splits a "file:line" location.
+	//
+	// location: The location, with or without a line.
+	//
+	// Returns:
+	// The file, and the line or 0 if the location has none.
+
+	if i := strings.LastIndex(location, ":"); i >= 0 {
+		if line, err := strconv.Atoi(location[i+1:]); err == nil {
+			return location[:i], line
+		}
+	}
+	return location, 0
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// configPlaceholder matches a placeholder that reads a key or field of a variable, such as {dbConfig["DB_HOST"]} or {cfg.Database.Host}.
+var configPlaceholder = regexp.MustCompile(`\{[A-Za-z_][A-Za-z0-9_]*((?:\["[^"{}]*"\]|\.[A-Za-z_][A-Za-z0-9_]*)+)\}`)
+
+func SubstituteConfigValues(template string, values map[string]string) string {
+	// SubstituteConfigValues replaces the placeholders of a template produced by ResolveTemplate with configuration values.
+	// The variable a placeholder starts with is ignored; the keys and fields that follow are looked up in values,
+	// first as a whole and then by the last one only, so {dbConfig.DBHost} is replaced by the value of "DB_HOST".
+	//
+	// template: The template to substitute.
+	// values: The configuration values, as returned by ParseConfigValues. May be nil.
+	//
+	// Returns:
+	// The template with every placeholder that names a known configuration value replaced by that value.
+
+	if len(values) == 0 {
+		return template
+	}
+
+	return configPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		selectors := configPlaceholder.FindStringSubmatch(placeholder)[1]
+		parts := strings.FieldsFunc(selectors, func(r rune) bool { return r == '.' || r == '[' || r == ']' || r == '"' })
+		if value, ok := values[NormalizeConfigKey(strings.Join(parts, "."))]; ok {
+			return value
+		}
+		if value, ok := values[NormalizeConfigKey(parts[len(parts)-1])]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,18 @@
+package util
+
+func WorkloadLabel(labels map[string]string) string {
+	// WorkloadLabel finds the workload a set of Kubernetes labels belongs to.
+	//
+	// labels: The labels of a pod, or the selector of a service.
+	//
+	// Returns:
+	// The value of the app label, which names the workloads of the analysed applications, falling back to the
+	// app.kubernetes.io/name and k8s-app labels. An empty string if there is none of them.
+
+	for _, key := range []string{"app", "app.kubernetes.io/name", "k8s-app"} {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
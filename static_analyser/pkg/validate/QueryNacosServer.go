@@ -0,0 +1,126 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	t "static_analyser/pkg/types"
+	"strings"
+	"time"
+)
+
+func QueryNacosServer(server string, namespace string, group string, serviceNames []string, username string, password string) ([]t.NacosInstance, error) {
+	// QueryNacosServer lists the instances registered with a Nacos server through its v1 Open API.
+	//
+	// server: The base URL of the server, such as "http://localhost:8848"; "/nacos" is added if the URL has no path.
+	// namespace: The namespace to query.
+	// group: The group to query.
+	// serviceNames: Services to look up in addition to those the server lists, such as the statically predicted ones.
+	// username: The user to log in as, or empty if the server does not require authentication.
+	// password: The password of the user.
+	//
+	// Returns:
+	// The instances of every listed or given service.
+	// An error if the server could not be reached or answered with an error.
+
+	base, err := url.Parse(strings.TrimSuffix(server, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Nacos server URL '%s': %w", server, err)
+	}
+	if base.Path == "" {
+		base.Path = "/nacos"
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	request := func(method string, endpoint string, params url.Values, result interface{}) error {
+		target := *base
+		target.Path += endpoint
+		target.RawQuery = params.Encode()
+		req, err := http.NewRequest(method, target.String(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to query Nacos server: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read Nacos response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Nacos server answered %s to %s: %s", resp.Status, endpoint, strings.TrimSpace(string(body)))
+		}
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to parse Nacos response to %s: %w", endpoint, err)
+		}
+		return nil
+	}
+
+	params := url.Values{"namespaceId": {namespace}, "groupName": {group}}
+	if username != "" {
+		var login struct {
+			AccessToken string `json:"accessToken"`
+		}
+		if err := request(http.MethodPost, "/v1/auth/login", url.Values{"username": {username}, "password": {password}}, &login); err != nil {
+			return nil, err
+		}
+		params.Set("accessToken", login.AccessToken)
+	}
+
+	names := append([]string{}, serviceNames...)
+	for page := 1; ; page++ {
+		var list struct {
+			Count int      `json:"count"`
+			Doms  []string `json:"doms"`
+		}
+		pageParams := url.Values{"pageNo": {fmt.Sprint(page)}, "pageSize": {"500"}}
+		for key, value := range params {
+			pageParams[key] = value
+		}
+		if err := request(http.MethodGet, "/v1/ns/service/list", pageParams, &list); err != nil {
+			return nil, err
+		}
+		names = append(names, list.Doms...)
+		if len(list.Doms) == 0 || page*500 >= list.Count {
+			break
+		}
+	}
+
+	instances := []t.NacosInstance{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var list struct {
+			Hosts []struct {
+				Ip          string            `json:"ip"`
+				Port        uint64            `json:"port"`
+				Weight      float64           `json:"weight"`
+				Healthy     bool              `json:"healthy"`
+				Enabled     bool              `json:"enabled"`
+				Ephemeral   bool              `json:"ephemeral"`
+				ClusterName string            `json:"clusterName"`
+				Metadata    map[string]string `json:"metadata"`
+			} `json:"hosts"`
+		}
+		listParams := url.Values{"serviceName": {name}}
+		for key, value := range params {
+			listParams[key] = value
+		}
+		if err := request(http.MethodGet, "/v1/ns/instance/list", listParams, &list); err != nil {
+			return nil, err
+		}
+		for _, host := range list.Hosts {
+			instances = append(instances, t.NacosInstance{ServiceName: name, GroupName: group, NamespaceId: namespace, ClusterName: host.ClusterName, Ip: host.Ip, Port: host.Port,
+				Weight: host.Weight, Healthy: host.Healthy, Enabled: host.Enabled, Ephemeral: host.Ephemeral, Metadata: host.Metadata})
+		}
+	}
+	return instances, nil
+}
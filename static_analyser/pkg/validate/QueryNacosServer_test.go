@@ -0,0 +1,128 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestQueryNacosServer(test *testing.T) {
+	// TestQueryNacosServer checks that the instances of the listed services, over several pages, and of the given
+	// services are queried from the v1 Open API, logging in first if a user is given, and that errors of the server
+	// are reported.
+	//
+	// test: The test.
+
+	// services are the services registered with the server, more than fit in one page of the service list
+	services := map[string][]string{"orders": {"10.0.0.1"}, "payments": {"10.0.0.2", "10.0.0.3"}, "hidden": {"10.0.0.4"}}
+	listed := []string{"orders"}
+	for i := 0; i < 500; i++ {
+		listed = append(listed, fmt.Sprintf("empty-%d", i))
+	}
+	listed = append(listed, "payments")
+
+	newServer := func(token string) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/nacos/v1/auth/login", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.FormValue("username") != "nacos" || r.FormValue("password") != "secret" {
+				http.Error(w, "unknown user", http.StatusForbidden)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"accessToken": token})
+		})
+		authorized := func(w http.ResponseWriter, r *http.Request) bool {
+			if r.FormValue("accessToken") != token || r.FormValue("namespaceId") != "shop" || r.FormValue("groupName") != "DEFAULT_GROUP" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return false
+			}
+			return true
+		}
+		mux.HandleFunc("/nacos/v1/ns/service/list", func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(w, r) {
+				return
+			}
+			var page, size int
+			fmt.Sscan(r.FormValue("pageNo"), &page)
+			fmt.Sscan(r.FormValue("pageSize"), &size)
+			doms := []string{}
+			for i := (page - 1) * size; i < page*size && i < len(listed); i++ {
+				doms = append(doms, listed[i])
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(listed), "doms": doms})
+		})
+		mux.HandleFunc("/nacos/v1/ns/instance/list", func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(w, r) {
+				return
+			}
+			hosts := []map[string]interface{}{}
+			for _, ip := range services[r.FormValue("serviceName")] {
+				hosts = append(hosts, map[string]interface{}{"ip": ip, "port": 8080, "weight": 1, "healthy": true, "enabled": true})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"hosts": hosts})
+		})
+		return httptest.NewServer(mux)
+	}
+	instance := func(name string, ip string) t.NacosInstance {
+		return t.NacosInstance{ServiceName: name, GroupName: "DEFAULT_GROUP", NamespaceId: "shop", Ip: ip, Port: 8080, Weight: 1, Healthy: true, Enabled: true}
+	}
+
+	cases := []struct {
+		name         string
+		token        string
+		serviceNames []string
+		username     string
+		password     string
+		want         []t.NacosInstance
+		wantErr      bool
+	}{
+		{
+			name: "listed services",
+			want: []t.NacosInstance{instance("orders", "10.0.0.1"), instance("payments", "10.0.0.2"), instance("payments", "10.0.0.3")},
+		},
+		{
+			name:         "given services first",
+			serviceNames: []string{"hidden", "orders"},
+			want:         []t.NacosInstance{instance("hidden", "10.0.0.4"), instance("orders", "10.0.0.1"), instance("payments", "10.0.0.2"), instance("payments", "10.0.0.3")},
+		},
+		{
+			name:     "logged in",
+			token:    "token",
+			username: "nacos",
+			password: "secret",
+			want:     []t.NacosInstance{instance("orders", "10.0.0.1"), instance("payments", "10.0.0.2"), instance("payments", "10.0.0.3")},
+		},
+		{
+			name:    "authentication required",
+			token:   "token",
+			wantErr: true,
+		},
+		{
+			name:     "wrong password",
+			token:    "token",
+			username: "nacos",
+			password: "guess",
+			wantErr:  true,
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			server := newServer(c.token)
+			defer server.Close()
+			got, err := QueryNacosServer(server.URL, "shop", "DEFAULT_GROUP", c.serviceNames, c.username, c.password)
+			if (err != nil) != c.wantErr {
+				test.Fatalf("got error %v, want error %v", err, c.wantErr)
+			}
+			if !c.wantErr && !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got instances %+v, want %+v", got, c.want)
+			}
+		})
+	}
+
+	if _, err := QueryNacosServer("http://127.0.0.1:1", "shop", "DEFAULT_GROUP", nil, "", ""); err == nil {
+		test.Errorf("got no error querying a server that is not listening")
+	}
+}
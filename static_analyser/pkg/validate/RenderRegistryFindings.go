@@ -0,0 +1,48 @@
+package validate
+
+import (
+	"fmt"
+	t "static_analyser/pkg/types"
+	"strings"
+)
+
+func RenderRegistryFindings(findings []t.RegistryFinding) string {
+	// RenderRegistryFindings renders the findings of ValidateRegistrations as text.
+	//
+	// findings: The findings to render.
+	//
+	// Returns:
+	// One line per finding followed by a summary line, or a single line saying the registrations match.
+
+	if len(findings) == 0 {
+		return "Static registrations match the registry.\n"
+	}
+	var b strings.Builder
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		b.WriteString(describeFinding(finding) + "\n")
+		counts[finding.Kind]++
+	}
+	fmt.Fprintf(&b, "\n%d unpredicted, %d missing, %d IP mismatches, %d port mismatches\n",
+		counts["unpredicted"], counts["missing"], counts["ip-mismatch"], counts["port-mismatch"])
+	return b.String()
+}
+
+func describeFinding(finding t.RegistryFinding) string {
+	// describeFinding describes a finding in one line, such as
+	// "port-mismatch HelloService (helloservice): predicted port 8080, registered at 10.0.0.1:8081".
+
+	subject := finding.ServiceName
+	if finding.Application != "" {
+		subject += " (" + finding.Application + ")"
+	}
+	switch finding.Kind {
+	case "unpredicted":
+		return fmt.Sprintf("%s %s: registered at %v but not predicted by static analysis", finding.Kind, subject, finding.Runtime)
+	case "missing":
+		return fmt.Sprintf("%s %s: predicted at %s:%s but not registered", finding.Kind, subject, finding.PredictedIP, finding.PredictedPort)
+	case "ip-mismatch":
+		return fmt.Sprintf("%s %s: predicted IP %s, registered at %v", finding.Kind, subject, finding.PredictedIP, finding.Runtime)
+	}
+	return fmt.Sprintf("%s %s: predicted port %s, registered at %v", finding.Kind, subject, finding.PredictedPort, finding.Runtime)
+}
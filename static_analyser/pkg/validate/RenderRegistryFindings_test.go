@@ -0,0 +1,45 @@
+package validate
+
+import (
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestRenderRegistryFindings(test *testing.T) {
+	// TestRenderRegistryFindings checks the line rendered for each kind of finding and the summary counting them.
+	//
+	// test: The test.
+
+	cases := []struct {
+		name     string
+		findings []t.RegistryFinding
+		want     string
+	}{
+		{
+			name:     "no findings",
+			findings: []t.RegistryFinding{},
+			want:     "Static registrations match the registry.\n",
+		},
+		{
+			name: "every kind",
+			findings: []t.RegistryFinding{
+				{Kind: "ip-mismatch", ServiceName: "orders", Application: "orders", PredictedIP: "10.0.0.1", PredictedPort: "8080", Runtime: []string{"10.0.0.2:8080"}},
+				{Kind: "port-mismatch", ServiceName: "orders", Application: "orders", PredictedIP: "10.0.0.1", PredictedPort: "9090", Runtime: []string{"10.0.0.2:8080"}},
+				{Kind: "missing", ServiceName: "payments", Application: "billing", PredictedIP: "{ip}", PredictedPort: "8080", Runtime: []string{}},
+				{Kind: "unpredicted", ServiceName: "audit", Runtime: []string{"10.0.0.3:7070", "10.0.0.4:7070"}},
+			},
+			want: "ip-mismatch orders (orders): predicted IP 10.0.0.1, registered at [10.0.0.2:8080]\n" +
+				"port-mismatch orders (orders): predicted port 9090, registered at [10.0.0.2:8080]\n" +
+				"missing payments (billing): predicted at {ip}:8080 but not registered\n" +
+				"unpredicted audit: registered at [10.0.0.3:7070 10.0.0.4:7070] but not predicted by static analysis\n" +
+				"\n1 unpredicted, 1 missing, 1 IP mismatches, 1 port mismatches\n",
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := RenderRegistryFindings(c.findings); got != c.want {
+				test.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package validate
+
+import (
+	"net"
+	"sort"
+	t "static_analyser/pkg/types"
+	"static_analyser/pkg/util"
+	"strconv"
+)
+
+func ValidateRegistrations(serviceDirectory map[string][]t.ServiceInfo, instances []t.NacosInstance) []t.RegistryFinding {
+	// ValidateRegistrations compares the registrations predicted by static analysis with the instances found at runtime.
+	// Services are matched by name across groups and namespaces; a predicted name computed at runtime, such as
+	// "orders-shard-{shard}", matches every runtime name it can take, see util.MatchTemplate. A predicted port range
+	// matches the instances with a port in the range. A predicted IP or port that static analysis could not resolve to
+	// a literal address, number or range matches any runtime instance.
+	//
+	// serviceDirectory: A map where the keys are the names of the predicted services and the values are their predicted instances.
+	// instances: The instances found at runtime.
+	//
+	// Returns:
+	// The findings, sorted by service name, kind and predicted port: "unpredicted" for services registered at runtime that static analysis missed,
+	// "missing" for predicted services with no runtime instance, and "ip-mismatch" or "port-mismatch" for predicted services
+	// none of whose runtime instances has the predicted IP or port. Each predicted instance is checked on its own, so a service
+	// registering several ports gets a finding per port no runtime instance has.
+
+	runtime := make(map[string][]t.NacosInstance)
+	for _, instance := range instances {
+		runtime[instance.ServiceName] = append(runtime[instance.ServiceName], instance)
+	}
+
+	addresses := func(instances []t.NacosInstance) []string {
+		result := []string{}
+		for _, instance := range instances {
+			result = append(result, net.JoinHostPort(instance.Ip, strconv.FormatUint(instance.Port, 10)))
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	// predicted checks if a predicted name is, or can take, a runtime name
+	predicted := func(name string, runtimeName string) bool {
+		return name == runtimeName || (!util.IsResolved(name) && util.MatchTemplate(name, runtimeName))
+	}
+
+	findings := []t.RegistryFinding{}
+	for name, infos := range serviceDirectory {
+		found := []t.NacosInstance{}
+		for runtimeName, instances := range runtime {
+			if predicted(name, runtimeName) {
+				found = append(found, instances...)
+			}
+		}
+		for _, info := range infos {
+			finding := t.RegistryFinding{ServiceName: name, Application: info.Application, PredictedIP: info.IP, PredictedPort: info.Port, Runtime: addresses(found)}
+			if len(found) == 0 {
+				finding.Kind = "missing"
+				findings = append(findings, finding)
+				continue
+			}
+
+			if net.ParseIP(info.IP) != nil {
+				matched := false
+				for _, instance := range found {
+					matched = matched || net.ParseIP(instance.Ip).Equal(net.ParseIP(info.IP))
+				}
+				if !matched {
+					finding.Kind = "ip-mismatch"
+					findings = append(findings, finding)
+				}
+			}
+			if first, last, ok := util.ParsePortRange(info.Port); ok {
+				matched := false
+				for _, instance := range found {
+					matched = matched || (instance.Port >= uint64(first) && instance.Port <= uint64(last))
+				}
+				if !matched {
+					finding.Kind = "port-mismatch"
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+
+	for runtimeName, found := range runtime {
+		known := false
+		for name := range serviceDirectory {
+			known = known || predicted(name, runtimeName)
+		}
+		if !known {
+			findings = append(findings, t.RegistryFinding{Kind: "unpredicted", ServiceName: runtimeName, Runtime: addresses(found)})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].ServiceName != findings[j].ServiceName {
+			return findings[i].ServiceName < findings[j].ServiceName
+		}
+		if findings[i].Kind != findings[j].Kind {
+			return findings[i].Kind < findings[j].Kind
+		}
+		return findings[i].PredictedPort < findings[j].PredictedPort
+	})
+	return findings
+}
@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"reflect"
+	t "static_analyser/pkg/types"
+	"testing"
+)
+
+func TestValidateRegistrations(test *testing.T) {
+	// TestValidateRegistrations checks the findings of predicted registrations against runtime instances, including
+	// service names computed at runtime and port ranges.
+	//
+	// test: The test.
+
+	instance := func(name string, ip string, port uint64) t.NacosInstance {
+		return t.NacosInstance{ServiceName: name, Ip: ip, Port: port}
+	}
+
+	cases := []struct {
+		name      string
+		predicted map[string][]t.ServiceInfo
+		instances []t.NacosInstance
+		want      []t.RegistryFinding
+	}{
+		{
+			name:      "literal registration",
+			predicted: map[string][]t.ServiceInfo{"orders": {{Application: "orders", IP: "10.0.0.1", Port: "8080"}}},
+			instances: []t.NacosInstance{instance("orders", "10.0.0.1", 8080)},
+			want:      []t.RegistryFinding{},
+		},
+		{
+			name:      "other IP and port",
+			predicted: map[string][]t.ServiceInfo{"orders": {{Application: "orders", IP: "10.0.0.1", Port: "8080"}}},
+			instances: []t.NacosInstance{instance("orders", "10.0.0.2", 9090)},
+			want: []t.RegistryFinding{
+				{Kind: "ip-mismatch", ServiceName: "orders", Application: "orders", PredictedIP: "10.0.0.1", PredictedPort: "8080", Runtime: []string{"10.0.0.2:9090"}},
+				{Kind: "port-mismatch", ServiceName: "orders", Application: "orders", PredictedIP: "10.0.0.1", PredictedPort: "8080", Runtime: []string{"10.0.0.2:9090"}},
+			},
+		},
+		{
+			name:      "port in the range",
+			predicted: map[string][]t.ServiceInfo{"orders": {{Application: "orders", IP: "{ip}", Port: "8080-8089"}}},
+			instances: []t.NacosInstance{instance("orders", "10.0.0.1", 8089)},
+			want:      []t.RegistryFinding{},
+		},
+		{
+			name:      "port past the range",
+			predicted: map[string][]t.ServiceInfo{"orders": {{Application: "orders", IP: "{ip}", Port: "8080-8089"}}},
+			instances: []t.NacosInstance{instance("orders", "10.0.0.1", 8090)},
+			want: []t.RegistryFinding{
+				{Kind: "port-mismatch", ServiceName: "orders", Application: "orders", PredictedIP: "{ip}", PredictedPort: "8080-8089", Runtime: []string{"10.0.0.1:8090"}},
+			},
+		},
+		{
+			name:      "unresolved port",
+			predicted: map[string][]t.ServiceInfo{"orders": {{Application: "orders", IP: "{ip}", Port: "{port}"}}},
+			instances: []t.NacosInstance{instance("orders", "10.0.0.1", 1234)},
+			want:      []t.RegistryFinding{},
+		},
+		{
+			name:      "name computed at runtime",
+			predicted: map[string][]t.ServiceInfo{"orders-shard-{shard}": {{Application: "orders", IP: "{ip}", Port: "8080"}}},
+			instances: []t.NacosInstance{instance("orders-shard-1", "10.0.0.1", 8080), instance("orders-shard-2", "10.0.0.2", 8080)},
+			want:      []t.RegistryFinding{},
+		},
+		{
+			name:      "name computed at runtime on another port",
+			predicted: map[string][]t.ServiceInfo{"orders-shard-{shard}": {{Application: "orders", IP: "{ip}", Port: "8080"}}},
+			instances: []t.NacosInstance{instance("orders-shard-1", "10.0.0.1", 9090)},
+			want: []t.RegistryFinding{
+				{Kind: "port-mismatch", ServiceName: "orders-shard-{shard}", Application: "orders", PredictedIP: "{ip}", PredictedPort: "8080", Runtime: []string{"10.0.0.1:9090"}},
+			},
+		},
+		{
+			name:      "names the template cannot take",
+			predicted: map[string][]t.ServiceInfo{"orders-shard-{shard}": {{Application: "orders", IP: "{ip}", Port: "8080"}}},
+			instances: []t.NacosInstance{instance("payments", "10.0.0.1", 8080)},
+			want: []t.RegistryFinding{
+				{Kind: "missing", ServiceName: "orders-shard-{shard}", Application: "orders", PredictedIP: "{ip}", PredictedPort: "8080", Runtime: []string{}},
+				{Kind: "unpredicted", ServiceName: "payments", Runtime: []string{"10.0.0.1:8080"}},
+			},
+		},
+		{
+			name:      "template matching anything",
+			predicted: map[string][]t.ServiceInfo{"{name}": {{Application: "orders", IP: "{ip}", Port: "8080"}}},
+			instances: []t.NacosInstance{instance("orders", "10.0.0.1", 8080)},
+			want: []t.RegistryFinding{
+				{Kind: "unpredicted", ServiceName: "orders", Runtime: []string{"10.0.0.1:8080"}},
+				{Kind: "missing", ServiceName: "{name}", Application: "orders", PredictedIP: "{ip}", PredictedPort: "8080", Runtime: []string{}},
+			},
+		},
+	}
+	for _, c := range cases {
+		test.Run(c.name, func(test *testing.T) {
+			if got := ValidateRegistrations(c.predicted, c.instances); !reflect.DeepEqual(got, c.want) {
+				test.Errorf("got findings %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// discover returns the address of a healthy instance of a shard.
+func discover(client naming_client.INamingClient, name string) string {
+	instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName: name,
+	})
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", instance.Ip, instance.Port)
+}
+
+func main() {
+	var client naming_client.INamingClient
+	for shard := 0; shard < 4; shard++ {
+		discover(client, "orders-shard-"+strconv.Itoa(shard))
+		// Every rack runs a metrics exporter on the same host number
+		http.Get("http://10.1." + strconv.Itoa(rand.Intn(4)) + ".5:9000/metrics")
+	}
+}
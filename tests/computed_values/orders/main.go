@@ -0,0 +1,29 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// register registers a shard on an address and port picked at start, so that shards sharing a host do not collide.
+func register(client naming_client.INamingClient, name string) error {
+	param := vo.RegisterInstanceParam{
+		Ip:          "10.20.30." + strconv.Itoa(rand.Intn(200)),
+		Port:        uint64(8080 + rand.Intn(10)),
+		ServiceName: name,
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	}
+	_, err := client.RegisterInstance(param)
+	return err
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client, "orders-shard-1")
+}
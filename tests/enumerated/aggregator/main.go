@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// discover returns a healthy instance of a service.
+func discover(client naming_client.INamingClient, name string) {
+	client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName: name,
+	})
+}
+
+// fanOut discovers every service of a slice through the discover wrapper.
+func fanOut(client naming_client.INamingClient) {
+	for _, name := range []string{"orders", "payments"} {
+		discover(client, name)
+	}
+}
+
+// pick discovers the ledger and audit services of a region.
+func pick(client naming_client.INamingClient, region string) {
+	ledgers := map[string]string{"eu": "ledger", "us": "ledger-us"}
+	client.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: ledgers[region],
+		HealthyOnly: true,
+	})
+
+	var audit string
+	switch region {
+	case "eu":
+		audit = "audit"
+	default:
+		audit = "audit-us"
+	}
+	client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName: audit,
+	})
+}
+
+func main() {
+	var client naming_client.INamingClient
+	fanOut(client)
+	pick(client, "eu")
+}
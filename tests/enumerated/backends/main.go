@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// register registers every backend the aggregator fans out to.
+func register(client naming_client.INamingClient) {
+	for _, name := range []string{"orders", "payments", "ledger", "ledger-us", "audit", "audit-us"} {
+		client.RegisterInstance(vo.RegisterInstanceParam{
+			Ip:          "backends.default.svc",
+			Port:        8080,
+			ServiceName: name,
+			Weight:      10,
+			Enable:      true,
+			Healthy:     true,
+			Ephemeral:   true,
+		})
+	}
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"google.golang.org/grpc"
+)
+
+// hello calls the greeter through the nacos:// resolver, which discovers it in Nacos.
+func hello(ctx context.Context) error {
+	conn, err := grpc.Dial("nacos://greeter", grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := NewGreeterClient(conn)
+	_, err = client.SayHello(ctx, &HelloRequest{Name: "gateway"})
+	return err
+}
+
+// goodbye discovers an instance of the greeter and dials it.
+func goodbye(ctx context.Context, naming naming_client.INamingClient) error {
+	instance, err := naming.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{ServiceName: "greeter"})
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.DialContext(ctx, fmt.Sprintf("%s:%d", instance.Ip, instance.Port), grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := NewGreeterClient(conn)
+	_, err = client.SayGoodbye(ctx, &HelloRequest{Name: "gateway"})
+	return err
+}
+
+// audit dials the audit log over DNS without calling it yet, and checks the ledger over HTTP with a client of the
+// same name as the gRPC clients above.
+func audit() error {
+	conn, err := grpc.Dial("dns:///audit.default.svc:9000", grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := &http.Client{}
+	_, err = client.Get("http://ledger.default.svc:8080/health")
+	return err
+}
+
+func main() {
+	var naming naming_client.INamingClient
+	ctx := context.Background()
+	hello(ctx)
+	goodbye(ctx, naming)
+	audit()
+}
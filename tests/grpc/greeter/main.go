@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"google.golang.org/grpc"
+)
+
+// register registers the greeter's gRPC port.
+func register(client naming_client.INamingClient) {
+	client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "greeter.default.svc",
+		Port:        50051,
+		ServiceName: "greeter",
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	})
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client)
+
+	listener, err := net.Listen("tcp", ":50051")
+	if err != nil {
+		panic(err)
+	}
+	server := grpc.NewServer()
+	server.Serve(listener)
+}
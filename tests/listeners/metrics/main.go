@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// register registers the metrics port.
+func register(client naming_client.INamingClient) {
+	client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "metrics.default.svc",
+		Port:        9100,
+		ServiceName: "metrics",
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	})
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client)
+
+	// The address is read at runtime, so the ports of metrics are not checked against it
+	server := &http.Server{Addr: os.Getenv("METRICS_ADDR")}
+	server.ListenAndServe()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+	"google.golang.org/grpc"
+)
+
+// register registers a port of the web service.
+func register(client naming_client.INamingClient, name string, port uint64) {
+	client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "web.default.svc",
+		Port:        port,
+		ServiceName: name,
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	})
+}
+
+// serveGRPC serves gRPC on an address computed from its port.
+func serveGRPC() {
+	port := 9090
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		panic(err)
+	}
+	server := grpc.NewServer()
+	server.Serve(listener)
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client, "web", 8080)
+	register(client, "web-grpc", 9090)
+
+	go serveGRPC()
+
+	// The profiler listens on a port the Deployment does not declare
+	debug, err := net.Listen("tcp", "localhost:6060")
+	if err != nil {
+		panic(err)
+	}
+	go http.Serve(debug, nil)
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// register registers every service the catalog serves, each on its own port.
+func register(client naming_client.INamingClient) error {
+	if _, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "catalog.default.svc",
+		Port:        8080,
+		ServiceName: "alpha",
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	}); err != nil {
+		return err
+	}
+	if _, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "catalog.default.svc",
+		Port:        8081,
+		ServiceName: "beta",
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	}); err != nil {
+		return err
+	}
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "catalog.default.svc",
+		Port:        8082,
+		ServiceName: "gamma",
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	})
+	return err
+}
+
+func main() {
+	var client naming_client.INamingClient
+	register(client)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// connect discovers every service the storefront calls.
+func connect(client naming_client.INamingClient) error {
+	if _, err := client.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: "alpha",
+		HealthyOnly: true,
+	}); err != nil {
+		return err
+	}
+	if _, err := client.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: "beta",
+		HealthyOnly: true,
+	}); err != nil {
+		return err
+	}
+	_, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName: "gamma",
+	})
+	return err
+}
+
+func main() {
+	var client naming_client.INamingClient
+	connect(client)
+}
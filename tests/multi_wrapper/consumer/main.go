@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// discoverAll returns every healthy instance of a service.
+func discoverAll(client naming_client.INamingClient, name string) (string, error) {
+	instances, err := client.SelectInstances(vo.SelectInstancesParam{
+		ServiceName: name,
+		HealthyOnly: true,
+	})
+	if err != nil || len(instances) == 0 {
+		return "", fmt.Errorf("no instances of %s", name)
+	}
+	return fmt.Sprintf("http://%s:%d", instances[0].Ip, instances[0].Port), nil
+}
+
+// discoverOne returns one healthy instance of a service.
+func discoverOne(client naming_client.INamingClient, name string) (string, error) {
+	instance, err := client.SelectOneHealthyInstance(vo.SelectOneHealthInstanceParam{
+		ServiceName: name,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://%s:%d", instance.Ip, instance.Port), nil
+}
+
+func main() {
+	var client naming_client.INamingClient
+	url, _ := discoverAll(client, "provider")
+	http.Get(url + "/items")
+	admin, _ := discoverOne(client, "provider-admin")
+	http.Post(admin+"/reload", "application/json", nil)
+}
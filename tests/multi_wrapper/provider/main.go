@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+)
+
+// registerHTTP registers the HTTP port of a service.
+func registerHTTP(client naming_client.INamingClient, name string, ip string, port uint64) error {
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          ip,
+		Port:        port,
+		ServiceName: name,
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+	})
+	return err
+}
+
+// registerGRPC registers the gRPC port of a service under its own name.
+func registerGRPC(client naming_client.INamingClient, port uint64, name string) error {
+	_, err := client.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          "provider.default.svc",
+		Port:        port,
+		ServiceName: name,
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+		Metadata:    map[string]string{"protocol": "grpc"},
+	})
+	return err
+}
+
+func main() {
+	var client naming_client.INamingClient
+	registerHTTP(client, "provider", "provider.default.svc", 8080)
+	registerHTTP(client, "provider-admin", "provider.default.svc", 8081)
+	registerGRPC(client, 9090, "provider")
+}